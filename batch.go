@@ -0,0 +1,79 @@
+package slog
+
+import (
+	"time"
+
+	"darvaza.org/core"
+)
+
+// Entry describes a log entry emitted as part of a batch
+type Entry struct {
+	// Time is when the event happened. If zero, the time
+	// it's emitted is used
+	Time time.Time
+	// Fields are attached to the entry, on top of those
+	// of the logger
+	Fields Fields
+	// Message is the text of the entry
+	Message string
+	// Stack, if set, is the call stack attached to the entry
+	Stack core.Stack
+	// Level is the level of the entry. Defaults to Info
+	Level LogLevel
+}
+
+// Apply returns a logger derived from the given one with the
+// level, time, call stack and fields of the Entry attached,
+// ready to Print() its Message
+func (e *Entry) Apply(l Logger) Logger {
+	level := e.Level
+	if level <= UndefinedLevel {
+		level = Info
+	}
+
+	l, ok := l.WithLevel(level).WithEnabled()
+	if !ok {
+		return l
+	}
+
+	if !e.Time.IsZero() {
+		l = WithTime(l, e.Time)
+	}
+	if len(e.Stack) > 0 {
+		l = WithCallStack(l, e.Stack)
+	}
+	if len(e.Fields) > 0 {
+		l = l.WithFields(e.Fields)
+	}
+	return l
+}
+
+// BatchLogger is implemented by loggers that can emit many
+// entries at once, e.g. taking their locks or making their
+// writes once per batch instead of per entry
+type BatchLogger interface {
+	// LogBatch emits the entries in order
+	LogBatch(entries []Entry)
+}
+
+// LogBatch emits entries on the context of the given logger,
+// in order, using its LogBatch() if it's a BatchLogger or
+// one by one otherwise. Fatal and Panic entries terminate the
+// program as usual, possibly before the rest are emitted.
+func LogBatch(l Logger, entries []Entry) {
+	if bl, ok := l.(BatchLogger); ok {
+		bl.LogBatch(entries)
+		return
+	}
+	LogEach(l, entries)
+}
+
+// LogEach emits entries on the context of the given logger
+// one by one, for loggers not implementing BatchLogger or
+// whose LogBatch() can't handle a particular batch
+func LogEach(l Logger, entries []Entry) {
+	for i := range entries {
+		e := &entries[i]
+		e.Apply(l).Print(e.Message)
+	}
+}
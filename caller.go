@@ -0,0 +1,37 @@
+package slog
+
+import (
+	"darvaza.org/core"
+)
+
+// CallerLogger is implemented by loggers that can attach
+// the immediate caller to a log context without capturing
+// the full call stack
+type CallerLogger interface {
+	// WithCaller attaches the frame skip levels above the caller
+	WithCaller(skip int) Logger
+}
+
+// WithCaller attaches the frame skip levels above the caller to
+// a log context as a CallerFieldName field. It uses the logger's
+// own WithCaller() if implemented, or a string as rendered by
+// FormatCaller() otherwise.
+func WithCaller(l Logger, skip int) Logger {
+	if cl, ok := l.(CallerLogger); ok {
+		return cl.WithCaller(skip + 1)
+	}
+
+	if l.Enabled() {
+		if f := core.StackFrame(skip + 1); f != nil {
+			return l.WithField(CallerFieldName, FormatCaller(*f))
+		}
+	}
+	return l
+}
+
+// FormatCaller renders a frame as the full function name
+// followed by the file and line of the source code, the same
+// way frames are rendered by FormatStack()
+func FormatCaller(f core.Frame) string {
+	return NewFrame(f).String()
+}
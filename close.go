@@ -0,0 +1,100 @@
+package slog
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+)
+
+// maxChainDepth limits how deep Close() and Flush() walk
+// chains of loggers
+const maxChainDepth = 64
+
+// Flusher is implemented by loggers holding entries that can
+// be delivered on demand
+type Flusher interface {
+	// Flush waits until the entries held so far have been
+	// delivered, or the context is cancelled
+	Flush(ctx context.Context) error
+}
+
+// Unwrapper is implemented by loggers passing entries to
+// another logger
+type Unwrapper interface {
+	Unwrap() Logger
+}
+
+// MultiUnwrapper is implemented by loggers passing entries to
+// several other loggers
+type MultiUnwrapper interface {
+	Unwrap() []Logger
+}
+
+// Close walks a chain of loggers, from the given one towards
+// their backends, closing those implementing io.Closer so entries
+// held by a stage reach the next before it's closed.
+// Errors are joined.
+func Close(l Logger) error {
+	return walkChain(l, func(l Logger) error {
+		if c, ok := l.(io.Closer); ok {
+			return c.Close()
+		}
+		return nil
+	})
+}
+
+// Flush walks a chain of loggers, from the given one towards
+// their backends, flushing those implementing Flusher.
+// Errors are joined.
+func Flush(ctx context.Context, l Logger) error {
+	return walkChain(l, func(l Logger) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if f, ok := l.(Flusher); ok {
+			return f.Flush(ctx)
+		}
+		return nil
+	})
+}
+
+// walkChain calls fn on every logger of the chain once, as
+// loggers shared by several branches are only visited the first
+// time. Only pointers are tracked, other types being visited
+// every time they are found.
+func walkChain(l Logger, fn func(Logger) error) error {
+	var errs []error
+	var visit func(Logger, int)
+
+	seen := make(map[Logger]struct{})
+	visit = func(l Logger, depth int) {
+		if l == nil || depth > maxChainDepth {
+			return
+		}
+
+		if reflect.TypeOf(l).Kind() == reflect.Pointer {
+			if _, ok := seen[l]; ok {
+				return
+			}
+			seen[l] = struct{}{}
+		}
+
+		if err := fn(l); err != nil {
+			errs = append(errs, err)
+		}
+
+		switch x := l.(type) {
+		case Unwrapper:
+			visit(x.Unwrap(), depth+1)
+		case MultiUnwrapper:
+			for _, p := range x.Unwrap() {
+				visit(p, depth+1)
+			}
+		}
+	}
+
+	visit(l, 0)
+	return errors.Join(errs...)
+}
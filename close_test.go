@@ -0,0 +1,53 @@
+package slog_test
+
+import (
+	"testing"
+
+	"darvaza.org/slog"
+	slogtest "darvaza.org/slog/internal/testing"
+)
+
+// stage is a handler counting the calls made by the chain walkers
+type stage struct {
+	*slogtest.Recorder
+	parents []slog.Logger
+	closed  int
+}
+
+func (s *stage) Unwrap() []slog.Logger { return s.parents }
+
+func (s *stage) Close() error {
+	s.closed++
+	return nil
+}
+
+func (*stage) Stats() slog.Stats {
+	return slog.Stats{slog.DroppedStat: 1}
+}
+
+func newStage(parents ...slog.Logger) *stage {
+	return &stage{
+		Recorder: slogtest.NewRecorder(),
+		parents:  parents,
+	}
+}
+
+func TestWalkChainShared(t *testing.T) {
+	backend := newStage()
+	left := newStage(backend)
+	right := newStage(backend)
+	front := newStage(left, right, left)
+
+	if err := slog.Close(front); err != nil {
+		t.Fatal(err)
+	}
+	for i, s := range []*stage{front, left, right, backend} {
+		if s.closed != 1 {
+			t.Errorf("stage #%d closed %d times", i, s.closed)
+		}
+	}
+
+	if n := slog.CollectStats(front)[slog.DroppedStat]; n != 4 {
+		t.Errorf("expected 4 stages counted, got %d", n)
+	}
+}
@@ -18,3 +18,25 @@ func GetLogger(ctx context.Context) (Logger, bool) {
 }
 
 var ctxLoggerKey = core.NewContextKey[Logger]("logger")
+
+// ContextLogger is implemented by loggers that can give up
+// delivering an entry, instead of blocking, once the context
+// it's logged on behalf of is done
+type ContextLogger interface {
+	// WithContext records the context entries are
+	// logged on behalf of
+	WithContext(ctx context.Context) Logger
+}
+
+// WithContext records the context entries are logged on behalf
+// of, so blocking handlers, like asynchronous or network ones,
+// drop the entry instead of waiting once it's done. Loggers not
+// implementing ContextLogger are returned unchanged.
+//
+// Unlike WithLogger, the context isn't modified.
+func WithContext(ctx context.Context, l Logger) Logger {
+	if cl, ok := l.(ContextLogger); ok && ctx != nil {
+		return cl.WithContext(ctx)
+	}
+	return l
+}
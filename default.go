@@ -0,0 +1,133 @@
+package slog
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync/atomic"
+)
+
+var (
+	_ Logger      = (*fallbackLogger)(nil)
+	_ NamedLogger = (*fallbackLogger)(nil)
+)
+
+// defaultLogger holds the logger set using SetDefault
+var defaultLogger atomic.Pointer[loggerHolder]
+
+// loggerHolder allows storing any Logger atomically
+type loggerHolder struct {
+	Logger
+}
+
+// SetDefault atomically replaces the logger returned by Default and
+// used by the package-level Log functions. Passing nil restores the
+// fallback, which discards everything but Fatal and Panic entries.
+func SetDefault(l Logger) {
+	if l == nil {
+		defaultLogger.Store(nil)
+	} else {
+		defaultLogger.Store(&loggerHolder{l})
+	}
+}
+
+// Default returns the logger set using SetDefault, or a fallback that
+// discards everything but Fatal and Panic entries, which are written
+// using the standard library's log package before terminating or
+// panicking. Loggers derived from it aren't affected by later calls
+// to SetDefault.
+func Default() Logger {
+	if h := defaultLogger.Load(); h != nil {
+		return h.Logger
+	}
+	return &fallbackLoggers[UndefinedLevel]
+}
+
+// Log adds an entry of the given level to the Default logger,
+// with arguments handled in the manner of fmt.Print
+func Log(level LogLevel, args ...any) {
+	if l, ok := Default().WithLevel(level).WithEnabled(); ok {
+		l.Print(args...)
+	}
+}
+
+// Logf adds an entry of the given level to the Default logger,
+// with arguments handled in the manner of fmt.Printf
+func Logf(level LogLevel, format string, args ...any) {
+	if l, ok := Default().WithLevel(level).WithEnabled(); ok {
+		l.Printf(format, args...)
+	}
+}
+
+// fallbackLoggers are the singletons of the fallback Default
+// for each level
+var fallbackLoggers = [...]fallbackLogger{
+	UndefinedLevel: {UndefinedLevel},
+	Panic:          {Panic},
+	Fatal:          {Fatal},
+	Error:          {Error},
+	Warn:           {Warn},
+	Info:           {Info},
+	Debug:          {Debug},
+}
+
+// fallbackLogger is the Default until SetDefault is called
+type fallbackLogger struct {
+	level LogLevel
+}
+
+func (l *fallbackLogger) Enabled() bool {
+	return l.level == Panic || l.level == Fatal
+}
+
+func (l *fallbackLogger) WithEnabled() (Logger, bool) {
+	return l, l.Enabled()
+}
+
+func (l *fallbackLogger) Print(args ...any) {
+	if l.Enabled() {
+		l.terminate(fmt.Sprint(args...))
+	}
+}
+
+func (l *fallbackLogger) Println(args ...any) {
+	if l.Enabled() {
+		l.terminate(fmt.Sprintln(args...))
+	}
+}
+
+func (l *fallbackLogger) Printf(format string, args ...any) {
+	if l.Enabled() {
+		l.terminate(fmt.Sprintf(format, args...))
+	}
+}
+
+func (l *fallbackLogger) terminate(msg string) {
+	msg = strings.TrimSpace(msg)
+	_ = log.Output(3, msg)
+
+	if l.level == Panic {
+		panic(msg)
+	}
+	Exit(1)
+}
+
+func (*fallbackLogger) Debug() Logger { return &fallbackLoggers[Debug] }
+func (*fallbackLogger) Info() Logger  { return &fallbackLoggers[Info] }
+func (*fallbackLogger) Warn() Logger  { return &fallbackLoggers[Warn] }
+func (*fallbackLogger) Error() Logger { return &fallbackLoggers[Error] }
+func (*fallbackLogger) Fatal() Logger { return &fallbackLoggers[Fatal] }
+func (*fallbackLogger) Panic() Logger { return &fallbackLoggers[Panic] }
+
+func (*fallbackLogger) WithLevel(level LogLevel) Logger {
+	if level <= UndefinedLevel || int(level) >= len(fallbackLoggers) {
+		// fix your code
+		fallbackLoggers[Panic].Printf("slog: invalid log level %v", level)
+	}
+	return &fallbackLoggers[level]
+}
+
+func (l *fallbackLogger) WithStack(int) Logger             { return l }
+func (l *fallbackLogger) WithName(string) Logger           { return l }
+func (l *fallbackLogger) WithField(string, any) Logger     { return l }
+func (l *fallbackLogger) WithFields(map[string]any) Logger { return l }
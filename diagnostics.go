@@ -0,0 +1,59 @@
+package slog
+
+import (
+	"errors"
+	"log"
+	"sync"
+)
+
+// ErrDropped is reported when entries couldn't be delivered and
+// were discarded
+var ErrDropped = errors.New("entries dropped")
+
+var diagMu sync.RWMutex
+var diagLogger Logger
+
+// SetDiagnostics sets the logger handlers report their own problems
+// to, like failing to encode or deliver entries, when they haven't
+// been given a specific error handler. Passing nil restores the
+// default, the standard library's log package.
+//
+// The diagnostics logger shouldn't depend on the handlers it
+// watches, or their failures will feed themselves.
+func SetDiagnostics(l Logger) {
+	diagMu.Lock()
+	defer diagMu.Unlock()
+
+	diagLogger = l
+}
+
+// Diagnostics returns the logger set with SetDiagnostics,
+// or nil if using the default
+func Diagnostics() Logger {
+	diagMu.RLock()
+	defer diagMu.RUnlock()
+
+	return diagLogger
+}
+
+// ReportError is called by handlers to report a problem to the
+// diagnostics logger, at Warn level with the ComponentFieldName and
+// ErrorFieldName fields, or to the standard library's log package
+// if none has been set.
+func ReportError(component string, err error) {
+	if err == nil {
+		return
+	}
+
+	l := Diagnostics()
+	if l == nil {
+		log.Printf("slog: %s: %v", component, err)
+		return
+	}
+
+	l = l.Warn().WithField(ErrorFieldName, err)
+	if component != "" {
+		l = l.WithField(ComponentFieldName, component)
+	}
+	l.Print(err)
+}
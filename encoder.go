@@ -0,0 +1,90 @@
+package slog
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// ValueEncoder converts field values of a particular type into
+// a representation handlers know how to deal with
+type ValueEncoder func(any) any
+
+var (
+	encodersMu sync.Mutex
+	encoders   atomic.Pointer[map[reflect.Type]ValueEncoder]
+)
+
+// RegisterEncoder sets how field values of type T are converted
+// before handlers see them, replacing any previous encoder for it.
+// A nil function removes it.
+//
+// Encoders are matched against the dynamic type of the value, so
+// registering an interface type has no effect.
+//
+// e.g.
+//
+//	slog.RegisterEncoder(func(ip net.IP) any { return ip.String() })
+//	slog.RegisterEncoder(func(req *http.Request) any {
+//		return map[string]any{"method": req.Method, "url": req.URL.String()}
+//	})
+func RegisterEncoder[T any](fn func(T) any) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if fn == nil {
+		RegisterTypeEncoder(t, nil)
+		return
+	}
+
+	RegisterTypeEncoder(t, func(v any) any {
+		return fn(v.(T))
+	})
+}
+
+// RegisterTypeEncoder sets how field values of the given type are
+// converted before handlers see them, replacing any previous encoder
+// for it. A nil ValueEncoder removes it.
+func RegisterTypeEncoder(t reflect.Type, fn ValueEncoder) {
+	if t == nil {
+		return
+	}
+
+	encodersMu.Lock()
+	defer encodersMu.Unlock()
+
+	var m map[reflect.Type]ValueEncoder
+	if p := encoders.Load(); p != nil {
+		m = make(map[reflect.Type]ValueEncoder, len(*p)+1)
+		for k, v := range *p {
+			m[k] = v
+		}
+	} else {
+		m = make(map[reflect.Type]ValueEncoder, 1)
+	}
+
+	if fn == nil {
+		delete(m, t)
+	} else {
+		m[t] = fn
+	}
+
+	if len(m) == 0 {
+		encoders.Store(nil)
+	} else {
+		encoders.Store(&m)
+	}
+}
+
+// EncodeValue converts a field value using the encoder registered
+// for its type. Values without one are returned unchanged, as is
+// what the encoder returns.
+func EncodeValue(v any) any {
+	p := encoders.Load()
+	if p == nil || v == nil {
+		return v
+	}
+
+	if fn, ok := (*p)[reflect.TypeOf(v)]; ok {
+		return fn(v)
+	}
+	return v
+}
@@ -0,0 +1,90 @@
+// Package env assembles a slog.Logger from environment variables
+package env
+
+import (
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"darvaza.org/core"
+	"darvaza.org/slog"
+	"darvaza.org/slog/handlers/levels"
+	"darvaza.org/slog/handlers/stdlog"
+)
+
+// Environment variables used by New
+const (
+	// LevelVar sets the thresholds, either a single level or
+	// per-component rules like "proxy=debug,*=info"
+	LevelVar = "LOG_LEVEL"
+	// FormatVar chooses between "text" and "json"
+	FormatVar = "LOG_FORMAT"
+	// OutputVar chooses between "stderr", "stdout" and "file:path"
+	OutputVar = "LOG_OUTPUT"
+
+	// DefaultLevel is the threshold when LOG_LEVEL isn't set
+	DefaultLevel = slog.Info
+)
+
+// Formats supported by LOG_FORMAT
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// New creates a slog.Logger configured by LOG_LEVEL,
+// LOG_FORMAT and LOG_OUTPUT.
+func New() (slog.Logger, error) {
+	return NewWithGetenv(os.Getenv)
+}
+
+// NewWithGetenv creates a slog.Logger configured by the variables
+// returned by the given function, os.Getenv if nil.
+//
+// Entries are written to stderr as text with the standard log flags
+// unless told otherwise. Files are opened for appending and remain
+// open for the life of the process.
+func NewWithGetenv(getenv func(string) string) (slog.Logger, error) {
+	if getenv == nil {
+		getenv = os.Getenv
+	}
+
+	cfg, err := levels.Parse(getenv(LevelVar), DefaultLevel)
+	if err != nil {
+		return nil, err
+	}
+
+	w, err := openOutput(getenv(OutputVar))
+	if err != nil {
+		return nil, err
+	}
+
+	var l slog.Logger
+	switch format := strings.ToLower(getenv(FormatVar)); format {
+	case "", FormatText:
+		l = stdlog.New(log.New(w, "", log.LstdFlags))
+	case FormatJSON:
+		l = stdlog.NewJSON(log.New(w, "", 0))
+	default:
+		return nil, core.Wrapf(core.ErrInvalid, "%s: unsupported format %q", FormatVar, format)
+	}
+
+	return levels.New(l, levels.NewRegistry(cfg)), nil
+}
+
+func openOutput(output string) (io.Writer, error) {
+	switch output {
+	case "", "stderr":
+		return os.Stderr, nil
+	case "stdout":
+		return os.Stdout, nil
+	}
+
+	filename, ok := strings.CutPrefix(output, "file:")
+	if !ok || filename == "" {
+		return nil, core.Wrapf(core.ErrInvalid, "%s: unsupported output %q", OutputVar, output)
+	}
+
+	return os.OpenFile(filename, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+}
@@ -0,0 +1,98 @@
+package env
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"darvaza.org/core"
+	"darvaza.org/slog"
+)
+
+// newTestLogger creates a logger writing to a file, using the
+// given variables, and returns a function to read the lines written
+func newTestLogger(t *testing.T, vars map[string]string) (slog.Logger, func() []string) {
+	t.Helper()
+
+	filename := filepath.Join(t.TempDir(), "out.log")
+	vars[OutputVar] = "file:" + filename
+
+	l, err := NewWithGetenv(func(key string) string { return vars[key] })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return l, func() []string {
+		b, err := os.ReadFile(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return strings.Split(strings.TrimSpace(string(b)), "\n")
+	}
+}
+
+func TestText(t *testing.T) {
+	l, lines := newTestLogger(t, map[string]string{
+		LevelVar: "db=debug,*=warn",
+	})
+
+	l.Info().Print("hidden")
+	l.Warn().Print("shown")
+	slog.WithName(l, "db").Debug().Print("db debug")
+
+	got := lines()
+	want := []string{
+		"[WARN] shown",
+		`[DEBUG] db debug logger="db"`,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+	for i := range want {
+		// after the date and time
+		if !strings.HasSuffix(got[i], " "+want[i]) {
+			t.Errorf("#%d: expected %q, got %q", i, want[i], got[i])
+		}
+	}
+}
+
+func TestJSON(t *testing.T) {
+	l, lines := newTestLogger(t, map[string]string{
+		FormatVar: "JSON",
+	})
+
+	l.Debug().Print("hidden")
+	l.Info().WithField("n", 1).Print("shown")
+
+	got := lines()
+	if len(got) != 1 {
+		t.Fatalf("expected 1 line, got %q", got)
+	}
+
+	var out map[string]any
+	if err := json.Unmarshal([]byte(got[0]), &out); err != nil {
+		t.Fatal(err)
+	}
+	if out["message"] != "shown" || out["level"] != "info" || out["n"] != float64(1) {
+		t.Errorf("unexpected entry %v", out)
+	}
+}
+
+func TestInvalid(t *testing.T) {
+	for _, vars := range []map[string]string{
+		{LevelVar: "loud"},
+		{FormatVar: "xml"},
+		{OutputVar: "syslog"},
+		{OutputVar: "file:"},
+	} {
+		_, err := NewWithGetenv(func(key string) string { return vars[key] })
+		if err == nil {
+			t.Errorf("%v: expected error", vars)
+		} else if vars[LevelVar] == "" && !errors.Is(err, core.ErrInvalid) {
+			t.Errorf("%v: unexpected error %v", vars, err)
+		}
+	}
+}
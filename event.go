@@ -0,0 +1,167 @@
+package slog
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// EventTag is the struct tag naming the field an event struct
+// field is logged as, optionally followed by ",omitempty".
+// Fields tagged "-" are skipped.
+const EventTag = "slog"
+
+// EventNamer is implemented by events providing the message they
+// are logged with, instead of the name of their type
+type EventNamer interface {
+	EventName() string
+}
+
+// LogEvent logs a typed event, a struct or pointer to one, as an entry of
+// the given level. Its exported fields become fields of the entry, named
+// as their EventTag or, if untagged, as the struct field itself, and the
+// message is its EventName() or the name of its type.
+// Values other than structs are printed as the message.
+//
+// e.g.
+//
+//	type UserLogin struct {
+//		User   string `slog:"user"`
+//		Method string `slog:"method,omitempty"`
+//	}
+//
+//	slog.LogEvent(logger, slog.Info, UserLogin{User: "alice"})
+func LogEvent(l Logger, level LogLevel, ev any) {
+	l, ok := l.WithLevel(level).WithEnabled()
+	if !ok {
+		return
+	}
+
+	v := eventValue(ev)
+	if !v.IsValid() {
+		l.Print(ev)
+		return
+	}
+
+	enc := eventEncoderFor(v.Type())
+	if fields := enc.fields(v); len(fields) > 0 {
+		l = l.WithFields(fields)
+	}
+	l.Print(eventName(ev, enc))
+}
+
+// EventFields returns the fields LogEvent would attach for an event,
+// or nil if it isn't a struct or pointer to one
+func EventFields(ev any) Fields {
+	v := eventValue(ev)
+	if !v.IsValid() {
+		return nil
+	}
+	return eventEncoderFor(v.Type()).fields(v)
+}
+
+// eventValue returns the struct value of an event, or
+// an invalid reflect.Value if it isn't one
+func eventValue(ev any) reflect.Value {
+	v := reflect.ValueOf(ev)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return reflect.Value{}
+	}
+	return v
+}
+
+func eventName(ev any, enc *eventEncoder) string {
+	if n, ok := ev.(EventNamer); ok {
+		return n.EventName()
+	}
+	return enc.name
+}
+
+// eventEncoders caches the eventEncoder of each struct type
+var eventEncoders sync.Map
+
+// eventEncoder converts structs of a type into Fields
+type eventEncoder struct {
+	name  string
+	order []eventField
+}
+
+type eventField struct {
+	name      string
+	index     []int
+	omitEmpty bool
+}
+
+func eventEncoderFor(t reflect.Type) *eventEncoder {
+	if enc, ok := eventEncoders.Load(t); ok {
+		return enc.(*eventEncoder)
+	}
+
+	enc := &eventEncoder{name: t.Name()}
+	for _, f := range reflect.VisibleFields(t) {
+		if ef, ok := newEventField(f); ok {
+			enc.order = append(enc.order, ef)
+		}
+	}
+
+	actual, _ := eventEncoders.LoadOrStore(t, enc)
+	return actual.(*eventEncoder)
+}
+
+// newEventField describes how a struct field is logged, if at all
+func newEventField(f reflect.StructField) (eventField, bool) {
+	tag, hasTag := f.Tag.Lookup(EventTag)
+	switch {
+	case !f.IsExported(), tag == "-":
+		return eventField{}, false
+	case f.Anonymous && !hasTag && indirectKind(f.Type) == reflect.Struct:
+		// promoted by VisibleFields
+		return eventField{}, false
+	}
+
+	name, opts, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = f.Name
+	}
+
+	return eventField{
+		name:      name,
+		index:     f.Index,
+		omitEmpty: opts == "omitempty",
+	}, true
+}
+
+func indirectKind(t reflect.Type) reflect.Kind {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Kind()
+}
+
+func (enc *eventEncoder) fields(v reflect.Value) Fields {
+	if len(enc.order) == 0 {
+		return nil
+	}
+
+	out := make(Fields, len(enc.order))
+	for _, ef := range enc.order {
+		fv, err := v.FieldByIndexErr(ef.index)
+		switch {
+		case err != nil:
+			// through a nil embedded pointer
+			continue
+		case ef.omitEmpty && fv.IsZero():
+			continue
+		default:
+			out[ef.name] = fv.Interface()
+		}
+	}
+	return out
+}
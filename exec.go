@@ -0,0 +1,51 @@
+package slog
+
+import (
+	"errors"
+	"os/exec"
+)
+
+// CaptureOutput sets the Stdout and Stderr of a command, unless
+// already set, to Writers logging each line on the given logger with
+// a StreamFieldName field of "stdout" or "stderr". If no options are
+// given, lines holding JSON or logfmt are decoded and plain ones
+// checked for level prefixes and klog headers.
+//
+// The returned function logs the incomplete last lines, if any,
+// and is meant to be called after cmd.Wait().
+func CaptureOutput(cmd *exec.Cmd, l Logger, opts *WriterOptions) (flush func() error) {
+	if cmd == nil || l == nil {
+		return func() error { return nil }
+	}
+
+	if opts == nil {
+		opts = &WriterOptions{
+			ParseLevel:  true,
+			ParseKlog:   true,
+			ParseJSON:   true,
+			ParseLogfmt: true,
+		}
+	}
+
+	var writers []*Writer
+	if cmd.Stdout == nil {
+		w := NewWriter(l.WithField(StreamFieldName, "stdout"), opts)
+		cmd.Stdout = w
+		writers = append(writers, w)
+	}
+	if cmd.Stderr == nil {
+		w := NewWriter(l.WithField(StreamFieldName, "stderr"), opts)
+		cmd.Stderr = w
+		writers = append(writers, w)
+	}
+
+	return func() error {
+		var errs []error
+		for _, w := range writers {
+			if err := w.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errors.Join(errs...)
+	}
+}
@@ -0,0 +1,56 @@
+package slog
+
+import (
+	"os"
+	"sync"
+)
+
+var exitMu sync.RWMutex
+var exitFunc func(int)
+var exitCallbacks []func()
+
+// SetExitFunc sets the function handlers call to terminate the
+// program after logging a Fatal entry. Passing nil restores the
+// default, os.Exit. It's useful to intercept Fatal on tests.
+func SetExitFunc(fn func(code int)) {
+	exitMu.Lock()
+	defer exitMu.Unlock()
+
+	exitFunc = fn
+}
+
+// OnExit registers a function to be called by Exit
+// before terminating the program, e.g. to flush buffers.
+// Callbacks are called in reverse order of registration.
+func OnExit(fn func()) {
+	if fn == nil {
+		return
+	}
+
+	exitMu.Lock()
+	defer exitMu.Unlock()
+
+	exitCallbacks = append(exitCallbacks, fn)
+}
+
+// Exit is called by handlers after logging a Fatal entry. It
+// runs the callbacks registered with OnExit and then the function
+// set with SetExitFunc, or os.Exit if none.
+func Exit(code int) {
+	exitMu.RLock()
+	fn := exitFunc
+	callbacks := exitCallbacks
+	exitMu.RUnlock()
+
+	for i := len(callbacks) - 1; i >= 0; i-- {
+		callbacks[i]()
+	}
+
+	if fn == nil {
+		fn = os.Exit
+	}
+
+	// revive:disable:deep-exit
+	fn(code)
+	// revive:enable:deep-exit
+}
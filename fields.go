@@ -0,0 +1,138 @@
+package slog
+
+import "time"
+
+// Field is a key/value pair to be attached to a log context
+type Field struct {
+	Value any
+	Key   string
+}
+
+// Any creates a Field with a value of any type
+func Any(key string, value any) Field {
+	return Field{Key: key, Value: value}
+}
+
+// String creates a Field with a string value
+func String(key, value string) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Int creates a Field with an int value
+func Int(key string, value int) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Int64 creates a Field with an int64 value
+func Int64(key string, value int64) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Uint64 creates a Field with an uint64 value
+func Uint64(key string, value uint64) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Float64 creates a Field with a float64 value
+func Float64(key string, value float64) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Bool creates a Field with a bool value
+func Bool(key string, value bool) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Err creates a Field for an error using ErrorFieldName as key
+func Err(err error) Field {
+	return Field{Key: ErrorFieldName, Value: err}
+}
+
+// Duration creates a Field with a time.Duration value
+func Duration(key string, value time.Duration) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Time creates a Field with a time.Time value
+func Time(key string, value time.Time) Field {
+	return Field{Key: key, Value: value}
+}
+
+// With attaches the given fields to a log context. A single field
+// is attached using WithField(), avoiding the allocation of a map.
+// Fields with an empty key are ignored.
+func With(l Logger, fields ...Field) Logger {
+	switch len(fields) {
+	case 0:
+		return l
+	case 1:
+		if f := fields[0]; f.Key != "" {
+			return l.WithField(f.Key, f.Value)
+		}
+		return l
+	}
+
+	m := make(map[string]any, len(fields))
+	for _, f := range fields {
+		if f.Key != "" {
+			m[f.Key] = f.Value
+		}
+	}
+	return l.WithFields(m)
+}
+
+// Clone returns a copy of the Fields, or nil if empty
+func (f Fields) Clone() Fields {
+	if len(f) == 0 {
+		return nil
+	}
+
+	out := make(Fields, len(f))
+	for k, v := range f {
+		out[k] = v
+	}
+	return out
+}
+
+// Merge returns the Fields combined with the overrides, which
+// take precedence. Neither is modified and, when one of them is
+// empty, the other is returned as is, so the result should be
+// cloned before being modified.
+func (f Fields) Merge(overrides Fields) Fields {
+	switch {
+	case len(overrides) == 0:
+		return f
+	case len(f) == 0:
+		return overrides
+	}
+
+	out := make(Fields, len(f)+len(overrides))
+	for k, v := range f {
+		out[k] = v
+	}
+	for k, v := range overrides {
+		out[k] = v
+	}
+	return out
+}
+
+// Filtered returns the Fields with the given keys. The Fields
+// aren't modified and, when all of them are kept, they are
+// returned as is, so the result should be cloned before being
+// modified.
+func (f Fields) Filtered(keys ...string) Fields {
+	var out Fields
+	for _, k := range keys {
+		if v, ok := f[k]; ok {
+			if out == nil {
+				out = make(Fields, len(keys))
+			}
+			out[k] = v
+		}
+	}
+
+	if len(out) == len(f) {
+		return f
+	}
+	return out
+}
@@ -0,0 +1,267 @@
+// Package accesslog provides a slog.Logger writing HTTP access
+// entries as Common or Combined Log Format lines to an io.Writer
+package accesslog
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"darvaza.org/core"
+	"darvaza.org/slog"
+	"darvaza.org/slog/internal"
+	"darvaza.org/slog/middleware"
+)
+
+var (
+	_ slog.Logger       = (*Logger)(nil)
+	_ slog.CallerLogger = (*Logger)(nil)
+	_ slog.NamedLogger  = (*Logger)(nil)
+	_ slog.TimedLogger  = (*Logger)(nil)
+	_ slog.StackLogger  = (*Logger)(nil)
+)
+
+// output is the io.Writer shared by all the loggers derived
+// from the same New() call
+type output struct {
+	mu  sync.Mutex
+	w   io.Writer
+	cfg Config
+}
+
+func (out *output) write(b []byte) {
+	out.mu.Lock()
+	_, err := out.w.Write(b)
+	out.mu.Unlock()
+
+	if err != nil {
+		out.reportError(err)
+	}
+}
+
+func (out *output) reportError(err error) {
+	if fn := out.cfg.OnError; fn != nil {
+		fn(err)
+	} else {
+		slog.ReportError("accesslog", err)
+	}
+}
+
+// Logger is a slog.Logger writing access log lines to an io.Writer
+type Logger struct {
+	internal.Loglet
+
+	out *output
+}
+
+// Enabled tells this logger is enabled
+func (l *Logger) Enabled() bool {
+	return l != nil && l.out != nil
+}
+
+// WithEnabled passes the logger and if it's enabled
+func (l *Logger) WithEnabled() (slog.Logger, bool) {
+	return l, l.Enabled()
+}
+
+// Print adds a log entry with arguments handled in the manner of fmt.Print
+func (l *Logger) Print(args ...any) {
+	l.msg(fmt.Sprint(args...))
+}
+
+// Println adds a log entry with arguments handled in the manner of fmt.Println
+func (l *Logger) Println(args ...any) {
+	l.msg(fmt.Sprintln(args...))
+}
+
+// Printf adds a log entry with arguments handled in the manner of fmt.Printf
+func (l *Logger) Printf(format string, args ...any) {
+	l.msg(internal.Sprintf(format, args...))
+}
+
+func (l *Logger) msg(msg string) {
+	msg = strings.TrimSpace(msg)
+
+	if l.Enabled() {
+		l.write()
+	}
+
+	switch l.Level() {
+	case slog.Fatal:
+		slog.Exit(1)
+	case slog.Panic:
+		panic(core.NewPanicError(2, msg))
+	}
+}
+
+func (l *Logger) write() {
+	t := l.Time()
+	if t.IsZero() {
+		t = l.out.cfg.Clock.Now()
+	}
+
+	fields := l.AcquireFieldsMap()
+	defer internal.ReleaseFieldsMap(fields)
+
+	if line, ok := l.out.cfg.Format.Render(t, fields); ok {
+		l.out.write([]byte(line + "\n"))
+	}
+}
+
+// Debug returns a new logger set to add entries as level Debug
+func (l *Logger) Debug() slog.Logger {
+	return l.WithLevel(slog.Debug)
+}
+
+// Info returns a new logger set to add entries as level Info
+func (l *Logger) Info() slog.Logger {
+	return l.WithLevel(slog.Info)
+}
+
+// Warn returns a new logger set to add entries as level Warn
+func (l *Logger) Warn() slog.Logger {
+	return l.WithLevel(slog.Warn)
+}
+
+// Error returns a new logger set to add entries as level Error
+func (l *Logger) Error() slog.Logger {
+	return l.WithLevel(slog.Error)
+}
+
+// Fatal returns a new logger set to add entries as level Fatal
+func (l *Logger) Fatal() slog.Logger {
+	return l.WithLevel(slog.Fatal)
+}
+
+// Panic returns a new logger set to add entries as level Panic
+func (l *Logger) Panic() slog.Logger {
+	return l.WithLevel(slog.Panic)
+}
+
+// WithLevel returns a new logger set to add entries to the specified level
+func (l *Logger) WithLevel(level slog.LogLevel) slog.Logger {
+	if level <= slog.UndefinedLevel {
+		// fix your code
+		l.Panic().WithStack(1).Printf("slog: invalid log level %v", level)
+	} else if level == l.Level() {
+		return l
+	}
+
+	return &Logger{
+		Loglet: l.Loglet.WithLevel(level),
+		out:    l.out,
+	}
+}
+
+// WithStack attaches a call stack to a new logger
+func (l *Logger) WithStack(skip int) slog.Logger {
+	return &Logger{
+		Loglet: l.Loglet.WithStack(skip + 1),
+		out:    l.out,
+	}
+}
+
+// WithCallStack attaches a given call stack to a new logger
+func (l *Logger) WithCallStack(st core.Stack) slog.Logger {
+	return &Logger{
+		Loglet: l.Loglet.WithCallStack(st),
+		out:    l.out,
+	}
+}
+
+// WithTime records when the event happened on a new logger
+func (l *Logger) WithTime(t time.Time) slog.Logger {
+	return &Logger{
+		Loglet: l.Loglet.WithTime(t),
+		out:    l.out,
+	}
+}
+
+// WithCaller attaches the immediate caller to a new logger
+func (l *Logger) WithCaller(skip int) slog.Logger {
+	return &Logger{
+		Loglet: l.Loglet.WithCaller(skip + 1),
+		out:    l.out,
+	}
+}
+
+// WithName appends a component to the dot-separated name of a
+// new logger, written as slog.NameFieldName field
+func (l *Logger) WithName(name string) slog.Logger {
+	if name != "" {
+		return &Logger{
+			Loglet: l.Loglet.WithName(name),
+			out:    l.out,
+		}
+	}
+	return l
+}
+
+// WithField returns a new logger with a field attached
+func (l *Logger) WithField(label string, value any) slog.Logger {
+	if label != "" {
+		return &Logger{
+			Loglet: l.Loglet.WithField(label, value),
+			out:    l.out,
+		}
+	}
+	return l
+}
+
+// WithFields returns a new logger with a set of fields attached
+func (l *Logger) WithFields(fields map[string]any) slog.Logger {
+	delete(fields, "")
+
+	if len(fields) > 0 {
+		return &Logger{
+			Loglet: l.Loglet.WithFields(fields),
+			out:    l.out,
+		}
+	}
+	return l
+}
+
+// New creates a slog.Logger writing a line to the given io.Writer
+// for each entry carrying the method and status fields, as logged
+// by middleware.HTTPHandler. Other entries, and the message, are
+// ignored. Writes are serialised. If no io.Writer is given, the
+// logger is disabled.
+func New(w io.Writer, cfg *Config) slog.Logger {
+	if w == nil {
+		return &Logger{}
+	}
+
+	out := &output{w: w}
+	if cfg != nil {
+		out.cfg = *cfg
+	}
+	out.cfg.SetDefaults()
+
+	return &Logger{out: out}
+}
+
+// Clock is the source of time used by the Logger
+type Clock = internal.Clock
+
+// Config describes the lines written by the Logger
+type Config struct {
+	// Clock is the source of time of entries that don't
+	// specify one. Defaults to the system clock
+	Clock Clock
+	// OnError is called when writing a line fails,
+	// instead of reporting it using slog.ReportError()
+	OnError func(error)
+
+	// Format is the layout of the lines.
+	// Defaults to middleware.CommonLogFormat
+	Format middleware.AccessLogFormat
+}
+
+// SetDefaults uses the system clock if no Clock is given
+func (cfg *Config) SetDefaults() {
+	if cfg.Clock == nil {
+		cfg.Clock = internal.SystemClock
+	}
+}
@@ -0,0 +1,84 @@
+package accesslog
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"darvaza.org/slog"
+	slogtest "darvaza.org/slog/internal/testing"
+	"darvaza.org/slog/middleware"
+)
+
+var testTime = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+func TestHTTPHandler(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(&buf, &Config{
+		Clock:  slogtest.NewManualClock(testTime),
+		Format: middleware.CombinedLogFormat,
+	})
+
+	h := middleware.NewHTTPHandler(l, http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		_, _ = rw.Write([]byte("hello"))
+	}))
+	h.AccessFields = true
+	h.LogStart = true
+
+	req := httptest.NewRequest(http.MethodGet, "/hello", nil)
+	req.Header.Set("User-Agent", "test")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	// only the completion has a status
+	want := `192.0.2.1 - - [02/Jan/2024:03:04:05 +0000] "GET /hello HTTP/1.1" 200 5 "-" "test"` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestEntries(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(&buf, nil)
+
+	l.Info().Print("ignored")
+	slog.WithTime(l.Info(), testTime).WithFields(slog.Fields{
+		middleware.MethodFieldName: "POST",
+		middleware.StatusFieldName: 201,
+	}).Print("ignored too")
+
+	want := `- - - [02/Jan/2024:03:04:05 +0000] "POST" 201 -` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("failed")
+}
+
+func TestWriteError(t *testing.T) {
+	var reported []error
+	l := New(failingWriter{}, &Config{
+		OnError: func(err error) { reported = append(reported, err) },
+	})
+
+	l.WithFields(slog.Fields{
+		middleware.MethodFieldName: "GET",
+		middleware.StatusFieldName: 200,
+	}).Print()
+
+	if len(reported) != 1 {
+		t.Errorf("expected 1 error reported, got %v", reported)
+	}
+}
+
+func TestDisabled(t *testing.T) {
+	if New(nil, nil).Enabled() {
+		t.Error("enabled without io.Writer")
+	}
+}
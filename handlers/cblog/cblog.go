@@ -2,8 +2,13 @@
 package cblog
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"darvaza.org/core"
 	"darvaza.org/slog"
@@ -13,18 +18,47 @@ import (
 const (
 	// DefaultOutputBufferSize is the default size of the channel buffer used for logging.
 	DefaultOutputBufferSize = 1024
+
+	// DefaultShutdownTimeout is the maximum time Close() will wait
+	// for the pending entries to be delivered.
+	DefaultShutdownTimeout = 5 * time.Second
+
+	// flushInterval is how often Flush() checks if the pending
+	// entries have been delivered.
+	flushInterval = time.Millisecond
 )
 
 var (
-	_ slog.Logger = (*Logger)(nil)
+	_ slog.Logger       = (*Logger)(nil)
+	_ slog.CallerLogger = (*Logger)(nil)
+	_ slog.NamedLogger  = (*Logger)(nil)
+	_ slog.KVLogger     = (*Logger)(nil)
+	_ slog.TimedLogger  = (*Logger)(nil)
+	_ slog.StackLogger  = (*Logger)(nil)
+
+	_ slog.ContextLogger = (*Logger)(nil)
+	_ slog.BatchLogger   = (*Logger)(nil)
+	_ slog.HealthChecker = (*Logger)(nil)
+	_ slog.StatsReporter = (*Logger)(nil)
 )
 
+// ErrClosed is reported by Health() once the logger has been closed
+var ErrClosed = errors.New("cblog: logger closed")
+
 // LogMsg represents one structured log entry
 type LogMsg struct {
+	Time    time.Time
 	Message string
 	Level   slog.LogLevel
 	Fields  map[string]any
 	Stack   core.Stack
+	Seq     uint64
+}
+
+// StackFrames returns the structured representation of
+// the call stack attached to the entry, if any
+func (m LogMsg) StackFrames() []slog.Frame {
+	return slog.StackFrames(m.Stack)
 }
 
 // Logger is a slog.Logger using a channel as backend
@@ -35,7 +69,25 @@ type Logger struct {
 }
 
 type cblog struct {
-	ch chan LogMsg
+	mu      sync.RWMutex
+	wg      sync.WaitGroup
+	ch      chan LogMsg
+	done    chan struct{}
+	pending atomic.Int64
+	dropped atomic.Uint64
+	seq     atomic.Uint64
+	clock   Clock
+
+	onOverflow func(LogMsg)
+	onError    func(LogMsg, error)
+	health     func() error
+	onFlush    func(context.Context) error
+	stats      func() slog.Stats
+	overflow   OverflowPolicy
+
+	once   sync.Once
+	closed bool
+	worker bool
 
 	Logger
 }
@@ -62,30 +114,158 @@ func (l *Logger) Println(args ...any) {
 
 // Printf adds a log entry with arguments handled in the manner of fmt.Printf
 func (l *Logger) Printf(format string, args ...any) {
-	l.sendMsg(fmt.Sprintf(format, args...))
+	l.sendMsg(internal.Sprintf(format, args...))
 }
 
 func (l *Logger) sendMsg(msg string) {
-	var m map[string]any
+	m := LogMsg{
+		Time:    l.Time(),
+		Message: strings.TrimSpace(msg),
+		Level:   l.Level(),
+		Fields:  l.FieldsMap(),
+		Stack:   l.CallStack(),
+	}
 
-	if n := l.FieldsCount(); n > 0 {
-		iter := l.Fields()
+	l.l.send(l.Context(), m)
+	l.terminate(2, &m)
+}
 
-		m = make(map[string]any, n)
+// LogBatch sends many entries at once, on top of the context of
+// the logger, taking the lock once. A Fatal or Panic entry ends
+// the batch, terminating like Print() would.
+func (l *Logger) LogBatch(entries []slog.Entry) {
+	if len(entries) == 0 {
+		return
+	}
 
-		for iter.Next() {
-			k, v := iter.Field()
+	msgs := make([]LogMsg, 0, len(entries))
+	for i := range entries {
+		msg := l.newMsg(&entries[i])
+		msgs = append(msgs, msg)
 
-			m[k] = v
+		if msg.Level <= slog.Fatal {
+			// the rest are never sent
+			break
 		}
 	}
 
-	l.l.ch <- LogMsg{
-		Message: strings.TrimSpace(msg),
-		Level:   l.Level(),
-		Fields:  m,
-		Stack:   l.CallStack(),
+	l.l.sendBatch(l.Context(), msgs)
+	l.terminate(1, &msgs[len(msgs)-1])
+}
+
+// terminate, after a Fatal or Panic entry, waits up to
+// DefaultShutdownTimeout for the pending entries to be delivered,
+// and then calls slog.Exit(1) or panics respectively. The logger
+// remains open, so it keeps working if slog.SetExitFunc() or a
+// recover() prevent the program from terminating.
+// The panic carries the call stack skip frames above the caller.
+func (l *Logger) terminate(skip int, msg *LogMsg) {
+	if msg.Level > slog.Fatal {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultShutdownTimeout)
+	_ = l.Flush(ctx)
+	cancel()
+
+	if msg.Level == slog.Fatal {
+		slog.Exit(1)
+	} else {
+		panic(core.NewPanicError(skip+1, msg.Message))
+	}
+}
+
+// newMsg composes the LogMsg of an Entry of a batch
+func (l *Logger) newMsg(e *slog.Entry) LogMsg {
+	msg := LogMsg{
+		Time:    e.Time,
+		Message: strings.TrimSpace(e.Message),
+		Level:   e.Level,
+		Fields:  l.FieldsMap(),
+		Stack:   e.Stack,
+	}
+
+	for k, v := range e.Fields {
+		if k == "" {
+			continue
+		}
+		if msg.Fields == nil {
+			msg.Fields = make(map[string]any, len(e.Fields))
+		}
+		msg.Fields[k] = slog.ResolveValue(v)
+	}
+
+	if msg.Time.IsZero() {
+		msg.Time = l.Time()
+	}
+	if msg.Level <= slog.UndefinedLevel {
+		msg.Level = slog.Info
+	}
+	if len(msg.Stack) == 0 {
+		msg.Stack = l.CallStack()
+	}
+	return msg
+}
+
+// Flush waits until all entries sent so far have been delivered,
+// or the context is cancelled. When using a callback, delivered
+// means the callback has returned. Otherwise it means the entries
+// have been read from the channel, and then the WithFlush function,
+// if any, is called to wait for the consumer.
+func (l *Logger) Flush(ctx context.Context) error {
+	if err := l.l.flush(ctx); err != nil {
+		return err
+	}
+	if fn := l.l.onFlush; fn != nil {
+		return fn(ctx)
 	}
+	return nil
+}
+
+// DroppedCount returns the number of entries discarded
+// because the channel was full
+func (l *Logger) DroppedCount() uint64 {
+	return l.l.dropped.Load()
+}
+
+// Health returns ErrClosed once the logger has been closed,
+// otherwise the result of the WithHealthCheck function, if any.
+func (l *Logger) Health() error {
+	return l.l.healthy()
+}
+
+// Stats returns the number of entries dropped and pending,
+// plus the counters of the WithStats function, if any.
+func (l *Logger) Stats() slog.Stats {
+	out := slog.Stats{
+		slog.DroppedStat: l.l.dropped.Load(),
+		slog.PendingStat: l.l.pendingCount(),
+	}
+	if fn := l.l.stats; fn != nil {
+		out.Add(fn())
+	}
+	return out
+}
+
+// Close stops accepting new entries and waits, up to
+// DefaultShutdownTimeout, for the pending ones to be delivered.
+func (l *Logger) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultShutdownTimeout)
+	defer cancel()
+
+	return l.Shutdown(ctx)
+}
+
+// Shutdown stops accepting new entries and waits for the pending
+// ones to be delivered, or the context to be cancelled.
+// The channel is closed so consumers know there won't be more entries.
+func (l *Logger) Shutdown(ctx context.Context) error {
+	l.l.close()
+
+	if err := l.l.flush(ctx); err != nil {
+		return err
+	}
+	return l.l.wait(ctx)
 }
 
 // Debug returns a new logger set to add entries as level Debug
@@ -143,11 +323,66 @@ func (l *Logger) WithStack(skip int) slog.Logger {
 	return out
 }
 
+// WithCallStack attaches a given call stack to a new logger
+func (l *Logger) WithCallStack(st core.Stack) slog.Logger {
+	out := &Logger{
+		Loglet: l.Loglet.WithCallStack(st),
+		l:      l.l,
+	}
+	return out
+}
+
+// WithTime records when the event happened on a new logger,
+// used as Time of the LogMsg instead of when it was sent
+func (l *Logger) WithTime(t time.Time) slog.Logger {
+	out := &Logger{
+		Loglet: l.Loglet.WithTime(t),
+		l:      l.l,
+	}
+	return out
+}
+
+// WithContext records the context entries are logged on behalf of
+// on a new logger. If the channel is full and the OverflowPolicy is to
+// block, the entry is dropped once the context is done.
+func (l *Logger) WithContext(ctx context.Context) slog.Logger {
+	out := &Logger{
+		Loglet: l.Loglet.WithContext(ctx),
+		l:      l.l,
+	}
+	return out
+}
+
+// WithCaller attaches the immediate caller to a new logger,
+// as slog.CallerFieldName field of core.Frame type
+func (l *Logger) WithCaller(skip int) slog.Logger {
+	out := &Logger{
+		Loglet: l.Loglet.WithCaller(skip + 1),
+		l:      l.l,
+	}
+	return out
+}
+
+// WithName appends a component to the dot-separated name of a
+// new logger, stored as slog.NameFieldName field
+func (l *Logger) WithName(name string) slog.Logger {
+	if name != "" {
+		ll := l.Loglet.WithName(name)
+		out := &Logger{
+			Loglet: ll.Compact(),
+			l:      l.l,
+		}
+		return out
+	}
+	return l
+}
+
 // WithField returns a new logger with a field attached
 func (l *Logger) WithField(label string, value any) slog.Logger {
 	if label != "" {
+		ll := l.Loglet.WithField(label, value)
 		out := &Logger{
-			Loglet: l.Loglet.WithField(label, value),
+			Loglet: ll.Compact(),
 			l:      l.l,
 		}
 		return out
@@ -160,8 +395,23 @@ func (l *Logger) WithFields(fields map[string]any) slog.Logger {
 	delete(fields, "")
 
 	if len(fields) > 0 {
+		ll := l.Loglet.WithFields(fields)
+		out := &Logger{
+			Loglet: ll.Compact(),
+			l:      l.l,
+		}
+		return out
+	}
+	return l
+}
+
+// WithKV returns a new logger with fields attached from
+// alternating key/value pairs
+func (l *Logger) WithKV(pairs ...any) slog.Logger {
+	if len(pairs) > 0 {
+		ll := l.Loglet.WithKV(pairs...)
 		out := &Logger{
-			Loglet: l.Loglet.WithFields(fields),
+			Loglet: ll.Compact(),
 			l:      l.l,
 		}
 		return out
@@ -170,19 +420,168 @@ func (l *Logger) WithFields(fields map[string]any) slog.Logger {
 }
 
 // New creates a new Channel Based Logger
-func New(ch chan LogMsg) (*Logger, <-chan LogMsg) {
+func New(ch chan LogMsg, opts ...Option) (*Logger, <-chan LogMsg) {
 	if ch == nil {
 		ch = make(chan LogMsg, DefaultOutputBufferSize)
 	}
 
-	l := newLogger(ch)
+	l := newLogger(ch, opts)
 	return &l.Logger, ch
 }
 
-func newLogger(ch chan LogMsg) *cblog {
+func newLogger(ch chan LogMsg, opts []Option) *cblog {
 	l := &cblog{
-		ch: ch,
+		ch:    ch,
+		done:  make(chan struct{}),
+		clock: internal.SystemClock,
 	}
 	l.Logger.l = l
+	l.apply(opts)
 	return l
 }
+
+func (l *cblog) send(ctx context.Context, msg LogMsg) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if !l.closed {
+		l.sendLocked(ctx, msg)
+	}
+}
+
+// sendBatch sends many messages holding the lock once
+func (l *cblog) sendBatch(ctx context.Context, msgs []LogMsg) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	for _, msg := range msgs {
+		if l.closed {
+			// discard
+			return
+		}
+		l.sendLocked(ctx, msg)
+	}
+}
+
+// sendLocked sends a message while holding the read lock,
+// unless closed
+func (l *cblog) sendLocked(ctx context.Context, msg LogMsg) {
+	if msg.Time.IsZero() {
+		msg.Time = l.clock.Now()
+	}
+	msg.Seq = l.seq.Add(1)
+
+	l.pending.Add(1)
+	select {
+	case l.ch <- msg:
+		return
+	default:
+		// full
+	}
+
+	if l.overflow == OverflowDrop {
+		l.pending.Add(-1)
+		l.drop(msg)
+		return
+	}
+
+	var expired <-chan struct{}
+	if ctx != nil {
+		expired = ctx.Done()
+	}
+
+	select {
+	case l.ch <- msg:
+	case <-l.done:
+		// shutting down
+		l.pending.Add(-1)
+	case <-expired:
+		// given up
+		l.pending.Add(-1)
+		l.drop(msg)
+	}
+}
+
+func (l *cblog) drop(msg LogMsg) {
+	l.dropped.Add(1)
+
+	if fn := l.onOverflow; fn != nil {
+		fn(msg)
+	}
+}
+
+func (l *cblog) close() {
+	l.once.Do(func() {
+		// unblock senders
+		close(l.done)
+
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		l.closed = true
+		close(l.ch)
+	})
+}
+
+func (l *cblog) healthy() error {
+	l.mu.RLock()
+	closed := l.closed
+	l.mu.RUnlock()
+
+	switch {
+	case closed:
+		return ErrClosed
+	case l.health != nil:
+		return l.health()
+	default:
+		return nil
+	}
+}
+
+func (l *cblog) pendingCount() uint64 {
+	if l.worker {
+		return uint64(max(l.pending.Load(), 0))
+	}
+	return uint64(len(l.ch))
+}
+
+func (l *cblog) flushed() bool {
+	if l.worker {
+		return l.pending.Load() == 0
+	}
+	return len(l.ch) == 0
+}
+
+func (l *cblog) flush(ctx context.Context) error {
+	if l.flushed() {
+		return nil
+	}
+
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	for !l.flushed() {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
+// wait waits for the worker to finish
+func (l *cblog) wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		l.wg.Wait()
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
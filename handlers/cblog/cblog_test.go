@@ -0,0 +1,184 @@
+package cblog
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"darvaza.org/core"
+	"darvaza.org/slog"
+	slogtest "darvaza.org/slog/internal/testing"
+)
+
+// collector records the entries delivered to a callback
+type collector struct {
+	mu   sync.Mutex
+	msgs []LogMsg
+}
+
+func (c *collector) add(msg LogMsg) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.msgs = append(c.msgs, msg)
+}
+
+func (c *collector) messages() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	out := make([]string, 0, len(c.msgs))
+	for _, msg := range c.msgs {
+		out = append(out, msg.Message)
+	}
+	return out
+}
+
+func newTestLogger(t *testing.T) (*Logger, *collector) {
+	c := new(collector)
+	l := NewWithCallback(0, c.add)
+	t.Cleanup(func() { _ = l.Close() })
+	return l, c
+}
+
+func interceptExit(t *testing.T) *int {
+	code := -1
+	slog.SetExitFunc(func(c int) { code = c })
+	t.Cleanup(func() { slog.SetExitFunc(nil) })
+	return &code
+}
+
+func TestFatalKeepsLoggerOpen(t *testing.T) {
+	code := interceptExit(t)
+	l, c := newTestLogger(t)
+
+	l.Info().Print("before")
+	l.Fatal().Print("fatal")
+
+	if *code != 1 {
+		t.Fatalf("expected exit code 1, got %d", *code)
+	}
+	if msgs := c.messages(); len(msgs) != 2 || msgs[1] != "fatal" {
+		t.Fatalf("Fatal didn't wait for delivery: %q", msgs)
+	}
+	if err := l.Health(); err != nil {
+		t.Fatalf("logger closed by Fatal: %v", err)
+	}
+
+	l.Info().Print("after")
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if msgs := c.messages(); len(msgs) != 3 || msgs[2] != "after" {
+		t.Errorf("logger unusable after Fatal: %q", msgs)
+	}
+}
+
+func TestPanicPanics(t *testing.T) {
+	l, c := newTestLogger(t)
+
+	err := core.Catch(func() error {
+		l.Panic().Print("panic")
+		return nil
+	})
+	if err == nil {
+		t.Fatal("Panic didn't panic")
+	}
+	if msgs := c.messages(); len(msgs) != 1 || msgs[0] != "panic" {
+		t.Errorf("Panic didn't wait for delivery: %q", msgs)
+	}
+
+	pe, ok := err.(*core.PanicError)
+	if !ok {
+		t.Fatalf("expected a *core.PanicError, got %T", err)
+	}
+	if s := fmt.Sprint(pe.Recovered()); s != "panic" {
+		t.Errorf("expected the message as payload, got %q", s)
+	}
+	if st := pe.CallStack(); len(st) == 0 ||
+		!strings.HasSuffix(st[0].Name(), "TestPanicPanics.func1") {
+		t.Errorf("call stack not starting at the caller: %v", st)
+	}
+}
+
+func TestBatchStopsAtFatal(t *testing.T) {
+	code := interceptExit(t)
+	l, c := newTestLogger(t)
+
+	l.LogBatch([]slog.Entry{
+		{Level: slog.Info, Message: "first"},
+		{Level: slog.Fatal, Message: "fatal"},
+		{Level: slog.Info, Message: "never"},
+	})
+
+	if *code != 1 {
+		t.Fatalf("expected exit code 1, got %d", *code)
+	}
+	if msgs := c.messages(); len(msgs) != 2 || msgs[1] != "fatal" {
+		t.Errorf("unexpected entries: %q", msgs)
+	}
+}
+
+func TestConcurrentMutation(t *testing.T) {
+	s := slogtest.ConcurrentMutationSuite{
+		Factory: func() slog.Logger {
+			return NewWithCallback(0, func(LogMsg) {})
+		},
+		Teardown: func(l slog.Logger) {
+			if err := l.(*Logger).Close(); err != nil {
+				t.Error(err)
+			}
+		},
+		Leaks: &slogtest.LeakOptions{},
+	}
+	s.Run(t)
+}
+
+func TestFanoutDropsPerHandler(t *testing.T) {
+	release := make(chan struct{})
+	stuck := func(LogMsg) error {
+		<-release
+		return nil
+	}
+
+	c := new(collector)
+	fast := func(msg LogMsg) error {
+		c.add(msg)
+		return nil
+	}
+
+	l := NewFanoutWithOptions(1, []Handler{stuck, fast},
+		WithOverflowPolicy(OverflowDrop))
+
+	const n = 20
+	for i := 0; i < n; i++ {
+		l.Info().Print(i)
+		// let the dispatcher catch up
+		if err := waitFor(func() bool { return len(c.messages()) == i+1 }); err != nil {
+			t.Fatalf("entry #%d held back by a stuck handler", i)
+		}
+	}
+
+	close(release)
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if dropped := l.DroppedCount(); dropped == 0 || dropped >= n {
+		t.Errorf("expected some entries dropped by the stuck handler, got %d", dropped)
+	}
+}
+
+// waitFor polls a condition for up to a second
+func waitFor(cond func() bool) error {
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			return context.DeadlineExceeded
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return nil
+}
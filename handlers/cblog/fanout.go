@@ -0,0 +1,122 @@
+package cblog
+
+import (
+	"darvaza.org/core"
+	"darvaza.org/slog"
+)
+
+// Handler processes a LogMsg delivered by a fan-out Logger
+type Handler func(LogMsg) error
+
+// WithErrorHandler sets a function to be called when a fan-out
+// Handler fails or panics, instead of reporting it using
+// slog.ReportError()
+func WithErrorHandler(fn func(LogMsg, error)) Option {
+	return func(l *cblog) {
+		l.onError = fn
+	}
+}
+
+// NewFanout creates a logger that delivers each log entry to all the
+// provided handlers. Each handler runs on its own goroutine with its
+// own buffer of the given size, and errors or panics on one of them
+// don't affect the others. The OverflowPolicy also applies to each
+// handler's buffer, so with OverflowDrop a handler falling behind
+// loses entries without holding back the others.
+func NewFanout(size int, handlers ...Handler) *Logger {
+	return NewFanoutWithOptions(size, handlers)
+}
+
+// NewFanoutWithOptions creates a fan-out logger like NewFanout,
+// also taking options
+func NewFanoutWithOptions(size int, handlers []Handler, opts ...Option) *Logger {
+	handlers = core.SliceCopyFn(handlers, func(_ []Handler, h Handler) (Handler, bool) {
+		return h, h != nil
+	})
+
+	if len(handlers) > 0 {
+		if size <= 0 {
+			size = DefaultOutputBufferSize
+		}
+
+		return newFanout(size, handlers, opts)
+	}
+	return nil
+}
+
+func newFanout(size int, handlers []Handler, opts []Option) *Logger {
+	ch := make(chan LogMsg, size)
+	l := newLogger(ch, opts)
+	l.worker = true
+
+	outs := make([]chan LogMsg, len(handlers))
+	for i, h := range handlers {
+		outs[i] = make(chan LogMsg, size)
+		l.spawnHandler(outs[i], h)
+	}
+
+	l.wg.Add(1)
+	go func() {
+		defer l.wg.Done()
+		defer func() {
+			for _, out := range outs {
+				close(out)
+			}
+		}()
+
+		for msg := range ch {
+			// one pending per handler
+			l.pending.Add(int64(len(outs) - 1))
+
+			for _, out := range outs {
+				l.dispatch(out, msg)
+			}
+		}
+	}()
+
+	return &l.Logger
+}
+
+// dispatch passes a message to the buffer of a handler,
+// applying the OverflowPolicy if it's full
+func (l *cblog) dispatch(out chan<- LogMsg, msg LogMsg) {
+	if l.overflow == OverflowDrop {
+		select {
+		case out <- msg:
+		default:
+			// this handler is behind
+			l.pending.Add(-1)
+			l.drop(msg)
+		}
+		return
+	}
+
+	out <- msg
+}
+
+func (l *cblog) spawnHandler(ch <-chan LogMsg, h Handler) {
+	l.wg.Add(1)
+	go func() {
+		defer l.wg.Done()
+
+		for msg := range ch {
+			err := core.Catch(func() error {
+				return h(msg)
+			})
+
+			if err != nil {
+				l.reportError(msg, err)
+			}
+
+			l.pending.Add(-1)
+		}
+	}()
+}
+
+func (l *cblog) reportError(msg LogMsg, err error) {
+	if fn := l.onError; fn != nil {
+		fn(msg, err)
+	} else {
+		slog.ReportError("cblog", err)
+	}
+}
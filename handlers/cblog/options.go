@@ -0,0 +1,87 @@
+package cblog
+
+import (
+	"context"
+
+	"darvaza.org/slog"
+	"darvaza.org/slog/internal"
+)
+
+// OverflowPolicy determines what happens to new entries
+// when the channel is full
+type OverflowPolicy int
+
+const (
+	// OverflowBlock waits until there is room on the channel
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDrop discards the entry and counts it
+	OverflowDrop
+)
+
+// Option configures a channel based Logger
+type Option func(*cblog)
+
+// WithOverflowPolicy sets what to do with new entries when
+// the channel is full. Defaults to OverflowBlock.
+func WithOverflowPolicy(policy OverflowPolicy) Option {
+	return func(l *cblog) {
+		l.overflow = policy
+	}
+}
+
+// WithOverflowHandler discards entries when the channel is full,
+// passing them to the given function after counting them.
+// The function is called synchronously by the logger.
+func WithOverflowHandler(fn func(LogMsg)) Option {
+	return func(l *cblog) {
+		l.overflow = OverflowDrop
+		l.onOverflow = fn
+	}
+}
+
+// Clock is the source of time used to timestamp entries
+type Clock = internal.Clock
+
+// WithClock sets the Clock used to timestamp entries
+// when they are sent. Defaults to the system clock.
+func WithClock(clock Clock) Option {
+	return func(l *cblog) {
+		if clock != nil {
+			l.clock = clock
+		}
+	}
+}
+
+// WithHealthCheck sets a function consulted by Health() when
+// the logger is open, typically reporting the state of whatever
+// consumes the channel
+func WithHealthCheck(fn func() error) Option {
+	return func(l *cblog) {
+		l.health = fn
+	}
+}
+
+// WithFlush sets a function called by Flush() once the entries have
+// been read from the channel, typically waiting until whatever consumes
+// it has delivered them
+func WithFlush(fn func(context.Context) error) Option {
+	return func(l *cblog) {
+		l.onFlush = fn
+	}
+}
+
+// WithStats sets a function whose counters are added
+// to those reported by Stats()
+func WithStats(fn func() slog.Stats) Option {
+	return func(l *cblog) {
+		l.stats = fn
+	}
+}
+
+func (l *cblog) apply(opts []Option) {
+	for _, opt := range opts {
+		if opt != nil {
+			opt(l)
+		}
+	}
+}
@@ -2,27 +2,30 @@ package cblog
 
 // NewWithCallback creates a logger and attached a goroutine to call
 // a provided handler, sequentially, for each log entry
-func NewWithCallback(size int, h func(LogMsg)) *Logger {
+func NewWithCallback(size int, h func(LogMsg), opts ...Option) *Logger {
 	// TODO: default handler?
 	if h != nil {
 		if size <= 0 {
 			size = DefaultOutputBufferSize
 		}
 
-		return newWithWorker(size, h)
+		return newWithWorker(size, h, opts)
 	}
 	return nil
 }
 
-func newWithWorker(size int, h func(LogMsg)) *Logger {
+func newWithWorker(size int, h func(LogMsg), opts []Option) *Logger {
 	ch := make(chan LogMsg, size)
-	l := newLogger(ch)
+	l := newLogger(ch, opts)
+	l.worker = true
 
+	l.wg.Add(1)
 	go func() {
-		defer close(ch)
+		defer l.wg.Done()
 
 		for msg := range ch {
 			h(msg)
+			l.pending.Add(-1)
 		}
 	}()
 
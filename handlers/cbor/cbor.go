@@ -0,0 +1,348 @@
+// Package cbor provides a slog.Logger writing entries in CBOR,
+// a compact binary encoding, to an io.Writer
+package cbor
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"darvaza.org/core"
+	"darvaza.org/slog"
+	"darvaza.org/slog/internal"
+)
+
+var (
+	_ slog.Logger       = (*Logger)(nil)
+	_ slog.CallerLogger = (*Logger)(nil)
+	_ slog.BatchLogger  = (*Logger)(nil)
+	_ slog.NamedLogger  = (*Logger)(nil)
+	_ slog.TimedLogger  = (*Logger)(nil)
+	_ slog.StackLogger  = (*Logger)(nil)
+)
+
+// Clock is the source of time used by the Logger
+type Clock = internal.Clock
+
+// output is the io.Writer shared by all the loggers derived
+// from the same New() call
+type output struct {
+	mu      sync.Mutex
+	w       io.Writer
+	clock   Clock
+	onError func(error)
+}
+
+func (out *output) write(b []byte) {
+	out.mu.Lock()
+	_, err := out.w.Write(b)
+	out.mu.Unlock()
+
+	if err != nil {
+		out.reportError(err)
+	}
+}
+
+func (out *output) reportError(err error) {
+	if fn := out.onError; fn != nil {
+		fn(err)
+	} else {
+		slog.ReportError("cbor", err)
+	}
+}
+
+// Option configures the Logger created by New
+type Option func(*output)
+
+// WithClock sets the source of time of entries that don't
+// specify one. Defaults to the system clock
+func WithClock(clock Clock) Option {
+	return func(out *output) {
+		out.clock = clock
+	}
+}
+
+// WithErrorHandler sets a function to be called when encoding
+// or writing an entry fails, instead of reporting it using
+// slog.ReportError()
+func WithErrorHandler(fn func(error)) Option {
+	return func(out *output) {
+		out.onError = fn
+	}
+}
+
+// Logger is a slog.Logger writing CBOR entries to an io.Writer
+type Logger struct {
+	internal.Loglet
+
+	out *output
+}
+
+// Enabled tells this logger is enabled
+func (l *Logger) Enabled() bool {
+	return l != nil && l.out != nil
+}
+
+// WithEnabled passes the logger and if it's enabled
+func (l *Logger) WithEnabled() (slog.Logger, bool) {
+	return l, l.Enabled()
+}
+
+// Print adds a log entry with arguments handled in the manner of fmt.Print
+func (l *Logger) Print(args ...any) {
+	l.msg(fmt.Sprint(args...))
+}
+
+// Println adds a log entry with arguments handled in the manner of fmt.Println
+func (l *Logger) Println(args ...any) {
+	l.msg(fmt.Sprintln(args...))
+}
+
+// Printf adds a log entry with arguments handled in the manner of fmt.Printf
+func (l *Logger) Printf(format string, args ...any) {
+	l.msg(internal.Sprintf(format, args...))
+}
+
+func (l *Logger) msg(msg string) {
+	msg = strings.TrimSpace(msg)
+
+	if l.Enabled() {
+		l.write(msg)
+	}
+
+	switch l.Level() {
+	case slog.Fatal:
+		slog.Exit(1)
+	case slog.Panic:
+		panic(core.NewPanicError(2, msg))
+	}
+}
+
+func (l *Logger) write(msg string) {
+	t := l.Time()
+	if t.IsZero() {
+		t = l.out.clock.Now()
+	}
+
+	b, err := Encode(&Entry{
+		Time:    t,
+		Level:   l.Level(),
+		Message: msg,
+		Fields:  l.FieldsMap(),
+		Stack:   slog.StackFrames(l.CallStack()),
+	})
+	if err != nil {
+		l.out.reportError(err)
+		return
+	}
+
+	l.out.write(b)
+}
+
+// LogBatch writes many entries, on top of the context of the logger,
+// with a single Write() call. Entries failing to encode are skipped
+// and reported. A Fatal or Panic entry ends the batch, terminating
+// once written.
+func (l *Logger) LogBatch(entries []slog.Entry) {
+	var buf []byte
+	var terminal *slog.Entry
+
+	for i := range entries {
+		e := l.batchEntry(&entries[i])
+
+		b, err := Encode(e)
+		if err != nil {
+			l.out.reportError(err)
+			continue
+		}
+		buf = append(buf, b...)
+
+		if e.Level == slog.Fatal || e.Level == slog.Panic {
+			// the rest are never written
+			terminal = &entries[i]
+			break
+		}
+	}
+
+	if len(buf) > 0 {
+		l.out.write(buf)
+	}
+
+	switch {
+	case terminal == nil:
+		return
+	case terminal.Level == slog.Fatal:
+		slog.Exit(1)
+	default:
+		panic(core.NewPanicError(1, strings.TrimSpace(terminal.Message)))
+	}
+}
+
+// batchEntry composes the Entry written for one of a batch
+func (l *Logger) batchEntry(be *slog.Entry) *Entry {
+	e := &Entry{
+		Time:    be.Time,
+		Level:   be.Level,
+		Message: strings.TrimSpace(be.Message),
+		Fields:  l.FieldsMap(),
+		Stack:   slog.StackFrames(be.Stack),
+	}
+
+	for k, v := range be.Fields {
+		if k == "" {
+			continue
+		}
+		if e.Fields == nil {
+			e.Fields = make(map[string]any, len(be.Fields))
+		}
+		e.Fields[k] = slog.ResolveValue(v)
+	}
+
+	if e.Time.IsZero() {
+		e.Time = l.Time()
+	}
+	if e.Time.IsZero() {
+		e.Time = l.out.clock.Now()
+	}
+	if e.Level <= slog.UndefinedLevel {
+		e.Level = slog.Info
+	}
+	if len(e.Stack) == 0 {
+		e.Stack = slog.StackFrames(l.CallStack())
+	}
+	return e
+}
+
+// Debug returns a new logger set to add entries as level Debug
+func (l *Logger) Debug() slog.Logger {
+	return l.WithLevel(slog.Debug)
+}
+
+// Info returns a new logger set to add entries as level Info
+func (l *Logger) Info() slog.Logger {
+	return l.WithLevel(slog.Info)
+}
+
+// Warn returns a new logger set to add entries as level Warn
+func (l *Logger) Warn() slog.Logger {
+	return l.WithLevel(slog.Warn)
+}
+
+// Error returns a new logger set to add entries as level Error
+func (l *Logger) Error() slog.Logger {
+	return l.WithLevel(slog.Error)
+}
+
+// Fatal returns a new logger set to add entries as level Fatal
+func (l *Logger) Fatal() slog.Logger {
+	return l.WithLevel(slog.Fatal)
+}
+
+// Panic returns a new logger set to add entries as level Panic
+func (l *Logger) Panic() slog.Logger {
+	return l.WithLevel(slog.Panic)
+}
+
+// WithLevel returns a new logger set to add entries to the specified level
+func (l *Logger) WithLevel(level slog.LogLevel) slog.Logger {
+	if level <= slog.UndefinedLevel {
+		// fix your code
+		l.Panic().WithStack(1).Printf("slog: invalid log level %v", level)
+	} else if level == l.Level() {
+		return l
+	}
+
+	return &Logger{
+		Loglet: l.Loglet.WithLevel(level),
+		out:    l.out,
+	}
+}
+
+// WithStack attaches a call stack to a new logger
+func (l *Logger) WithStack(skip int) slog.Logger {
+	return &Logger{
+		Loglet: l.Loglet.WithStack(skip + 1),
+		out:    l.out,
+	}
+}
+
+// WithCallStack attaches a given call stack to a new logger
+func (l *Logger) WithCallStack(st core.Stack) slog.Logger {
+	return &Logger{
+		Loglet: l.Loglet.WithCallStack(st),
+		out:    l.out,
+	}
+}
+
+// WithTime records when the event happened on a new logger
+func (l *Logger) WithTime(t time.Time) slog.Logger {
+	return &Logger{
+		Loglet: l.Loglet.WithTime(t),
+		out:    l.out,
+	}
+}
+
+// WithCaller attaches the immediate caller to a new logger
+func (l *Logger) WithCaller(skip int) slog.Logger {
+	return &Logger{
+		Loglet: l.Loglet.WithCaller(skip + 1),
+		out:    l.out,
+	}
+}
+
+// WithName appends a component to the dot-separated name of a
+// new logger, written as slog.NameFieldName field
+func (l *Logger) WithName(name string) slog.Logger {
+	if name != "" {
+		return &Logger{
+			Loglet: l.Loglet.WithName(name),
+			out:    l.out,
+		}
+	}
+	return l
+}
+
+// WithField returns a new logger with a field attached
+func (l *Logger) WithField(label string, value any) slog.Logger {
+	if label != "" {
+		return &Logger{
+			Loglet: l.Loglet.WithField(label, value),
+			out:    l.out,
+		}
+	}
+	return l
+}
+
+// WithFields returns a new logger with a set of fields attached
+func (l *Logger) WithFields(fields map[string]any) slog.Logger {
+	delete(fields, "")
+
+	if len(fields) > 0 {
+		return &Logger{
+			Loglet: l.Loglet.WithFields(fields),
+			out:    l.out,
+		}
+	}
+	return l
+}
+
+// New creates a slog.Logger writing each entry to the given
+// io.Writer as a CBOR map, forming a CBOR sequence (RFC 8742).
+// Writes are serialised. If none is given, the logger is disabled.
+func New(w io.Writer, opts ...Option) slog.Logger {
+	if w == nil {
+		return &Logger{}
+	}
+
+	out := &output{w: w}
+	for _, opt := range opts {
+		opt(out)
+	}
+	if out.clock == nil {
+		out.clock = internal.SystemClock
+	}
+
+	return &Logger{out: out}
+}
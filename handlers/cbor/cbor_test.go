@@ -0,0 +1,165 @@
+package cbor
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"darvaza.org/slog"
+	slogtest "darvaza.org/slog/internal/testing"
+)
+
+// countingWriter counts the calls to Write
+type countingWriter struct {
+	bytes.Buffer
+	writes int
+}
+
+func (w *countingWriter) Write(b []byte) (int, error) {
+	w.writes++
+	return w.Buffer.Write(b)
+}
+
+func TestRoundTrip(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := slogtest.NewManualClock(start)
+
+	var buf bytes.Buffer
+	l := New(&buf, WithClock(clock))
+
+	slog.WithName(l.Info(), "db").WithField("user", "alice").Print("logged in")
+	l.Warn().WithField("n", 42).Printf("%d retries\n", 3)
+	l.Error().WithStack(0).Print("failed")
+
+	entries, err := Decode(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(entries))
+	}
+
+	e := entries[0]
+	if e.Level != slog.Info || e.Message != "logged in" || !e.Time.Equal(start) {
+		t.Errorf("unexpected entry: %+v", e)
+	}
+	if e.Fields["user"] != "alice" || e.Fields[slog.NameFieldName] != "db" {
+		t.Errorf("unexpected fields: %v", e.Fields)
+	}
+
+	e = entries[1]
+	if e.Message != "3 retries" || e.Fields["n"] != uint64(42) {
+		t.Errorf("unexpected entry: %+v", e)
+	}
+
+	e = entries[2]
+	if len(e.Stack) == 0 || !strings.HasSuffix(e.Stack[0].Function, "TestRoundTrip") {
+		t.Errorf("unexpected stack: %v", e.Stack)
+	}
+}
+
+func TestRelay(t *testing.T) {
+	at := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	l := New(&buf)
+	slog.WithTime(slog.WithName(l.Warn(), "worker"), at).
+		WithField("job", "sync").Print("slow")
+	l.Error().WithStack(0).Print("broken")
+	for _, level := range []slog.LogLevel{slog.Fatal, slog.Panic} {
+		// bypass the Logger, which would terminate
+		b, err := Encode(&Entry{Time: at, Level: level, Message: level.String()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		buf.Write(b)
+	}
+
+	rec := slogtest.NewRecorder()
+	if err := Relay(&buf, rec); err != nil {
+		t.Fatal(err)
+	}
+
+	msgs := rec.Messages()
+	slogtest.AssertMessagesInOrder(t, msgs,
+		slogtest.Expect().Level(slog.Warn).Message("slow").
+			Field(slog.NameFieldName, "worker").Field("job", "sync"),
+		slogtest.Expect().Level(slog.Error).Message("broken").
+			HasField(slog.StackFieldName),
+		slogtest.Expect().Level(slog.Error).Message("fatal").
+			Field(OriginalLevelFieldName, "fatal"),
+		slogtest.Expect().Level(slog.Error).Message("panic").
+			Field(OriginalLevelFieldName, "panic"))
+
+	if len(msgs) > 0 && !msgs[0].Time.Equal(at) {
+		t.Errorf("expected time %v, got %v", at, msgs[0].Time)
+	}
+}
+
+func TestRelayTruncated(t *testing.T) {
+	var buf bytes.Buffer
+	New(&buf).Info().Print("first")
+	New(&buf).Info().Print("second")
+
+	rec := slogtest.NewRecorder()
+	err := Relay(bytes.NewReader(buf.Bytes()[:buf.Len()-2]), rec)
+	if err == nil {
+		t.Error("expected error")
+	}
+	slogtest.AssertMessagesInOrder(t, rec.Messages(),
+		slogtest.Expect().Message("first"))
+}
+
+func TestLogBatch(t *testing.T) {
+	var w countingWriter
+	l := New(&w).WithField("service", "api")
+
+	slog.LogBatch(l, []slog.Entry{
+		{Message: "first", Fields: slog.Fields{"n": 1}},
+		{Message: "second", Level: slog.Warn},
+	})
+
+	if w.writes != 1 {
+		t.Errorf("expected a single write, got %d", w.writes)
+	}
+
+	entries, err := Decode(w.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	for i, want := range []slog.LogLevel{slog.Info, slog.Warn} {
+		e := entries[i]
+		if e.Level != want || e.Fields["service"] != "api" {
+			t.Errorf("#%d: unexpected entry %+v", i, e)
+		}
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("closed") }
+
+func TestWriteError(t *testing.T) {
+	var reported []error
+	l := New(failingWriter{}, WithErrorHandler(func(err error) {
+		reported = append(reported, err)
+	}))
+
+	l.Info().Print("lost")
+	if len(reported) != 1 {
+		t.Errorf("expected 1 error, got %v", reported)
+	}
+}
+
+func TestDisabled(t *testing.T) {
+	l := New(nil)
+	if l.Info().Enabled() {
+		t.Error("logger without io.Writer enabled")
+	}
+	l.Info().Print("ignored")
+}
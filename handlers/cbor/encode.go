@@ -0,0 +1,139 @@
+package cbor
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"time"
+
+	fxcbor "github.com/fxamacker/cbor/v2"
+
+	"darvaza.org/core"
+	"darvaza.org/slog"
+	"darvaza.org/slog/internal"
+)
+
+// Entry is a decoded log entry
+type Entry struct {
+	Time    time.Time
+	Fields  map[string]any
+	Message string
+	Stack   []slog.Frame
+	Level   slog.LogLevel
+}
+
+// record is the wire representation of an Entry, using integer
+// keys and the time as nanoseconds since the epoch
+type record struct {
+	Fields  map[string]any `cbor:"4,keyasint,omitempty"`
+	Message string         `cbor:"3,keyasint"`
+	Stack   []slog.Frame   `cbor:"5,keyasint,omitempty"`
+	Time    int64          `cbor:"1,keyasint"`
+	Level   slog.LogLevel  `cbor:"2,keyasint"`
+}
+
+var (
+	encMode fxcbor.EncMode
+	decMode fxcbor.DecMode
+)
+
+func init() {
+	var err error
+
+	encMode, err = fxcbor.CoreDetEncOptions().EncMode()
+	if err != nil {
+		core.Panic(err)
+	}
+
+	decMode, err = fxcbor.DecOptions{
+		DefaultMapType: reflect.TypeOf(map[string]any(nil)),
+	}.DecMode()
+	if err != nil {
+		core.Panic(err)
+	}
+}
+
+// Encode renders an Entry as a CBOR map
+func Encode(e *Entry) ([]byte, error) {
+	r := record{
+		Time:    e.Time.UnixNano(),
+		Level:   e.Level,
+		Message: e.Message,
+		Stack:   e.Stack,
+	}
+
+	if len(e.Fields) > 0 {
+		r.Fields = make(map[string]any, len(e.Fields))
+		for k, v := range e.Fields {
+			r.Fields[k] = encodeValue(v)
+		}
+	}
+
+	return encMode.Marshal(r)
+}
+
+// encodeValue prepares a field value, marshalling once those
+// not handled by internal.EncodeValue
+func encodeValue(v any) any {
+	if m, ok := v.(fxcbor.Marshaler); ok {
+		return m
+	}
+	if out, ok := internal.EncodeValue(v); ok {
+		return out
+	}
+
+	b, err := encMode.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return fxcbor.RawMessage(b)
+}
+
+// Decoder reads the entries written by a Logger
+type Decoder struct {
+	dec *fxcbor.Decoder
+}
+
+// NewDecoder creates a Decoder reading from the given io.Reader
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{
+		dec: decMode.NewDecoder(r),
+	}
+}
+
+// Decode reads the next entry. It returns io.EOF when
+// there are no more.
+func (d *Decoder) Decode() (*Entry, error) {
+	var r record
+
+	if err := d.dec.Decode(&r); err != nil {
+		return nil, err
+	}
+
+	return &Entry{
+		Time:    time.Unix(0, r.Time),
+		Level:   r.Level,
+		Message: r.Message,
+		Fields:  r.Fields,
+		Stack:   r.Stack,
+	}, nil
+}
+
+// Decode reads all the entries of a buffer
+func Decode(b []byte) ([]*Entry, error) {
+	var out []*Entry
+
+	d := NewDecoder(bytes.NewReader(b))
+	for {
+		e, err := d.Decode()
+		switch {
+		case errors.Is(err, io.EOF):
+			return out, nil
+		case err != nil:
+			return out, err
+		}
+		out = append(out, e)
+	}
+}
@@ -0,0 +1,17 @@
+module darvaza.org/slog/handlers/cbor
+
+go 1.22
+
+replace darvaza.org/slog => ../../
+
+require (
+	darvaza.org/core v0.16.0
+	darvaza.org/slog v0.6.0
+	github.com/fxamacker/cbor/v2 v2.7.0
+)
+
+require (
+	github.com/x448/float16 v0.8.4 // indirect
+	golang.org/x/net v0.34.0 // indirect
+	golang.org/x/text v0.21.0 // indirect
+)
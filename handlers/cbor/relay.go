@@ -0,0 +1,78 @@
+package cbor
+
+import (
+	"errors"
+	"io"
+
+	"darvaza.org/slog"
+)
+
+// OriginalLevelFieldName is the field Emit uses to preserve
+// the level of Fatal and Panic entries
+const OriginalLevelFieldName = "original_level"
+
+// Emit logs a decoded entry again on the given logger, e.g. one
+// received from another process, keeping its time, name, fields
+// and call stack, which is attached as slog.StackFieldName field
+// as the frames can't be resolved here.
+//
+// Fatal and Panic entries are emitted as Error, with their original
+// level as OriginalLevelFieldName field, so the process relaying them
+// isn't terminated.
+func (e *Entry) Emit(l slog.Logger) {
+	if l == nil || e == nil {
+		return
+	}
+
+	level := e.Level
+	switch {
+	case level == slog.Fatal, level == slog.Panic:
+		l = l.WithField(OriginalLevelFieldName, level.String())
+		level = slog.Error
+	case level <= slog.UndefinedLevel:
+		level = slog.Info
+	}
+
+	l, ok := l.WithLevel(level).WithEnabled()
+	if !ok {
+		return
+	}
+
+	fields := e.Fields
+	if name, ok := fields[slog.NameFieldName].(string); ok && name != "" {
+		fields = slog.Fields(fields).Clone()
+		delete(fields, slog.NameFieldName)
+		l = slog.WithName(l, name)
+	}
+
+	if !e.Time.IsZero() {
+		l = slog.WithTime(l, e.Time)
+	}
+	if len(fields) > 0 {
+		l = l.WithFields(fields)
+	}
+	if len(e.Stack) > 0 {
+		l = l.WithField(slog.StackFieldName, e.Stack)
+	}
+
+	l.Print(e.Message)
+}
+
+// Relay reads the entries written by a Logger, e.g. from a pipe or
+// socket connected to another process, and emits them on the given
+// logger until the io.Reader is exhausted. io.EOF isn't reported
+// as error.
+func Relay(r io.Reader, l slog.Logger) error {
+	d := NewDecoder(r)
+	for {
+		e, err := d.Decode()
+		switch {
+		case errors.Is(err, io.EOF):
+			return nil
+		case err != nil:
+			return err
+		}
+
+		e.Emit(l)
+	}
+}
@@ -0,0 +1,117 @@
+package cef
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"darvaza.org/slog"
+	slogtest "darvaza.org/slog/internal/testing"
+)
+
+var testTime = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestLogger(t *testing.T, cfg *Config) (slog.Logger, *bytes.Buffer) {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	cfg.Vendor = "Darvaza"
+	cfg.Product = "slog"
+	cfg.Version = "1.0"
+	cfg.Clock = slogtest.NewManualClock(testTime)
+
+	l, err := New(&buf, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return l, &buf
+}
+
+// logEntries writes the entries shared by the format tests
+func logEntries(l slog.Logger) {
+	l.Warn().WithFields(map[string]any{
+		DefaultSignatureField: "login",
+		"user":                "alice",
+		"x-y":                 "a=b",
+	}).Print("bad a|b\nc")
+	l.Info().Print("started")
+}
+
+func expectLines(t *testing.T, buf *bytes.Buffer, want ...string) {
+	t.Helper()
+
+	got := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(got) != len(want) {
+		t.Fatalf("expected %d lines, got %q", len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("#%d: expected\n%q\ngot\n%q", i, want[i], got[i])
+		}
+	}
+}
+
+func TestCEF(t *testing.T) {
+	l, buf := newTestLogger(t, &Config{})
+	logEntries(l)
+
+	expectLines(t, buf,
+		`CEF:0|Darvaza|slog|1.0|login|bad a\|b c|5|rt=1704110400000 suser=alice xy=a\=b`,
+		`CEF:0|Darvaza|slog|1.0|info|started|3|rt=1704110400000`)
+}
+
+func TestLEEF(t *testing.T) {
+	l, buf := newTestLogger(t, &Config{Format: LEEF})
+	logEntries(l)
+
+	expectLines(t, buf,
+		"LEEF:1.0|Darvaza|slog|1.0|login|devTime=Jan 01 2024 12:00:00.000 UTC"+
+			"\tsev=5\tmsg=bad a|b\\nc\tsuser=alice\txy=a=b",
+		"LEEF:1.0|Darvaza|slog|1.0|info|devTime=Jan 01 2024 12:00:00.000 UTC"+
+			"\tsev=3\tmsg=started")
+}
+
+func TestDictionary(t *testing.T) {
+	l, buf := newTestLogger(t, &Config{
+		Dictionary:     map[string]string{"user": "duser"},
+		SignatureField: "code",
+		DropUnmapped:   true,
+	})
+
+	slog.WithTime(l.Error(), testTime.Add(time.Second)).WithFields(map[string]any{
+		"code":  "E42",
+		"user":  "bob",
+		"other": "dropped",
+	}).Print("denied")
+
+	expectLines(t, buf,
+		`CEF:0|Darvaza|slog|1.0|E42|denied|7|rt=1704110401000 duser=bob`)
+}
+
+func TestValidate(t *testing.T) {
+	for _, cfg := range []*Config{
+		nil,
+		{Vendor: "Darvaza"},
+		{Vendor: "Darvaza", Product: "slog", Format: LEEF + 1},
+		{Vendor: "Darvaza", Product: "slog", Dictionary: map[string]string{"user": "s-user"}},
+	} {
+		if _, err := New(&bytes.Buffer{}, cfg); err == nil {
+			t.Errorf("%+v: expected error", cfg)
+		}
+	}
+}
+
+func TestSeverity(t *testing.T) {
+	prev := -1
+	for _, level := range []slog.LogLevel{
+		slog.Debug, slog.Info, slog.Warn, slog.Error, slog.Fatal, slog.Panic,
+	} {
+		sev := Severity(level)
+		if sev <= prev || sev > 10 {
+			t.Errorf("%s: unexpected severity %d", level, sev)
+		}
+		prev = sev
+	}
+}
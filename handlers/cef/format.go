@@ -0,0 +1,247 @@
+package cef
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"darvaza.org/core"
+	"darvaza.org/slog"
+	"darvaza.org/slog/internal"
+)
+
+// Format is the kind of record written
+type Format int
+
+const (
+	// CEF is ArcSight's Common Event Format
+	CEF Format = iota
+	// LEEF is IBM QRadar's Log Event Extended Format 1.0
+	LEEF
+)
+
+const (
+	// DefaultSignatureField is the field identifying the
+	// type of event
+	DefaultSignatureField = "event"
+
+	// LEEFTimeFormat is the layout of the devTime attribute
+	// of LEEF records
+	LEEFTimeFormat = "Jan 02 2006 15:04:05.000 MST"
+)
+
+// Clock is the source of time used by the Logger
+type Clock = internal.Clock
+
+// DefaultDictionary maps well-known field names to CEF extensions
+var DefaultDictionary = map[string]string{
+	"user":               "suser",
+	"method":             "requestMethod",
+	"path":               "request",
+	"request_id":         "externalId",
+	slog.ErrorFieldName:  "reason",
+	slog.NameFieldName:   "deviceFacility",
+	slog.CallerFieldName: "deviceProcessName",
+}
+
+// Config describes the records written by the Logger
+type Config struct {
+	// Clock is the source of time of entries that don't
+	// specify one. Defaults to the system clock
+	Clock Clock
+	// OnError is called when writing a record fails,
+	// instead of reporting it using slog.ReportError()
+	OnError func(error)
+
+	// Dictionary maps field names to extension keys.
+	// Defaults to DefaultDictionary
+	Dictionary map[string]string
+
+	// Vendor, Product and Version identify the device
+	// in the header
+	Vendor  string
+	Product string
+	Version string
+
+	// SignatureField is the field used as Signature ID, or Event
+	// ID on LEEF. The level is used if missing.
+	// Defaults to DefaultSignatureField
+	SignatureField string
+
+	// Format is the kind of record written. Defaults to CEF
+	Format Format
+
+	// DropUnmapped omits fields not in the Dictionary instead of
+	// writing them using their sanitised names as keys
+	DropUnmapped bool
+}
+
+// SetDefaults sets the DefaultDictionary, DefaultSignatureField
+// and the system clock if not given
+func (cfg *Config) SetDefaults() {
+	if cfg.Clock == nil {
+		cfg.Clock = internal.SystemClock
+	}
+	if cfg.Dictionary == nil {
+		cfg.Dictionary = DefaultDictionary
+	}
+	if cfg.SignatureField == "" {
+		cfg.SignatureField = DefaultSignatureField
+	}
+}
+
+// Validate checks the Format, that Vendor and Product are given,
+// and the Dictionary maps to valid extension keys
+func (cfg *Config) Validate() error {
+	switch {
+	case cfg.Format != CEF && cfg.Format != LEEF:
+		return core.Wrapf(core.ErrInvalid, "cef: invalid format %v", cfg.Format)
+	case cfg.Vendor == "", cfg.Product == "":
+		return core.Wrap(core.ErrInvalid, "cef: vendor and product required")
+	}
+
+	for field, key := range cfg.Dictionary {
+		if key == "" || extensionKey(key) != key {
+			return core.Wrapf(core.ErrInvalid, "cef: invalid extension %q for %q", key, field)
+		}
+	}
+	return nil
+}
+
+// Severity converts a slog.LogLevel into a CEF severity,
+// from 0 to 10
+func Severity(level slog.LogLevel) int {
+	switch level {
+	case slog.Panic:
+		return 10
+	case slog.Fatal:
+		return 9
+	case slog.Error:
+		return 7
+	case slog.Warn:
+		return 5
+	case slog.Info:
+		return 3
+	default:
+		return 1
+	}
+}
+
+// record is an entry about to be rendered
+type record struct {
+	Time      time.Time
+	Fields    map[string]any
+	Message   string
+	Signature string
+	Level     slog.LogLevel
+}
+
+// render writes a record, without new line
+func (cfg *Config) render(buf *bytes.Buffer, r *record) {
+	r.Signature = r.Level.String()
+	if v, ok := r.Fields[cfg.SignatureField]; ok {
+		r.Signature = valueString(v)
+	}
+
+	if cfg.Format == LEEF {
+		cfg.renderLEEF(buf, r)
+	} else {
+		cfg.renderCEF(buf, r)
+	}
+}
+
+func (cfg *Config) renderCEF(buf *bytes.Buffer, r *record) {
+	buf.WriteString("CEF:0")
+	for _, s := range []string{
+		cfg.Vendor, cfg.Product, cfg.Version, r.Signature, r.Message,
+		strconv.Itoa(Severity(r.Level)),
+	} {
+		buf.WriteByte('|')
+		buf.WriteString(escapeHeader(s))
+	}
+	buf.WriteByte('|')
+
+	buf.WriteString("rt=")
+	buf.WriteString(strconv.FormatInt(r.Time.UnixMilli(), 10))
+
+	cfg.renderExtensions(buf, ' ', r.Fields, escapeCEF)
+}
+
+func (cfg *Config) renderLEEF(buf *bytes.Buffer, r *record) {
+	buf.WriteString("LEEF:1.0")
+	for _, s := range []string{
+		cfg.Vendor, cfg.Product, cfg.Version, r.Signature,
+	} {
+		buf.WriteByte('|')
+		buf.WriteString(escapeHeader(s))
+	}
+	buf.WriteByte('|')
+
+	_, _ = fmt.Fprintf(buf, "devTime=%s\tsev=%d\tmsg=%s",
+		escapeLEEF(r.Time.Format(LEEFTimeFormat)), Severity(r.Level), escapeLEEF(r.Message))
+
+	cfg.renderExtensions(buf, '\t', r.Fields, escapeLEEF)
+}
+
+// renderExtensions writes the fields as key=value pairs, sorted
+// by field name, each preceded by the separator
+func (cfg *Config) renderExtensions(buf *bytes.Buffer, sep byte, fields map[string]any,
+	escape func(string) string) {
+	for _, k := range core.SortedKeys(fields) {
+		if k == cfg.SignatureField {
+			continue
+		}
+
+		key, ok := cfg.Dictionary[k]
+		switch {
+		case ok:
+		case cfg.DropUnmapped:
+			continue
+		default:
+			key = extensionKey(k)
+		}
+
+		if key != "" {
+			buf.WriteByte(sep)
+			buf.WriteString(key)
+			buf.WriteByte('=')
+			buf.WriteString(escape(valueString(fields[k])))
+		}
+	}
+}
+
+func valueString(v any) string {
+	switch x := v.(type) {
+	case core.Frame:
+		return slog.FormatCaller(x)
+	case time.Time:
+		return strconv.FormatInt(x.UnixMilli(), 10)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// extensionKey removes from a name the characters not valid
+// in extension keys
+func extensionKey(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return -1
+		}
+	}, name)
+}
+
+var (
+	headerEscaper = strings.NewReplacer(`\`, `\\`, `|`, `\|`, "\r", " ", "\n", " ")
+	cefEscaper    = strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\r", `\r`, "\n", `\n`)
+	leefEscaper   = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\r", `\r`, "\n", `\n`)
+)
+
+func escapeHeader(s string) string { return headerEscaper.Replace(s) }
+func escapeCEF(s string) string    { return cefEscaper.Replace(s) }
+func escapeLEEF(s string) string   { return leefEscaper.Replace(s) }
@@ -0,0 +1,15 @@
+module darvaza.org/slog/handlers/cef
+
+go 1.22
+
+replace darvaza.org/slog => ../../
+
+require (
+	darvaza.org/core v0.16.0
+	darvaza.org/slog v0.6.0
+)
+
+require (
+	golang.org/x/net v0.34.0 // indirect
+	golang.org/x/text v0.21.0 // indirect
+)
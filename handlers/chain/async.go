@@ -0,0 +1,237 @@
+package chain
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"darvaza.org/slog"
+	"darvaza.org/slog/internal"
+)
+
+// DefaultAsyncBufferSize is the number of entries an Async logger
+// holds when no size is given
+const DefaultAsyncBufferSize = 1024
+
+// DefaultShutdownTimeout is the maximum time Close() waits for
+// pending entries
+const DefaultShutdownTimeout = 5 * time.Second
+
+// ErrAsyncClosed is reported by the Health() of an Async logger
+// once it has been closed
+var ErrAsyncClosed = errors.New("chain: async logger closed")
+
+// job is an entry waiting to be printed, or a flush marker
+type job struct {
+	logger  slog.Logger
+	flushed chan struct{}
+	msg     string
+}
+
+// Async passes entries to its parent on a separate goroutine,
+// taking the cost of the parent away from the caller. Fatal and
+// Panic entries are passed synchronously, after the pending ones.
+//
+// When the buffer is full the caller is blocked, unless the
+// entry was logged on behalf of a context, see slog.WithContext(),
+// and it's done first. Those entries are dropped.
+type Async struct {
+	mu      sync.RWMutex
+	ch      chan job
+	done    chan struct{}
+	logger  slog.Logger
+	clock   internal.Clock
+	dropped atomic.Uint64
+	closed  bool
+}
+
+// NewAsync creates an Async logger holding up to size entries
+// before blocking the caller
+func NewAsync(parent slog.Logger, size int) *Async {
+	if parent == nil {
+		return nil
+	}
+	if size <= 0 {
+		size = DefaultAsyncBufferSize
+	}
+
+	a := &Async{
+		ch:    make(chan job, size),
+		done:  make(chan struct{}),
+		clock: internal.SystemClock,
+	}
+	a.logger = internal.NewWrapper(parent, &internal.WrapperHooks{
+		Print: a.print,
+		Batch: a.batch,
+		Flush: a.Flush,
+		Close: a.Close,
+
+		Health: a.Health,
+		Stats:  a.Stats,
+	})
+
+	go a.run()
+	return a
+}
+
+// Logger returns the slog.Logger whose entries are passed
+// asynchronously. slog.Flush() and slog.Close() on it reach
+// the Async logger.
+func (a *Async) Logger() slog.Logger {
+	return a.logger
+}
+
+func (a *Async) run() {
+	defer close(a.done)
+
+	for j := range a.ch {
+		if j.flushed != nil {
+			close(j.flushed)
+		} else {
+			j.logger.Print(j.msg)
+		}
+	}
+}
+
+func (a *Async) print(e *internal.Entry) bool {
+	switch e.Level() {
+	case slog.Fatal, slog.Panic:
+		// deliver what's pending before terminating
+		ctx, cancel := context.WithTimeout(context.Background(), DefaultShutdownTimeout)
+		defer cancel()
+
+		_ = a.Flush(ctx)
+		return true
+	}
+
+	l := e.Logger
+	if e.Loglet.Time().IsZero() {
+		l = slog.WithTime(l, a.clock.Now())
+	}
+
+	// print synchronously once closed
+	return !a.send(e.Loglet.Context(), job{logger: l, msg: e.Message})
+}
+
+// batch queues the entries of a batch taking the lock once.
+// Batches with Fatal or Panic entries are emitted one by one.
+func (a *Async) batch(ll *internal.Loglet, parent slog.Logger, entries []slog.Entry) bool {
+	for i := range entries {
+		switch entries[i].Level {
+		case slog.Fatal, slog.Panic:
+			return false
+		}
+	}
+
+	now := a.clock.Now()
+	jobs := make([]job, 0, len(entries))
+	for i := range entries {
+		e := &entries[i]
+
+		l, ok := e.Apply(parent).WithEnabled()
+		if !ok {
+			continue
+		}
+		if e.Time.IsZero() && ll.Time().IsZero() {
+			l = slog.WithTime(l, now)
+		}
+		jobs = append(jobs, job{logger: l, msg: e.Message})
+	}
+
+	// emitted synchronously once closed
+	return a.send(ll.Context(), jobs...)
+}
+
+// send queues jobs, giving up on each if the context is done first.
+// It returns false if the Async logger has been closed.
+func (a *Async) send(ctx context.Context, jobs ...job) bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.closed {
+		return false
+	}
+
+	var expired <-chan struct{}
+	if ctx != nil {
+		expired = ctx.Done()
+	}
+
+	for _, j := range jobs {
+		select {
+		case a.ch <- j:
+		case <-expired:
+			if j.flushed == nil {
+				a.dropped.Add(1)
+			}
+		}
+	}
+	return true
+}
+
+// Flush waits until all entries sent so far have been passed to
+// the parent, or the context is cancelled
+func (a *Async) Flush(ctx context.Context) error {
+	flushed := make(chan struct{})
+	if !a.send(ctx, job{flushed: flushed}) {
+		flushed = a.done
+	}
+
+	select {
+	case <-flushed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops accepting new entries and waits, up to
+// DefaultShutdownTimeout, for the pending ones to be passed.
+// Entries logged afterwards are passed synchronously.
+func (a *Async) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultShutdownTimeout)
+	defer cancel()
+
+	return a.Shutdown(ctx)
+}
+
+// Health returns ErrAsyncClosed once the Async logger
+// has been closed
+func (a *Async) Health() error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.closed {
+		return ErrAsyncClosed
+	}
+	return nil
+}
+
+// Stats returns the number of entries waiting to be passed,
+// and of those dropped as their context was done first
+func (a *Async) Stats() slog.Stats {
+	return slog.Stats{
+		slog.PendingStat: uint64(len(a.ch)),
+		slog.DroppedStat: a.dropped.Load(),
+	}
+}
+
+// Shutdown stops accepting new entries and waits for the pending
+// ones to be passed, or the context to be cancelled.
+func (a *Async) Shutdown(ctx context.Context) error {
+	a.mu.Lock()
+	if !a.closed {
+		a.closed = true
+		close(a.ch)
+	}
+	a.mu.Unlock()
+
+	select {
+	case <-a.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,152 @@
+// Package chain provides a builder composing slog.Logger handlers
+// into a pipeline in the right order
+package chain
+
+import (
+	"context"
+	"errors"
+
+	"darvaza.org/core"
+	"darvaza.org/slog"
+	"darvaza.org/slog/handlers/filter"
+	"darvaza.org/slog/handlers/sampling"
+)
+
+// Builder describes a pipeline of handlers in front of a parent
+// slog.Logger. Regardless of the order stages are added, entries
+// go through the filter first, then sampling, then the async queue,
+// and finally reach the parent and the other Multi loggers.
+type Builder struct {
+	parent   slog.Logger
+	multi    []slog.Logger
+	errs     []error
+	sampling *sampling.Config
+	async    *Async
+
+	asyncSize int
+	threshold slog.LogLevel
+
+	hasFilter bool
+	hasAsync  bool
+}
+
+// New starts describing a pipeline in front of the given parent
+func New(parent slog.Logger) *Builder {
+	b := &Builder{parent: parent}
+	if parent == nil {
+		b.fail("parent logger not specified")
+	}
+	return b
+}
+
+func (b *Builder) fail(msg string, args ...any) {
+	b.errs = append(b.errs, core.Wrapf(core.ErrInvalid, "chain: "+msg, args...))
+}
+
+// WithFilter discards entries less severe than the threshold
+func (b *Builder) WithFilter(threshold slog.LogLevel) *Builder {
+	switch {
+	case b.hasFilter:
+		b.fail("filter already set")
+	case threshold <= slog.UndefinedLevel || threshold > slog.Debug:
+		b.fail("invalid filter threshold %v", threshold)
+	default:
+		b.hasFilter = true
+		b.threshold = threshold
+	}
+	return b
+}
+
+// WithSampling limits repeated entries as described by the
+// sampling.Config
+func (b *Builder) WithSampling(cfg *sampling.Config) *Builder {
+	switch {
+	case b.sampling != nil:
+		b.fail("sampling already set")
+	case cfg == nil:
+		b.fail("sampling config not specified")
+	case cfg.First < 0 || cfg.Thereafter < 0:
+		b.fail("invalid sampling config")
+	default:
+		b.sampling = cfg
+	}
+	return b
+}
+
+// WithAsync passes entries to the parent on a separate goroutine,
+// holding up to size of them. Zero means DefaultAsyncBufferSize.
+func (b *Builder) WithAsync(size int) *Builder {
+	switch {
+	case b.hasAsync:
+		b.fail("async already set")
+	case size < 0:
+		b.fail("invalid async buffer size %v", size)
+	default:
+		b.hasAsync = true
+		b.asyncSize = size
+	}
+	return b
+}
+
+// WithMulti passes entries to other loggers besides the parent
+func (b *Builder) WithMulti(others ...slog.Logger) *Builder {
+	for _, l := range others {
+		if l == nil {
+			b.fail("nil logger given to multi")
+			return b
+		}
+	}
+
+	b.multi = append(b.multi, others...)
+	return b
+}
+
+// Build validates the pipeline and composes it, returning the
+// slog.Logger at its front
+func (b *Builder) Build() (slog.Logger, error) {
+	if err := errors.Join(b.errs...); err != nil {
+		return nil, err
+	}
+
+	if b.async != nil {
+		return nil, core.Wrap(core.ErrInvalid, "chain: already built")
+	}
+
+	l := b.parent
+	if len(b.multi) > 0 {
+		l = NewMulti(l, b.multi...)
+	}
+
+	if b.hasAsync {
+		b.async = NewAsync(l, b.asyncSize)
+		l = b.async.Logger()
+	}
+
+	if b.sampling != nil {
+		l = sampling.New(l, b.sampling)
+	}
+
+	if b.hasFilter {
+		l = filter.New(l, b.threshold)
+	}
+
+	return l, nil
+}
+
+// Flush waits until the entries held by the async stage, if any,
+// have been passed on, or the context is cancelled
+func (b *Builder) Flush(ctx context.Context) error {
+	if b.async == nil {
+		return nil
+	}
+	return b.async.Flush(ctx)
+}
+
+// Close stops the async stage, if any, waiting up to
+// DefaultShutdownTimeout for the pending entries
+func (b *Builder) Close() error {
+	if b.async == nil {
+		return nil
+	}
+	return b.async.Close()
+}
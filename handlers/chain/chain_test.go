@@ -0,0 +1,200 @@
+package chain
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"darvaza.org/slog"
+	"darvaza.org/slog/handlers/sampling"
+	"darvaza.org/slog/internal"
+	slogtest "darvaza.org/slog/internal/testing"
+)
+
+func flush(t *testing.T, l slog.Logger) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := slog.Flush(ctx, l); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBuild(t *testing.T) {
+	rec := slogtest.NewRecorder()
+	other := slogtest.NewRecorder()
+
+	// stages given out of order
+	b := New(rec).
+		WithAsync(0).
+		WithMulti(other).
+		WithSampling(&sampling.Config{First: 1, Tick: time.Hour}).
+		WithFilter(slog.Warn)
+	defer b.Close()
+
+	l, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l.Info().Print("filtered")
+	for i := 0; i < 3; i++ {
+		l.Warn().Print("sampled")
+	}
+	l.Error().Print("last")
+	flush(t, l)
+
+	for _, r := range []*slogtest.Recorder{rec, other} {
+		slogtest.AssertMessagesInOrder(t, r.Messages(),
+			slogtest.Expect().Level(slog.Warn).Message("sampled"),
+			slogtest.Expect().Level(slog.Error).Message("last"))
+	}
+
+	if _, err := b.Build(); err == nil {
+		t.Error("built twice")
+	}
+}
+
+func TestBuildErrors(t *testing.T) {
+	rec := slogtest.NewRecorder()
+
+	for i, b := range []*Builder{
+		New(nil),
+		New(rec).WithFilter(slog.Info).WithFilter(slog.Warn),
+		New(rec).WithFilter(slog.UndefinedLevel),
+		New(rec).WithSampling(nil),
+		New(rec).WithSampling(&sampling.Config{First: -1}),
+		New(rec).WithAsync(-1),
+		New(rec).WithAsync(1).WithAsync(1),
+		New(rec).WithMulti(rec, nil),
+	} {
+		if _, err := b.Build(); err == nil {
+			t.Errorf("#%d: expected error", i)
+		}
+	}
+}
+
+func TestMulti(t *testing.T) {
+	primary := slogtest.NewRecorder()
+	other := slogtest.NewRecorder()
+
+	if l := NewMulti(primary, nil); l != primary {
+		t.Errorf("expected the primary alone, got %T", l)
+	}
+
+	l := NewMulti(primary, other)
+	l.Info().WithField("key", "value").Print("info")
+	l.Fatal().Print("fatal")
+
+	slogtest.AssertMessagesInOrder(t, primary.Messages(),
+		slogtest.Expect().Level(slog.Info).Message("info").Field("key", "value"),
+		slogtest.Expect().Level(slog.Fatal).Message("fatal"))
+
+	// others get Fatal and Panic entries as Error
+	slogtest.AssertMessagesInOrder(t, other.Messages(),
+		slogtest.Expect().Level(slog.Info).Message("info").Field("key", "value"),
+		slogtest.Expect().Level(slog.Error).Message("fatal"))
+}
+
+func TestMultiProperties(t *testing.T) {
+	slogtest.CheckProperties(t, func(parent slog.Logger) slog.Logger {
+		return NewMulti(parent, slogtest.NewDiscardRecorder())
+	})
+}
+
+// newBlockedParent returns a logger whose entries reach the
+// Recorder once the returned channel is closed
+func newBlockedParent(rec *slogtest.Recorder) (slog.Logger, chan struct{}) {
+	release := make(chan struct{})
+	l := internal.NewWrapper(rec, &internal.WrapperHooks{
+		Print: func(*internal.Entry) bool {
+			<-release
+			return true
+		},
+	})
+	return l, release
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestAsync(t *testing.T) {
+	rec := slogtest.NewRecorder()
+	parent, release := newBlockedParent(rec)
+
+	a := NewAsync(parent, 1)
+	l := a.Logger()
+	start := time.Now()
+
+	// taken by the goroutine, blocked on the parent
+	l.Info().Print("first")
+	waitFor(t, func() bool { return a.Stats()[slog.PendingStat] == 0 })
+
+	// fills the buffer
+	l.Info().Print("second")
+
+	// given up on as its context is done
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	slog.WithContext(ctx, l.Info()).Print("third")
+
+	if n := a.Stats()[slog.DroppedStat]; n != 1 {
+		t.Errorf("expected 1 entry dropped, got %d", n)
+	}
+
+	close(release)
+	flush(t, l)
+
+	msgs := rec.Messages()
+	slogtest.AssertMessagesInOrder(t, msgs,
+		slogtest.Expect().Message("first"),
+		slogtest.Expect().Message("second"))
+	// timestamped when logged, not when passed on
+	slogtest.AssertWithin(t, msgs, start, time.Second)
+}
+
+func TestAsyncClose(t *testing.T) {
+	rec := slogtest.NewRecorder()
+	a := NewAsync(rec, 0)
+	l := a.Logger()
+
+	l.Info().Print("pending")
+	if err := slog.Close(l); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Health(); !errors.Is(err, ErrAsyncClosed) {
+		t.Errorf("expected ErrAsyncClosed, got %v", err)
+	}
+
+	// printed synchronously once closed
+	l.Info().Print("after")
+	slogtest.AssertMessagesInOrder(t, rec.Messages(),
+		slogtest.Expect().Message("pending"),
+		slogtest.Expect().Message("after"))
+}
+
+func TestAsyncFatal(t *testing.T) {
+	rec := slogtest.NewRecorder()
+	a := NewAsync(rec, 0)
+	defer a.Close()
+
+	l := a.Logger()
+	l.Info().Print("pending")
+	l.Fatal().Print("fatal")
+
+	// the pending entries are passed before the Fatal one
+	slogtest.AssertMessagesInOrder(t, rec.Messages(),
+		slogtest.Expect().Level(slog.Info).Message("pending"),
+		slogtest.Expect().Level(slog.Fatal).Message("fatal"))
+}
@@ -0,0 +1,21 @@
+module darvaza.org/slog/handlers/chain
+
+go 1.22
+
+replace (
+	darvaza.org/slog => ../../
+	darvaza.org/slog/handlers/filter => ../filter
+	darvaza.org/slog/handlers/sampling => ../sampling
+)
+
+require (
+	darvaza.org/core v0.16.0
+	darvaza.org/slog v0.6.0
+	darvaza.org/slog/handlers/filter v0.0.0
+	darvaza.org/slog/handlers/sampling v0.0.0
+)
+
+require (
+	golang.org/x/net v0.34.0 // indirect
+	golang.org/x/text v0.21.0 // indirect
+)
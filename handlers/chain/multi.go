@@ -0,0 +1,233 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"darvaza.org/core"
+	"darvaza.org/slog"
+	"darvaza.org/slog/internal"
+)
+
+var (
+	_ slog.Logger         = (*Multi)(nil)
+	_ slog.CallerLogger   = (*Multi)(nil)
+	_ slog.NamedLogger    = (*Multi)(nil)
+	_ slog.TimedLogger    = (*Multi)(nil)
+	_ slog.StackLogger    = (*Multi)(nil)
+	_ slog.ContextLogger  = (*Multi)(nil)
+	_ slog.MultiUnwrapper = (*Multi)(nil)
+)
+
+// Multi is a slog.Logger passing every entry to multiple loggers.
+// The first one is the primary, and Fatal and Panic entries reach
+// the others as Error before the primary terminates.
+type Multi struct {
+	loggers []slog.Logger
+	level   slog.LogLevel
+}
+
+// NewMulti creates a Multi logger passing entries to the primary
+// logger and to the others. Nil loggers are ignored.
+func NewMulti(primary slog.Logger, others ...slog.Logger) slog.Logger {
+	loggers := make([]slog.Logger, 0, len(others)+1)
+	for _, l := range append([]slog.Logger{primary}, others...) {
+		if l != nil {
+			loggers = append(loggers, l)
+		}
+	}
+
+	switch len(loggers) {
+	case 0:
+		return nil
+	case 1:
+		return loggers[0]
+	default:
+		return &Multi{loggers: loggers}
+	}
+}
+
+func (m *Multi) dup(fn func(slog.Logger) slog.Logger) *Multi {
+	out := &Multi{
+		loggers: make([]slog.Logger, len(m.loggers)),
+		level:   m.level,
+	}
+	for i, l := range m.loggers {
+		out.loggers[i] = fn(l)
+	}
+	return out
+}
+
+// Unwrap returns the loggers entries are passed to, so
+// slog.Close() and slog.Flush() can walk the chain
+func (m *Multi) Unwrap() []slog.Logger {
+	return m.loggers
+}
+
+// Enabled tells if any of the loggers is enabled
+func (m *Multi) Enabled() bool {
+	if m == nil {
+		return false
+	}
+
+	for _, l := range m.loggers {
+		if l.Enabled() {
+			return true
+		}
+	}
+	return false
+}
+
+// WithEnabled passes the logger and if it's enabled
+func (m *Multi) WithEnabled() (slog.Logger, bool) {
+	return m, m.Enabled()
+}
+
+// Print adds a log entry with arguments handled in the manner of fmt.Print
+func (m *Multi) Print(args ...any) {
+	m.print(fmt.Sprint(args...))
+}
+
+// Println adds a log entry with arguments handled in the manner of fmt.Println
+func (m *Multi) Println(args ...any) {
+	m.print(fmt.Sprintln(args...))
+}
+
+// Printf adds a log entry with arguments handled in the manner of fmt.Printf
+func (m *Multi) Printf(format string, args ...any) {
+	m.print(internal.Sprintf(format, args...))
+}
+
+func (m *Multi) print(msg string) {
+	// the primary last, as it may terminate
+	for _, l := range m.loggers[1:] {
+		l.Print(msg)
+	}
+	m.loggers[0].Print(msg)
+}
+
+// Debug returns a new logger set to add entries as level Debug
+func (m *Multi) Debug() slog.Logger {
+	return m.WithLevel(slog.Debug)
+}
+
+// Info returns a new logger set to add entries as level Info
+func (m *Multi) Info() slog.Logger {
+	return m.WithLevel(slog.Info)
+}
+
+// Warn returns a new logger set to add entries as level Warn
+func (m *Multi) Warn() slog.Logger {
+	return m.WithLevel(slog.Warn)
+}
+
+// Error returns a new logger set to add entries as level Error
+func (m *Multi) Error() slog.Logger {
+	return m.WithLevel(slog.Error)
+}
+
+// Fatal returns a new logger set to add entries as level Fatal
+func (m *Multi) Fatal() slog.Logger {
+	return m.WithLevel(slog.Fatal)
+}
+
+// Panic returns a new logger set to add entries as level Panic
+func (m *Multi) Panic() slog.Logger {
+	return m.WithLevel(slog.Panic)
+}
+
+// WithLevel returns a new logger set to add entries to the specified level
+func (m *Multi) WithLevel(level slog.LogLevel) slog.Logger {
+	if level <= slog.UndefinedLevel {
+		// fix your code
+		m.Panic().WithStack(1).Printf("slog: invalid log level %v", level)
+	} else if level == m.level {
+		return m
+	}
+
+	others := level
+	if level < slog.Error {
+		others = slog.Error
+	}
+
+	out := &Multi{
+		loggers: make([]slog.Logger, len(m.loggers)),
+		level:   level,
+	}
+	out.loggers[0] = m.loggers[0].WithLevel(level)
+	for i, l := range m.loggers[1:] {
+		out.loggers[i+1] = l.WithLevel(others)
+	}
+	return out
+}
+
+// WithStack attaches a call stack to a new logger
+func (m *Multi) WithStack(skip int) slog.Logger {
+	return m.dup(func(l slog.Logger) slog.Logger {
+		return l.WithStack(skip + 3)
+	})
+}
+
+// WithCallStack attaches a given call stack to a new logger
+func (m *Multi) WithCallStack(st core.Stack) slog.Logger {
+	return m.dup(func(l slog.Logger) slog.Logger {
+		return slog.WithCallStack(l, st)
+	})
+}
+
+// WithContext records the context entries are logged on
+// behalf of on a new logger
+func (m *Multi) WithContext(ctx context.Context) slog.Logger {
+	return m.dup(func(l slog.Logger) slog.Logger {
+		return slog.WithContext(ctx, l)
+	})
+}
+
+// WithCaller attaches the immediate caller to a new logger
+func (m *Multi) WithCaller(skip int) slog.Logger {
+	return m.dup(func(l slog.Logger) slog.Logger {
+		return slog.WithCaller(l, skip+3)
+	})
+}
+
+// WithTime records when the event happened on a new logger
+func (m *Multi) WithTime(t time.Time) slog.Logger {
+	return m.dup(func(l slog.Logger) slog.Logger {
+		return slog.WithTime(l, t)
+	})
+}
+
+// WithName appends a component to the name of a new logger
+func (m *Multi) WithName(name string) slog.Logger {
+	if name == "" {
+		return m
+	}
+
+	return m.dup(func(l slog.Logger) slog.Logger {
+		return slog.WithName(l, name)
+	})
+}
+
+// WithField returns a new logger with a field attached
+func (m *Multi) WithField(label string, value any) slog.Logger {
+	if label == "" {
+		return m
+	}
+
+	return m.dup(func(l slog.Logger) slog.Logger {
+		return l.WithField(label, value)
+	})
+}
+
+// WithFields returns a new logger with a set of fields attached
+func (m *Multi) WithFields(fields map[string]any) slog.Logger {
+	delete(fields, "")
+	if len(fields) == 0 {
+		return m
+	}
+
+	return m.dup(func(l slog.Logger) slog.Logger {
+		return l.WithFields(fields)
+	})
+}
@@ -4,18 +4,19 @@ package discard
 import (
 	"fmt"
 	"log"
-	"os"
 	"strings"
 
 	"darvaza.org/slog"
 )
 
 var (
-	_ slog.Logger = (*Logger)(nil)
+	_ slog.Logger      = (*Logger)(nil)
+	_ slog.NamedLogger = (*Logger)(nil)
 )
 
 // Logger implements slog.Logger but doesn't log anything
 type Logger struct {
+	stats *Stats
 	level slog.LogLevel
 }
 
@@ -38,6 +39,8 @@ func (nl *Logger) WithEnabled() (slog.Logger, bool) {
 func (nl *Logger) Print(args ...any) {
 	if nl.Enabled() {
 		nl.print(fmt.Sprint(args...))
+	} else if nl.counting() {
+		nl.stats.record(nl.level, fmt.Sprint(args...))
 	}
 }
 
@@ -45,6 +48,8 @@ func (nl *Logger) Print(args ...any) {
 func (nl *Logger) Println(args ...any) {
 	if nl.Enabled() {
 		nl.print(fmt.Sprintln(args...))
+	} else if nl.counting() {
+		nl.stats.record(nl.level, fmt.Sprintln(args...))
 	}
 }
 
@@ -52,9 +57,16 @@ func (nl *Logger) Println(args ...any) {
 func (nl *Logger) Printf(format string, args ...any) {
 	if nl.Enabled() {
 		nl.print(fmt.Sprintf(format, args...))
+	} else if nl.counting() {
+		nl.stats.record(nl.level, fmt.Sprintf(format, args...))
 	}
 }
 
+// counting tells if discarded entries are counted
+func (nl *Logger) counting() bool {
+	return nl != nil && nl.stats != nil
+}
+
 // revive:disable:confusing-naming
 func (nl *Logger) print(msg string) {
 	msg = strings.TrimSpace(msg)
@@ -63,23 +75,31 @@ func (nl *Logger) print(msg string) {
 	if nl.level != slog.Fatal {
 		panic(msg)
 	}
-	// revive:disable:deep-exit
-	os.Exit(1)
+	slog.Exit(1)
 }
 
 // revive:enable:confusing-naming
 
 // Debug pretends to return a new NOOP logger
-func (nl *Logger) Debug() slog.Logger { return nl }
+func (nl *Logger) Debug() slog.Logger { return nl.discarded(slog.Debug) }
 
 // Info pretends to return a new NOOP logger
-func (nl *Logger) Info() slog.Logger { return nl }
+func (nl *Logger) Info() slog.Logger { return nl.discarded(slog.Info) }
 
 // Warn pretends to return a new NOOP logger
-func (nl *Logger) Warn() slog.Logger { return nl }
+func (nl *Logger) Warn() slog.Logger { return nl.discarded(slog.Warn) }
 
 // Error pretends to return a new NOOP logger
-func (nl *Logger) Error() slog.Logger { return nl }
+func (nl *Logger) Error() slog.Logger { return nl.discarded(slog.Error) }
+
+// discarded returns the shared logger of the level when
+// counting, or itself otherwise
+func (nl *Logger) discarded(level slog.LogLevel) slog.Logger {
+	if nl.counting() {
+		return nl.stats.logger(level)
+	}
+	return nl
+}
 
 // Fatal return a new Fatal logger
 func (nl *Logger) Fatal() slog.Logger {
@@ -97,13 +117,21 @@ func (nl *Logger) WithLevel(level slog.LogLevel) slog.Logger {
 	if level <= slog.UndefinedLevel {
 		// fix your code
 		nl.Panic().Printf("slog: invalid log level %v", level)
+	} else if nl.counting() {
+		if level > slog.Fatal && level <= slog.Debug {
+			return nl.stats.logger(level)
+		}
+		return &Logger{level: level, stats: nl.stats}
 	}
-	return &Logger{level}
+	return &Logger{level: level}
 }
 
 // WithStack pretends to attach a call stack to the logger
 func (nl *Logger) WithStack(int) slog.Logger { return nl }
 
+// WithName pretends to name the Logger
+func (nl *Logger) WithName(string) slog.Logger { return nl }
+
 // WithField pretends to add a fields to the Logger
 func (nl *Logger) WithField(string, any) slog.Logger { return nl }
 
@@ -112,3 +140,10 @@ func (nl *Logger) WithFields(map[string]any) slog.Logger { return nl }
 
 // New creates a slog.Logger that doesn't really log anything
 func New() slog.Logger { return &Logger{} }
+
+// NewWithStats creates a slog.Logger that doesn't really log
+// anything, but counts what it discards
+func NewWithStats() (slog.Logger, *Stats) {
+	s := newStats()
+	return &Logger{stats: s}, s
+}
@@ -0,0 +1,125 @@
+package discard
+
+import (
+	"bytes"
+	"log"
+	"testing"
+
+	"darvaza.org/slog"
+)
+
+// captureLog redirects the standard logger for the duration of the test
+func captureLog(t *testing.T) *bytes.Buffer {
+	var buf bytes.Buffer
+
+	flags, out := log.Flags(), log.Writer()
+	log.SetFlags(0)
+	log.SetOutput(&buf)
+	t.Cleanup(func() {
+		log.SetFlags(flags)
+		log.SetOutput(out)
+	})
+	return &buf
+}
+
+func TestDiscard(t *testing.T) {
+	buf := captureLog(t)
+	l := New()
+
+	for _, ll := range []slog.Logger{l, l.Debug(), l.Info(), l.Warn(), l.Error()} {
+		if ll.Enabled() {
+			t.Error("unexpected enabled logger")
+		}
+		ll.WithField("k", "v").WithStack(0).Print("discarded")
+	}
+	if buf.Len() != 0 {
+		t.Errorf("unexpected output %q", buf)
+	}
+}
+
+func TestFatal(t *testing.T) {
+	buf := captureLog(t)
+
+	var code int
+	slog.SetExitFunc(func(c int) { code = c })
+	t.Cleanup(func() { slog.SetExitFunc(nil) })
+
+	l := New().Fatal()
+	if !l.Enabled() {
+		t.Fatal("Fatal logger not enabled")
+	}
+	l.Printf("fatal %d", 1)
+
+	if code != 1 || buf.String() != "fatal 1\n" {
+		t.Errorf("unexpected exit code %d and output %q", code, buf)
+	}
+}
+
+func TestPanic(t *testing.T) {
+	buf := captureLog(t)
+
+	defer func() {
+		if r := recover(); r != "panic" {
+			t.Errorf("unexpected panic %v", r)
+		}
+		if buf.String() != "panic\n" {
+			t.Errorf("unexpected output %q", buf)
+		}
+	}()
+
+	New().Panic().Println("panic")
+}
+
+func TestStats(t *testing.T) {
+	l, stats := NewWithStats()
+
+	l.Print("no level")
+	l.Info().Print("first")
+	l.Info().WithField("k", "v").Printf("%s", "second")
+	l.Debug().Println("debug")
+	l.WithLevel(slog.Warn).Print("warn")
+
+	if n := stats.Entries(slog.UndefinedLevel); n != 1 {
+		t.Errorf("expected 1 entry without level, got %d", n)
+	}
+	if n := stats.Entries(slog.Info); n != 2 {
+		t.Errorf("expected 2 Info entries, got %d", n)
+	}
+	if n := stats.Bytes(slog.Info); n != uint64(len("first")+len("second")) {
+		t.Errorf("unexpected Info size %d", n)
+	}
+	// trimmed
+	if n := stats.Bytes(slog.Debug); n != uint64(len("debug")) {
+		t.Errorf("unexpected Debug size %d", n)
+	}
+	if n := stats.Entries(slog.LogLevel(42)); n != 0 {
+		t.Errorf("unexpected %d entries on an invalid level", n)
+	}
+
+	if entries, _ := stats.Total(); entries != 5 {
+		t.Errorf("expected 5 entries, got %d", entries)
+	}
+
+	stats.Reset()
+	if entries, bytes := stats.Total(); entries != 0 || bytes != 0 {
+		t.Errorf("unexpected %d entries and %d bytes after Reset", entries, bytes)
+	}
+
+	// a nil Stats is valid
+	var none *Stats
+	if entries, _ := none.Total(); entries != 0 || none.Entries(slog.Info) != 0 {
+		t.Error("unexpected entries on nil Stats")
+	}
+}
+
+func TestStatsShared(t *testing.T) {
+	l, _ := NewWithStats()
+
+	// deriving doesn't allocate
+	if l.Info() != l.Info().Info() {
+		t.Error("expected the shared Info logger")
+	}
+	if n := testing.AllocsPerRun(10, func() { _ = l.Debug().Warn() }); n != 0 {
+		t.Errorf("unexpected %v allocations", n)
+	}
+}
@@ -0,0 +1,86 @@
+package discard
+
+import (
+	"strings"
+	"sync/atomic"
+
+	"darvaza.org/slog"
+)
+
+// Stats counts the entries discarded by a Logger, and the
+// size of their messages, per level.
+// Entries skipped by the caller because the Logger isn't
+// Enabled() aren't counted.
+type Stats struct {
+	loggers [slog.Debug + 1]Logger
+	entries [slog.Debug + 1]atomic.Uint64
+	bytes   [slog.Debug + 1]atomic.Uint64
+}
+
+func newStats() *Stats {
+	s := new(Stats)
+	for i := range s.loggers {
+		s.loggers[i] = Logger{
+			stats: s,
+			level: slog.LogLevel(i),
+		}
+	}
+	return s
+}
+
+// logger returns the shared Logger of a level
+func (s *Stats) logger(level slog.LogLevel) *Logger {
+	return &s.loggers[level]
+}
+
+func (s *Stats) record(level slog.LogLevel, msg string) {
+	if s.valid(level) {
+		s.entries[level].Add(1)
+		s.bytes[level].Add(uint64(len(strings.TrimSpace(msg))))
+	}
+}
+
+func (*Stats) valid(level slog.LogLevel) bool {
+	return level >= slog.UndefinedLevel && level <= slog.Debug
+}
+
+// Entries returns the number of entries discarded at the given
+// level. Entries printed before choosing a level are counted
+// as slog.UndefinedLevel.
+func (s *Stats) Entries(level slog.LogLevel) uint64 {
+	if s == nil || !s.valid(level) {
+		return 0
+	}
+	return s.entries[level].Load()
+}
+
+// Bytes returns the total size of the messages discarded at
+// the given level
+func (s *Stats) Bytes(level slog.LogLevel) uint64 {
+	if s == nil || !s.valid(level) {
+		return 0
+	}
+	return s.bytes[level].Load()
+}
+
+// Total returns the number of entries discarded and the
+// total size of their messages, across all levels
+func (s *Stats) Total() (entries, bytes uint64) {
+	if s != nil {
+		for i := range s.entries {
+			entries += s.entries[i].Load()
+			bytes += s.bytes[i].Load()
+		}
+	}
+	return entries, bytes
+}
+
+// Reset sets all counters to zero
+func (s *Stats) Reset() {
+	if s != nil {
+		for i := range s.entries {
+			s.entries[i].Store(0)
+			s.bytes[i].Store(0)
+		}
+	}
+}
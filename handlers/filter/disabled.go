@@ -0,0 +1,38 @@
+package filter
+
+import "darvaza.org/slog"
+
+// disabledEntries are the LogEntry values returned for
+// levels above the Threshold, so disabled entries don't
+// allocate. They check the Threshold again when used, and
+// behave like any other LogEntry if it has been lowered.
+type disabledEntries [slog.Debug + 1]LogEntry
+
+// disabledEntry returns the shared LogEntry for the given level
+func (l *Logger) disabledEntry(level slog.LogLevel) *LogEntry {
+	entries := l.disabled.Load()
+	if entries == nil {
+		entries = new(disabledEntries)
+		for i := range entries {
+			entries[i] = LogEntry{
+				logger: l,
+				level:  slog.LogLevel(i),
+				shared: true,
+			}
+		}
+
+		if !l.disabled.CompareAndSwap(nil, entries) {
+			entries = l.disabled.Load()
+		}
+	}
+	return &entries[level]
+}
+
+// live returns a new logger for shared entries whose
+// level has been enabled since they were created
+func (l *LogEntry) live() (slog.Logger, bool) {
+	if l.shared && l.logger.Peek(l.level) {
+		return l.logger.WithLevel(l.level), true
+	}
+	return nil, false
+}
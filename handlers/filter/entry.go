@@ -1,24 +1,63 @@
 package filter
 
 import (
+	"context"
 	"fmt"
 	"log"
-	"os"
+	"time"
 
 	"darvaza.org/core"
 	"darvaza.org/slog"
+	"darvaza.org/slog/internal"
 )
 
 var (
-	_ slog.Logger = (*LogEntry)(nil)
+	_ slog.Logger        = (*LogEntry)(nil)
+	_ slog.NamedLogger   = (*LogEntry)(nil)
+	_ slog.TimedLogger   = (*LogEntry)(nil)
+	_ slog.StackLogger   = (*LogEntry)(nil)
+	_ slog.ContextLogger = (*LogEntry)(nil)
+	_ slog.Unwrapper     = (*LogEntry)(nil)
+	_ slog.Peeker        = (*LogEntry)(nil)
 )
 
 // LogEntry implements a level filtered logger
 type LogEntry struct {
+	// loglet records fields and stack for the EntryFilter
+	loglet internal.Loglet
+
 	logger *Logger
+	entry  slog.Logger
+	level  slog.LogLevel
+
+	// stacked tells a call stack has been attached
+	stacked bool
+
+	// shared marks the entries of levels above the Threshold
+	// when they were requested, which have no parent entry
+	shared bool
+}
+
+// with returns a copy of the LogEntry using a new parent entry
+func (l *LogEntry) with(entry slog.Logger) *LogEntry {
+	out := *l
+	out.entry = entry
+	return &out
+}
 
-	level slog.LogLevel
-	entry slog.Logger
+// Unwrap returns the parent logger, so slog.Close() and
+// slog.Flush() can walk the chain
+func (l *LogEntry) Unwrap() slog.Logger {
+	if l == nil {
+		return nil
+	}
+	return l.entry
+}
+
+// recording tells if fields and stack need to be recorded
+// for the EntryFilter
+func (l *LogEntry) recording() bool {
+	return l.logger.EntryFilter != nil
 }
 
 // Enabled tells this logger would record logs
@@ -26,7 +65,10 @@ func (l *LogEntry) Enabled() bool {
 	if l == nil || l.logger == nil {
 		return false
 	}
-	if l.level <= slog.UndefinedLevel || l.level > l.logger.Threshold {
+	if l.shared {
+		return l.logger.Peek(l.level)
+	}
+	if l.level <= slog.UndefinedLevel || l.level > l.logger.CurrentThreshold() {
 		return false
 	}
 	return l.entry == nil || l.entry.Enabled()
@@ -40,7 +82,9 @@ func (l *LogEntry) WithEnabled() (slog.Logger, bool) {
 // Print would, if conditions are met, add a log entry with the arguments
 // in the manner of fmt.Print
 func (l *LogEntry) Print(args ...any) {
-	if l.Enabled() {
+	if next, ok := l.live(); ok {
+		next.Print(args...)
+	} else if l.Enabled() {
 		l.msg(fmt.Sprint(args...))
 	}
 }
@@ -48,7 +92,9 @@ func (l *LogEntry) Print(args ...any) {
 // Println would, if conditions are met, add a log entry with the arguments
 // in the manner of fmt.Println
 func (l *LogEntry) Println(args ...any) {
-	if l.Enabled() {
+	if next, ok := l.live(); ok {
+		next.Println(args...)
+	} else if l.Enabled() {
 		l.msg(fmt.Sprintln(args...))
 	}
 }
@@ -56,8 +102,10 @@ func (l *LogEntry) Println(args ...any) {
 // Printf would, if conditions are met, add a log entry with the arguments
 // in the manner of fmt.Printf
 func (l *LogEntry) Printf(format string, args ...any) {
-	if l.Enabled() {
-		l.msg(fmt.Sprintf(format, args...))
+	if next, ok := l.live(); ok {
+		next.Printf(format, args...)
+	} else if l.Enabled() {
+		l.msg(internal.Sprintf(format, args...))
 	}
 }
 
@@ -73,6 +121,21 @@ func (l *LogEntry) msg(msg string) {
 		}
 	}
 
+	if l.needsStack() {
+		// skip msg and Print
+		l = l.addCallStack(l.logger.trimStack(core.StackTrace(2)))
+	}
+
+	if fn := l.logger.EntryFilter; fn != nil {
+		fields := l.loglet.AcquireFieldsMap()
+		ok := fn(l.level, msg, fields, l.loglet.CallStack())
+		internal.ReleaseFieldsMap(fields)
+
+		if !ok {
+			return
+		}
+	}
+
 	if l.entry == nil {
 		// parentless is either Fatal or Panic
 		_ = log.Output(3, msg)
@@ -81,13 +144,27 @@ func (l *LogEntry) msg(msg string) {
 			panic(msg)
 		}
 
-		// revive:disable:deep-exit
-		os.Exit(1)
+		slog.Exit(1)
 	}
 
 	l.entry.Print(msg)
 }
 
+// needsStack tells if a call stack needs to be attached
+// because of the StackLevel
+func (l *LogEntry) needsStack() bool {
+	return !l.stacked && l.entry != nil && l.level <= l.logger.StackLevel
+}
+
+// Peek tells if entries of the given level would be logged,
+// without creating a logger for it
+func (l *LogEntry) Peek(level slog.LogLevel) bool {
+	if l == nil || l.logger == nil {
+		return false
+	}
+	return l.logger.Peek(level)
+}
+
 // Debug creates a new filtered logger on level slog.Debug
 func (l *LogEntry) Debug() slog.Logger {
 	return l.logger.WithLevel(slog.Debug)
@@ -123,10 +200,93 @@ func (l *LogEntry) WithLevel(level slog.LogLevel) slog.Logger {
 	return l.logger.WithLevel(level)
 }
 
-// WithStack would, if conditions are met, attach a call stack to the log entry
+// WithStack would, if conditions are met, attach a call stack to the log entry,
+// limited by MaxStackDepth and StackTrimPrefixes
 func (l *LogEntry) WithStack(skip int) slog.Logger {
+	if next, ok := l.live(); ok {
+		return next.WithStack(skip + 1)
+	}
+
+	if l.Enabled() && l.entry != nil {
+		if l.logger.trimsStack() {
+			return l.addCallStack(l.logger.trimStack(core.StackTrace(skip + 1)))
+		}
+
+		out := l.with(l.entry.WithStack(skip + 1))
+		out.stacked = true
+		if l.recording() {
+			out.loglet = l.loglet.WithStack(skip + 1)
+		}
+		return out
+	}
+	return l
+}
+
+// WithCallStack would, if conditions are met, attach a given call
+// stack to the log entry, limited by MaxStackDepth and StackTrimPrefixes
+func (l *LogEntry) WithCallStack(st core.Stack) slog.Logger {
+	if next, ok := l.live(); ok {
+		return slog.WithCallStack(next, st)
+	}
+
 	if l.Enabled() && l.entry != nil {
-		l.entry.WithStack(skip + 1)
+		return l.addCallStack(l.logger.trimStack(st))
+	}
+	return l
+}
+
+func (l *LogEntry) addCallStack(st core.Stack) *LogEntry {
+	out := l.with(slog.WithCallStack(l.entry, st))
+	out.stacked = true
+	if l.recording() {
+		out.loglet = l.loglet.WithCallStack(st)
+	}
+	return out
+}
+
+// WithTime would, if conditions are met, record when the event
+// happened on the log entry
+func (l *LogEntry) WithTime(t time.Time) slog.Logger {
+	if next, ok := l.live(); ok {
+		return slog.WithTime(next, t)
+	}
+
+	if l.Enabled() && l.entry != nil {
+		out := l.with(slog.WithTime(l.entry, t))
+		if l.recording() {
+			out.loglet = l.loglet.WithTime(t)
+		}
+		return out
+	}
+	return l
+}
+
+// WithContext would, if conditions are met, record the context
+// entries are logged on behalf of on the log entry
+func (l *LogEntry) WithContext(ctx context.Context) slog.Logger {
+	if next, ok := l.live(); ok {
+		return slog.WithContext(ctx, next)
+	}
+
+	if ctx != nil && l.Enabled() && l.entry != nil {
+		return l.with(slog.WithContext(ctx, l.entry))
+	}
+	return l
+}
+
+// WithName would, if conditions are met, append a component to the
+// name of the log entry
+func (l *LogEntry) WithName(name string) slog.Logger {
+	if next, ok := l.live(); ok {
+		return slog.WithName(next, name)
+	}
+
+	if name != "" && l.Enabled() && l.entry != nil {
+		out := l.with(slog.WithName(l.entry, name))
+		if l.recording() {
+			out.loglet = l.loglet.WithName(name)
+		}
+		return out
 	}
 	return l
 }
@@ -134,23 +294,32 @@ func (l *LogEntry) WithStack(skip int) slog.Logger {
 // WithField would, if conditions are met, attach a field to the log entry. This
 // field could be altered if a FieldFilter is used
 func (l *LogEntry) WithField(label string, value any) slog.Logger {
+	if next, ok := l.live(); ok {
+		return next.WithField(label, value)
+	}
+
 	if label != "" && l.Enabled() && l.entry != nil {
-		l.addField(label, value)
+		return l.addField(label, value)
 	}
 	return l
 }
 
-func (l *LogEntry) addField(label string, value any) {
+func (l *LogEntry) addField(label string, value any) *LogEntry {
+	if fs := l.logger.fieldSet(); fs != nil && !fs.Pass(label) {
+		// denied
+		return l
+	}
+
 	if fn := l.logger.FieldOverride; fn != nil {
 		// intercepted
 		fn(l.entry, label, value)
-		return
+		return l
 	}
 
 	if fn := l.logger.FieldsOverride; fn != nil {
 		// intercepted
 		fn(l.entry, slog.Fields{label: value})
-		return
+		return l
 	}
 
 	if fn := l.logger.FieldFilter; fn != nil {
@@ -159,29 +328,44 @@ func (l *LogEntry) addField(label string, value any) {
 		label, value, ok = fn(label, value)
 
 		if !ok {
-			return
+			return l
 		}
 	}
 
-	l.entry.WithField(label, value)
+	out := l.with(l.entry.WithField(label, value))
+	if l.recording() {
+		out.loglet = l.loglet.WithField(label, value)
+	}
+	return out
 }
 
 // WithFields would, if conditions are met, attach fields to the log entry.
 // These fields could be altered if a FieldFilter is used
 func (l *LogEntry) WithFields(fields map[string]any) slog.Logger {
+	if next, ok := l.live(); ok {
+		return next.WithFields(fields)
+	}
+
 	if len(fields) > 0 && l.Enabled() && l.entry != nil {
 		delete(fields, "")
 
-		l.addFields(fields)
+		return l.addFields(fields)
 	}
 	return l
 }
 
-func (l *LogEntry) addFields(fields map[string]any) {
+func (l *LogEntry) addFields(fields map[string]any) *LogEntry {
+	if fs := l.logger.fieldSet(); fs != nil {
+		fields = fs.Filter(fields)
+		if len(fields) == 0 {
+			return l
+		}
+	}
+
 	if fn := l.logger.FieldsOverride; fn != nil {
 		// intercepted
 		fn(l.entry, fields)
-		return
+		return l
 	}
 
 	if fn := l.logger.FieldOverride; fn != nil {
@@ -189,7 +373,7 @@ func (l *LogEntry) addFields(fields map[string]any) {
 		for _, key := range core.SortedKeys(fields) {
 			fn(l.entry, key, fields[key])
 		}
-		return
+		return l
 	}
 
 	if fn := l.logger.FieldFilter; fn != nil {
@@ -197,7 +381,11 @@ func (l *LogEntry) addFields(fields map[string]any) {
 		fields = modifyFields(fields, fn)
 	}
 
-	l.entry.WithFields(fields)
+	out := l.with(l.entry.WithFields(fields))
+	if l.recording() {
+		out.loglet = l.loglet.WithFields(fields)
+	}
+	return out
 }
 
 func modifyFields(fields map[string]any, fn func(string, any) (string, any, bool)) map[string]any {
@@ -0,0 +1,80 @@
+package filter
+
+import "darvaza.org/slog"
+
+// fieldSet is the compiled form of AllowFields and DenyFields
+type fieldSet struct {
+	allow map[string]struct{}
+	deny  map[string]struct{}
+	keys  []string
+}
+
+func newFieldSet(allow, deny []string) *fieldSet {
+	return &fieldSet{
+		allow: newSet(allow),
+		deny:  newSet(deny),
+		keys:  allow,
+	}
+}
+
+func newSet(keys []string) map[string]struct{} {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	m := make(map[string]struct{}, len(keys))
+	for _, k := range keys {
+		m[k] = struct{}{}
+	}
+	return m
+}
+
+// Pass tells if a field with the given key is allowed
+func (fs *fieldSet) Pass(key string) bool {
+	if fs.allow != nil {
+		if _, ok := fs.allow[key]; !ok {
+			return false
+		}
+	}
+
+	_, denied := fs.deny[key]
+	return !denied
+}
+
+// Filter returns the fields allowed, without modifying
+// the given map, which is returned as is if all pass
+func (fs *fieldSet) Filter(fields map[string]any) map[string]any {
+	out := slog.Fields(fields)
+	if fs.allow != nil {
+		out = out.Filtered(fs.keys...)
+	}
+
+	shared := len(out) == len(fields)
+	for k := range fs.deny {
+		if _, ok := out[k]; ok {
+			if shared {
+				out, shared = out.Clone(), false
+			}
+			delete(out, k)
+		}
+	}
+	return out
+}
+
+// fieldSet returns the compiled AllowFields and DenyFields,
+// or nil if neither is used
+func (l *Logger) fieldSet() *fieldSet {
+	if len(l.AllowFields) == 0 && len(l.DenyFields) == 0 {
+		return nil
+	}
+
+	if fs := l.fields.Load(); fs != nil {
+		return fs
+	}
+
+	fs := newFieldSet(l.AllowFields, l.DenyFields)
+	if !l.fields.CompareAndSwap(nil, fs) {
+		fs = l.fields.Load()
+	}
+	return fs
+}
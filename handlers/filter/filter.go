@@ -2,11 +2,18 @@
 package filter
 
 import (
+	"sync/atomic"
+
+	"darvaza.org/core"
 	"darvaza.org/slog"
+	"darvaza.org/slog/internal"
 )
 
 var (
-	_ slog.Logger = (*Logger)(nil)
+	_ slog.Logger         = (*Logger)(nil)
+	_ slog.NamedLogger    = (*Logger)(nil)
+	_ slog.MultiUnwrapper = (*Logger)(nil)
+	_ slog.Peeker         = (*Logger)(nil)
 )
 
 // Logger implements a factory for level filtered loggers
@@ -14,13 +21,39 @@ type Logger struct {
 	// Parent is the Logger to used as backend when conditions are met
 	Parent slog.Logger
 
-	// Threshold is the minimum level to be logged
+	// Threshold is the initial minimum level to be logged. Loggers
+	// created for levels above it are shared, and check it again
+	// when used. Use SetThreshold() to change it once the Logger
+	// is in use, and CurrentThreshold() to read it.
 	Threshold slog.LogLevel
 
+	// StackLevel, if set, attaches call stacks to entries of
+	// that level or more severe which don't have one already,
+	// e.g. slog.Error
+	StackLevel slog.LogLevel
+
+	// threshold holds the level set by SetThreshold() plus one,
+	// or zero if the Threshold field hasn't been replaced
+	threshold atomic.Int32
+
+	// Routes send ranges of levels to different parent loggers,
+	// taking precedence over Parent. A Route without Parent is
+	// treated as parentless.
+	Routes []Route
+
 	// FieldFilter allows us to modify filters before passing them
 	// to the Parent logger
 	FieldFilter func(key string, val any) (string, any, bool)
 
+	// AllowFields, if not empty, lists the only fields passed to
+	// the Parent logger
+	AllowFields []string
+
+	// DenyFields lists fields never passed to the Parent logger.
+	// AllowFields and DenyFields are compiled on first use and
+	// applied before any other field filter or override.
+	DenyFields []string
+
 	// FieldOverride intercepts calls to WithField() on enabled loggers
 	// to let you transform the field
 	FieldOverride func(entry slog.Logger, key string, val any)
@@ -32,6 +65,74 @@ type Logger struct {
 	// MessageFilter allows us to modify Print() messages before passing
 	// them to the Parent logger, on completely discard the entry
 	MessageFilter func(msg string) (string, bool)
+
+	// EntryFilter sees the complete entry, after MessageFilter and
+	// FieldFilter, and decides if it's passed to the Parent logger.
+	// Fields attached via FieldOverride or FieldsOverride aren't seen.
+	// The fields map is pooled and can't be retained after returning.
+	EntryFilter func(level slog.LogLevel, msg string, fields map[string]any, stack core.Stack) bool
+
+	// MaxStackDepth, if positive, limits the number of frames of
+	// the call stacks attached by WithStack()
+	MaxStackDepth int
+
+	// StackTrimPrefixes removes from the call stacks attached by
+	// WithStack() the frames of functions whose full name starts
+	// with any of them, e.g. "runtime." or "testing."
+	StackTrimPrefixes []string
+
+	fields    atomic.Pointer[fieldSet]
+	disabled  atomic.Pointer[disabledEntries]
+	observers internal.Observers[func(old, level slog.LogLevel)]
+}
+
+// CurrentThreshold returns the minimum level to be logged,
+// as set by SetThreshold() or initially by the Threshold field
+func (l *Logger) CurrentThreshold() slog.LogLevel {
+	return l.decodeThreshold(l.threshold.Load())
+}
+
+func (l *Logger) decodeThreshold(v int32) slog.LogLevel {
+	if v == 0 {
+		return l.Threshold
+	}
+	return slog.LogLevel(v - 1)
+}
+
+// SetThreshold changes the Threshold atomically, calling the
+// functions registered using OnThresholdChange() if it's different
+func (l *Logger) SetThreshold(level slog.LogLevel) {
+	old := l.decodeThreshold(l.threshold.Swap(int32(level) + 1))
+	if level == old {
+		return
+	}
+
+	l.observers.Each(func(fn func(old, level slog.LogLevel)) {
+		fn(old, level)
+	})
+}
+
+// OnThresholdChange registers a function to be called when
+// SetThreshold() changes the Threshold, returning a function
+// to unregister it
+func (l *Logger) OnThresholdChange(fn func(old, level slog.LogLevel)) (remove func()) {
+	if fn == nil {
+		return func() {}
+	}
+	return l.observers.Add(fn)
+}
+
+// trimsStack tells if call stacks need to be trimmed
+func (l *Logger) trimsStack() bool {
+	return l.MaxStackDepth > 0 || len(l.StackTrimPrefixes) > 0
+}
+
+// trimStack applies MaxStackDepth and StackTrimPrefixes
+func (l *Logger) trimStack(st core.Stack) core.Stack {
+	if l.trimsStack() {
+		return slog.TrimStack(st, l.MaxStackDepth, l.StackTrimPrefixes...)
+	}
+	return st
 }
 
 // Enabled tells this logger doesn't log anything, but WithLevel() might
@@ -71,6 +172,22 @@ func (l *Logger) Fatal() slog.Logger { return l.WithLevel(slog.Fatal) }
 // Panic returns a filtered logger on level slog.Panic
 func (l *Logger) Panic() slog.Logger { return l.WithLevel(slog.Panic) }
 
+// Peek tells if entries of the given level would be logged, without
+// creating a logger for it, consulting the parent using slog.Peek()
+func (l *Logger) Peek(level slog.LogLevel) bool {
+	switch {
+	case level <= slog.UndefinedLevel, level > l.CurrentThreshold():
+		return false
+	}
+
+	if parent := l.parentFor(level); parent != nil {
+		return slog.Peek(parent, level)
+	}
+
+	// parentless Fatal and Panic are written anyway
+	return level <= slog.Fatal
+}
+
 // WithLevel returns a filtered logger set to the given level
 func (l *Logger) WithLevel(level slog.LogLevel) slog.Logger {
 	var entry slog.Logger
@@ -78,8 +195,11 @@ func (l *Logger) WithLevel(level slog.LogLevel) slog.Logger {
 	if level <= slog.UndefinedLevel {
 		// fix your code
 		l.Panic().WithStack(1).Printf("slog: invalid log level %v", level)
-	} else if l.Parent != nil {
-		entry = l.Parent.WithLevel(level)
+	} else if level > l.CurrentThreshold() && level <= slog.Debug {
+		// shared, to avoid allocations
+		return l.disabledEntry(level)
+	} else if parent := l.parentFor(level); parent != nil {
+		entry = parent.WithLevel(level)
 	} else if level > slog.Fatal {
 		// Parentless non-Fatal, NOOP
 		return l
@@ -92,9 +212,27 @@ func (l *Logger) WithLevel(level slog.LogLevel) slog.Logger {
 	}
 }
 
+// Unwrap returns the Parent and the parents of the Routes,
+// so slog.Close() and slog.Flush() can walk the chain
+func (l *Logger) Unwrap() []slog.Logger {
+	out := make([]slog.Logger, 0, len(l.Routes)+1)
+	if l.Parent != nil {
+		out = append(out, l.Parent)
+	}
+	for _, r := range l.Routes {
+		if r.Parent != nil {
+			out = append(out, r.Parent)
+		}
+	}
+	return out
+}
+
 // WithStack does nothing
 func (l *Logger) WithStack(int) slog.Logger { return l }
 
+// WithName does nothing
+func (l *Logger) WithName(string) slog.Logger { return l }
+
 // WithField does nothing
 func (l *Logger) WithField(string, any) slog.Logger { return l }
 
@@ -116,7 +254,22 @@ func New(parent slog.Logger, threshold slog.LogLevel) slog.Logger {
 	}
 }
 
+// NewRouter creates a new filtered log factory sending entries to
+// different parent loggers depending on their level. Entries not
+// matching any Route are sent to the fallback parent, if any.
+func NewRouter(fallback slog.Logger, threshold slog.LogLevel, routes ...Route) slog.Logger {
+	if threshold <= slog.UndefinedLevel {
+		threshold = slog.Error
+	}
+	return &Logger{
+		Parent:    fallback,
+		Threshold: threshold,
+		Routes:    routes,
+	}
+}
+
 // NewNoop creates a new filtered log factory that only implements Fatal().Print()
+// Consider using darvaza.org/slog/handlers/noop instead, which doesn't allocate.
 func NewNoop() slog.Logger {
 	return New(nil, slog.Fatal)
 }
@@ -0,0 +1,117 @@
+package filter
+
+import (
+	"testing"
+
+	"darvaza.org/slog"
+	slogtest "darvaza.org/slog/internal/testing"
+)
+
+func newTestLogger(threshold slog.LogLevel) (*Logger, *slogtest.Recorder) {
+	rec := slogtest.NewRecorder()
+	return New(rec, threshold).(*Logger), rec
+}
+
+func TestDisabledAllocs(t *testing.T) {
+	slogtest.CheckDisabledAllocs(t, func() slog.Logger {
+		l, _ := newTestLogger(slog.Info)
+		return l
+	})
+}
+
+func TestSharedEntryThreshold(t *testing.T) {
+	l, rec := newTestLogger(slog.Info)
+
+	dbg := l.Debug()
+	if dbg != l.Debug() {
+		t.Fatal("disabled entries aren't shared")
+	}
+	if dbg.Enabled() {
+		t.Fatal("Debug enabled above Threshold")
+	}
+	dbg.Print("dropped")
+
+	l.SetThreshold(slog.Debug)
+	if !dbg.Enabled() {
+		t.Fatal("Debug still disabled after lowering the Threshold")
+	}
+	dbg.WithField("key", "value").Print("held")
+	dbg.Printf("%s", "formatted")
+
+	msgs := rec.Messages()
+	if len(msgs) != 2 {
+		t.Fatalf("expected 2 entries, got %d: %v", len(msgs), msgs)
+	}
+	if msgs[0].Message != "held" || msgs[0].Fields["key"] != "value" {
+		t.Errorf("unexpected entry: %+v", msgs[0])
+	}
+	if msgs[1].Message != "formatted" || msgs[1].Level != slog.Debug {
+		t.Errorf("unexpected entry: %+v", msgs[1])
+	}
+
+	l.SetThreshold(slog.Info)
+	dbg.Print("dropped again")
+	if n := len(rec.Messages()); n != 2 {
+		t.Errorf("expected 2 entries after raising the Threshold, got %d", n)
+	}
+}
+
+func TestSetThresholdConcurrent(t *testing.T) {
+	l, _ := newTestLogger(slog.Info)
+
+	s := slogtest.ConcurrentMutationSuite{
+		Factory:      func() slog.Logger { return l },
+		SetThreshold: l.SetThreshold,
+	}
+	s.Run(t)
+
+	if level := l.CurrentThreshold(); level != slog.Debug {
+		t.Errorf("expected Threshold %v, got %v", slog.Debug, level)
+	}
+}
+
+func TestOnThresholdChange(t *testing.T) {
+	var changes [][2]slog.LogLevel
+
+	l, _ := newTestLogger(slog.Info)
+	remove := l.OnThresholdChange(func(old, level slog.LogLevel) {
+		changes = append(changes, [2]slog.LogLevel{old, level})
+	})
+
+	l.SetThreshold(slog.Debug)
+	l.SetThreshold(slog.Debug)
+	remove()
+	l.SetThreshold(slog.Warn)
+
+	if len(changes) != 1 || changes[0] != [2]slog.LogLevel{slog.Info, slog.Debug} {
+		t.Errorf("unexpected changes: %v", changes)
+	}
+	if level := l.CurrentThreshold(); level != slog.Warn {
+		t.Errorf("expected Threshold %v, got %v", slog.Warn, level)
+	}
+}
+
+func BenchmarkFilter(b *testing.B) {
+	slogtest.RunBenchmarks(b, func() slog.Logger {
+		return New(slogtest.NewDiscardRecorder(), slog.Info)
+	})
+}
+
+func FuzzFilter(f *testing.F) {
+	slogtest.FuzzLoggerInputs(f, func(parent slog.Logger) slog.Logger {
+		return New(parent, slog.Info)
+	})
+}
+
+func TestWithStackFrames(t *testing.T) {
+	l, rec := newTestLogger(slog.Debug)
+
+	l.Info().WithStack(0).Print("stack")
+	l.Info().WithField("key", "value").WithStack(0).Print("stack and field")
+
+	here := slogtest.FrameMatcher{Func: "TestWithStackFrames", File: "filter_test.go"}
+	slogtest.AssertMessagesInOrder(t, rec.Messages(),
+		slogtest.Expect().Message("stack").StackTop(here),
+		slogtest.Expect().Message("stack and field").StackTop(here).Field("key", "value"),
+	)
+}
@@ -0,0 +1,31 @@
+package filter
+
+import "darvaza.org/slog"
+
+// Route sends entries with a level between From and To, inclusive,
+// to a particular Parent. The order of From and To doesn't matter.
+type Route struct {
+	Parent slog.Logger
+	From   slog.LogLevel
+	To     slog.LogLevel
+}
+
+// Match tells if the Route applies to the given level
+func (r Route) Match(level slog.LogLevel) bool {
+	lo, hi := r.From, r.To
+	if lo > hi {
+		lo, hi = hi, lo
+	}
+	return level >= lo && level <= hi
+}
+
+// parentFor returns the parent logger for the given level,
+// using the first matching Route or falling back to Parent
+func (l *Logger) parentFor(level slog.LogLevel) slog.Logger {
+	for _, r := range l.Routes {
+		if r.Match(level) {
+			return r.Parent
+		}
+	}
+	return l.Parent
+}
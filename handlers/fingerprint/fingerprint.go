@@ -0,0 +1,139 @@
+// Package fingerprint provides a slog.Logger that groups errors
+// by a stable fingerprint of their template and call stack
+package fingerprint
+
+import (
+	"encoding/hex"
+	"hash/fnv"
+	"time"
+
+	"darvaza.org/core"
+	"darvaza.org/slog"
+	"darvaza.org/slog/internal"
+)
+
+const (
+	// DefaultFieldName is the default field label for the fingerprint
+	DefaultFieldName = "fingerprint"
+	// DefaultFrames is the default number of stack frames considered
+	DefaultFrames = 3
+	// DefaultMaxGroups is the default number of groups counted
+	DefaultMaxGroups = 1024
+)
+
+// Clock is the source of time used to record when
+// groups are seen
+type Clock = internal.Clock
+
+// Config describes which entries are fingerprinted and how
+type Config struct {
+	// Clock defaults to the system clock
+	Clock Clock
+
+	// FieldName is the field label for the fingerprint.
+	// Defaults to DefaultFieldName
+	FieldName string
+
+	// Frames is the number of frames, from the top of the call
+	// stack, considered. Defaults to DefaultFrames
+	Frames int
+
+	// MaxGroups limits the number of groups counted. Entries
+	// of new groups are still fingerprinted once it's reached.
+	// Defaults to DefaultMaxGroups
+	MaxGroups int
+
+	// Threshold is the least severe level fingerprinted.
+	// Defaults to slog.Error
+	Threshold slog.LogLevel
+}
+
+// SetDefaults sets the field name, frames, groups and
+// Threshold not specified, and the system clock
+func (cfg *Config) SetDefaults() {
+	cfg.Clock = internal.ClockOrSystem(cfg.Clock)
+
+	if cfg.FieldName == "" {
+		cfg.FieldName = DefaultFieldName
+	}
+	if cfg.Frames <= 0 {
+		cfg.Frames = DefaultFrames
+	}
+	if cfg.MaxGroups <= 0 {
+		cfg.MaxGroups = DefaultMaxGroups
+	}
+	if cfg.Threshold <= slog.UndefinedLevel {
+		cfg.Threshold = slog.Error
+	}
+}
+
+// New creates a slog.Logger that attaches a fingerprint to entries
+// of the Threshold level or more severe before passing them to the
+// parent, and a Tracker counting them by fingerprint.
+func New(parent slog.Logger, cfg *Config) (slog.Logger, *Tracker) {
+	if parent == nil {
+		return nil, nil
+	}
+
+	var c Config
+	if cfg != nil {
+		c = *cfg
+	}
+	c.SetDefaults()
+
+	t := &Tracker{
+		cfg:    c,
+		groups: make(map[string]*Group),
+	}
+
+	return internal.NewWrapper(parent, &internal.WrapperHooks{
+		Print: t.print,
+	}), t
+}
+
+func (t *Tracker) print(e *internal.Entry) bool {
+	level := e.Level()
+	if level <= slog.UndefinedLevel || level > t.cfg.Threshold {
+		return true
+	}
+
+	template := e.Format
+	if template == "" {
+		template = e.Message
+	}
+
+	fp := Fingerprint(template, e.Loglet.CallStack(), t.cfg.Frames)
+	t.record(fp, template, level)
+
+	e.Logger = e.Logger.WithField(t.cfg.FieldName, fp)
+	return true
+}
+
+// Fingerprint computes a stable identifier for a message template
+// and the function names of up to the given number of frames from
+// the top of the call stack. Lines are ignored so it survives
+// unrelated changes to the code.
+func Fingerprint(template string, stack core.Stack, frames int) string {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(template))
+
+	if frames > len(stack) {
+		frames = len(stack)
+	}
+	for _, f := range stack[:frames] {
+		_, _ = h.Write([]byte{0})
+		_, _ = h.Write([]byte(f.Name()))
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Group is the summary of the entries sharing a fingerprint
+type Group struct {
+	FirstSeen   time.Time
+	LastSeen    time.Time
+	Fingerprint string
+	Template    string
+	Count       uint64
+	Level       slog.LogLevel
+}
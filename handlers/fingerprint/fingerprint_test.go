@@ -0,0 +1,118 @@
+package fingerprint
+
+import (
+	"testing"
+	"time"
+
+	"darvaza.org/core"
+	"darvaza.org/slog"
+	slogtest "darvaza.org/slog/internal/testing"
+)
+
+// fail logs an error with its call stack, from the same place
+func fail(l slog.Logger, user string) {
+	l.WithStack(0).Printf("user %s failed", user)
+}
+
+func TestGroups(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := slogtest.NewManualClock(start)
+
+	rec := slogtest.NewRecorder()
+	l, tracker := New(rec, &Config{Clock: clock})
+
+	fail(l.Error(), "alice")
+	clock.Advance(time.Minute)
+	fail(l.Fatal(), "bob")
+	l.Error().Print("other")
+	l.Warn().Print("not fingerprinted")
+
+	msgs := rec.Messages()
+	if len(msgs) != 4 {
+		t.Fatalf("expected 4 entries, got %d", len(msgs))
+	}
+	fp, _ := msgs[0].Fields[DefaultFieldName].(string)
+	if fp == "" || msgs[1].Fields[DefaultFieldName] != fp {
+		t.Errorf("expected the same fingerprint, got %v", msgs[:2])
+	}
+	if msgs[2].Fields[DefaultFieldName] == fp {
+		t.Error("different template with the same fingerprint")
+	}
+	if _, ok := msgs[3].Fields[DefaultFieldName]; ok {
+		t.Error("Warn entry fingerprinted")
+	}
+
+	groups := tracker.Groups()
+	if len(groups) != 2 {
+		t.Fatalf("expected 2 groups, got %v", groups)
+	}
+
+	// the most frequent first
+	g := groups[0]
+	if g.Fingerprint != fp || g.Count != 2 || g.Template != "user %s failed" {
+		t.Errorf("unexpected group %+v", g)
+	}
+	if g.Level != slog.Fatal {
+		t.Errorf("expected the most severe level, got %s", g.Level)
+	}
+	if !g.FirstSeen.Equal(start) || !g.LastSeen.Equal(start.Add(time.Minute)) {
+		t.Errorf("unexpected times %v and %v", g.FirstSeen, g.LastSeen)
+	}
+
+	if got, ok := tracker.Get(fp); !ok || got != g {
+		t.Errorf("expected %+v, got %+v", g, got)
+	}
+
+	tracker.Reset()
+	if groups := tracker.Groups(); len(groups) != 0 {
+		t.Errorf("unexpected groups after Reset: %v", groups)
+	}
+}
+
+func TestMaxGroups(t *testing.T) {
+	rec := slogtest.NewRecorder()
+	l, tracker := New(rec, &Config{
+		MaxGroups: 1,
+		Threshold: slog.Warn,
+		FieldName: "fp",
+	})
+
+	l.Warn().Print("first")
+	l.Warn().Print("second")
+
+	if n := len(tracker.Groups()); n != 1 {
+		t.Errorf("expected 1 group, got %d", n)
+	}
+	// still fingerprinted
+	slogtest.AssertMessagesInOrder(t, rec.Messages(),
+		slogtest.Expect().Message("first").HasField("fp"),
+		slogtest.Expect().Message("second").HasField("fp"))
+}
+
+func TestFingerprint(t *testing.T) {
+	a := core.StackTrace(0)
+	b := core.StackTrace(0)
+
+	// lines are ignored
+	if Fingerprint("x", a, 2) != Fingerprint("x", b, 2) {
+		t.Error("fingerprint depends on the lines")
+	}
+	if Fingerprint("x", a, 2) == Fingerprint("y", a, 2) {
+		t.Error("fingerprint ignores the template")
+	}
+	if Fingerprint("x", a, 1) == Fingerprint("x", a[1:], 1) {
+		t.Error("fingerprint ignores the frames")
+	}
+	// more frames than available
+	if Fingerprint("x", a, len(a)+5) != Fingerprint("x", a, len(a)) {
+		t.Error("fingerprint changes beyond the stack")
+	}
+}
+
+func TestProperties(t *testing.T) {
+	slogtest.CheckProperties(t, func(parent slog.Logger) slog.Logger {
+		// nothing fingerprinted to keep the fields unaltered
+		l, _ := New(parent, &Config{Threshold: slog.Panic})
+		return l
+	})
+}
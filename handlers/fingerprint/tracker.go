@@ -0,0 +1,89 @@
+package fingerprint
+
+import (
+	"sort"
+	"sync"
+
+	"darvaza.org/slog"
+)
+
+// Tracker counts entries by fingerprint
+type Tracker struct {
+	mu     sync.Mutex
+	groups map[string]*Group
+	cfg    Config
+}
+
+func (t *Tracker) record(fp, template string, level slog.LogLevel) {
+	now := t.cfg.Clock.Now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	g, ok := t.groups[fp]
+	switch {
+	case ok:
+		g.Count++
+		g.LastSeen = now
+		if level < g.Level {
+			// most severe
+			g.Level = level
+		}
+	case len(t.groups) < t.cfg.MaxGroups:
+		t.groups[fp] = &Group{
+			FirstSeen:   now,
+			LastSeen:    now,
+			Fingerprint: fp,
+			Template:    template,
+			Count:       1,
+			Level:       level,
+		}
+	}
+}
+
+// Get returns the Group of a fingerprint
+func (t *Tracker) Get(fp string) (Group, bool) {
+	if t == nil {
+		return Group{}, false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if g, ok := t.groups[fp]; ok {
+		return *g, true
+	}
+	return Group{}, false
+}
+
+// Groups returns all groups, the most frequent first
+func (t *Tracker) Groups() []Group {
+	if t == nil {
+		return nil
+	}
+
+	t.mu.Lock()
+	out := make([]Group, 0, len(t.groups))
+	for _, g := range t.groups {
+		out = append(out, *g)
+	}
+	t.mu.Unlock()
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		return out[i].Fingerprint < out[j].Fingerprint
+	})
+	return out
+}
+
+// Reset forgets all groups
+func (t *Tracker) Reset() {
+	if t != nil {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		t.groups = make(map[string]*Group)
+	}
+}
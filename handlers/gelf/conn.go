@@ -0,0 +1,178 @@
+package gelf
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+const (
+	// chunkHeaderSize is the size of the header of every chunk
+	chunkHeaderSize = 12
+	// maxChunks is the maximum number of chunks of a message
+	maxChunks = 128
+)
+
+var (
+	chunkMagic = []byte{0x1e, 0x0f}
+
+	// ErrTooLarge indicates a message needs more than
+	// 128 chunks to be sent over UDP
+	ErrTooLarge = errors.New("gelf: message too large")
+
+	// errBackoff indicates it's too soon to reconnect
+	errBackoff = errors.New("gelf: waiting to reconnect")
+)
+
+func (c *Client) isUDP() bool {
+	return strings.HasPrefix(c.cfg.Network, "udp")
+}
+
+// send writes a message, reconnecting once if the
+// existing connection fails, e.g. closed by the peer
+// while idle
+func (c *Client) send(b []byte) error {
+	if c.isUDP() {
+		return c.sendUDP(b)
+	}
+
+	// TCP messages are null terminated
+	b = append(b, 0)
+	reused := c.conn != nil
+	err := c.write(b)
+	if err != nil && reused {
+		// stale connection, try again right away
+		c.retryAt = time.Time{}
+		err = c.write(b)
+	}
+	return err
+}
+
+func (c *Client) sendUDP(b []byte) error {
+	b, err := c.compress(b)
+	if err != nil {
+		return err
+	}
+
+	if len(b) <= c.cfg.ChunkSize {
+		return c.write(b)
+	}
+
+	size := c.cfg.ChunkSize - chunkHeaderSize
+	count := (len(b) + size - 1) / size
+	if count > maxChunks {
+		return ErrTooLarge
+	}
+
+	var id [8]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return err
+	}
+
+	chunk := make([]byte, 0, c.cfg.ChunkSize)
+	for i := 0; i < count; i++ {
+		part := b[i*size : min(len(b), (i+1)*size)]
+
+		chunk = append(chunk[:0], chunkMagic...)
+		chunk = append(chunk, id[:]...)
+		chunk = append(chunk, byte(i), byte(count))
+		chunk = append(chunk, part...)
+
+		if err := c.write(chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Client) compress(b []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	var w io.WriteCloser
+
+	switch c.cfg.Compression {
+	case CompressGzip:
+		w = gzip.NewWriter(&buf)
+	case CompressZlib:
+		w = zlib.NewWriter(&buf)
+	default:
+		return b, nil
+	}
+
+	if _, err := w.Write(b); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (c *Client) write(b []byte) error {
+	if err := c.connect(); err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(c.cfg.WriteTimeout)
+	if err := c.conn.SetWriteDeadline(deadline); err != nil {
+		c.disconnect()
+		return err
+	}
+
+	if _, err := c.conn.Write(b); err != nil {
+		c.disconnect()
+		return err
+	}
+	return nil
+}
+
+// connect makes sure there is a connection, unless it's
+// too soon to try again
+func (c *Client) connect() error {
+	switch {
+	case c.conn != nil:
+		return nil
+	case c.cfg.Clock.Now().Before(c.retryAt):
+		return errBackoff
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.cfg.DialTimeout)
+	defer cancel()
+
+	conn, err := c.cfg.Dial(ctx, c.cfg.Network, c.cfg.Address)
+	if err != nil {
+		// exponential backoff
+		c.retryAt = c.cfg.Clock.Now().Add(c.delay)
+		c.delay = min(2*c.delay, c.cfg.MaxReconnectDelay)
+		return err
+	}
+
+	c.conn = conn
+	c.delay = c.cfg.ReconnectDelay
+	return nil
+}
+
+func (c *Client) disconnect() {
+	if c.conn != nil {
+		_ = c.conn.Close()
+		c.conn = nil
+	}
+	c.retryAt = c.cfg.Clock.Now().Add(c.delay)
+}
+
+func newDialer(config *tls.Config) func(context.Context, string, string) (net.Conn, error) {
+	if config != nil {
+		d := &tls.Dialer{Config: config}
+		return d.DialContext
+	}
+
+	d := &net.Dialer{}
+	return d.DialContext
+}
@@ -0,0 +1,94 @@
+package gelf
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"darvaza.org/slog"
+	"darvaza.org/slog/handlers/cblog"
+	"darvaza.org/slog/internal"
+)
+
+// Version is the GELF version produced by Encode
+const Version = "1.1"
+
+var syslogLevels = []int{
+	slog.UndefinedLevel: 6,
+	slog.Panic:          1, // Alert
+	slog.Fatal:          2, // Critical
+	slog.Error:          3, // Error
+	slog.Warn:           4, // Warning
+	slog.Info:           6, // Informational
+	slog.Debug:          7, // Debug
+}
+
+// SyslogLevel returns the syslog severity corresponding
+// to a slog.LogLevel
+func SyslogLevel(level slog.LogLevel) int {
+	if level >= slog.UndefinedLevel && int(level) < len(syslogLevels) {
+		return syslogLevels[level]
+	}
+	return syslogLevels[slog.UndefinedLevel]
+}
+
+// Encode renders an entry as a GELF message. Fields become
+// additional fields prefixed by an underscore, and the call
+// stack, if any, is appended to the full_message.
+func Encode(msg cblog.LogMsg, host string) ([]byte, error) {
+	out := make(map[string]any, len(msg.Fields)+6)
+	for k, v := range msg.Fields {
+		if k = FieldName(k); k != "" {
+			out[k] = encodeValue(v)
+		}
+	}
+
+	out["version"] = Version
+	out["host"] = host
+	out["short_message"] = msg.Message
+	out["timestamp"] = float64(msg.Time.UnixMicro()) / 1e6
+	out["level"] = SyslogLevel(msg.Level)
+	if len(msg.Stack) > 0 {
+		out["full_message"] = msg.Message + "\n" + slog.FormatStack(msg.Stack)
+	}
+
+	return json.Marshal(out)
+}
+
+// FieldName converts a field label into a GELF additional field
+// name, or returns an empty string if it can't be used.
+func FieldName(label string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		case r == '_', r == '.', r == '-':
+			return r
+		default:
+			return '_'
+		}
+	}, label)
+
+	switch name {
+	case "", "id":
+		// _id is reserved
+		return ""
+	default:
+		return "_" + name
+	}
+}
+
+func encodeValue(v any) any {
+	if n, ok := v.(json.Number); ok {
+		return n
+	}
+	if out, ok := internal.EncodeValue(v); ok {
+		return out
+	}
+
+	// GELF only allows strings and numbers
+	if b, err := json.Marshal(v); err == nil {
+		return string(b)
+	}
+	return fmt.Sprint(v)
+}
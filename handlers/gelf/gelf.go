@@ -0,0 +1,257 @@
+// Package gelf provides a slog.Logger that sends entries
+// to Graylog using GELF 1.1
+package gelf
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"darvaza.org/core"
+	"darvaza.org/slog"
+	"darvaza.org/slog/handlers/cblog"
+	"darvaza.org/slog/internal"
+)
+
+const (
+	// DefaultChunkSize is the maximum size of a UDP datagram,
+	// suitable for most WANs
+	DefaultChunkSize = 1420
+	// DefaultDialTimeout is the maximum time to wait for a connection
+	DefaultDialTimeout = 5 * time.Second
+	// DefaultWriteTimeout is the maximum time to wait for a write
+	DefaultWriteTimeout = 5 * time.Second
+	// DefaultReconnectDelay is the initial wait before reconnecting
+	DefaultReconnectDelay = time.Second
+	// DefaultMaxReconnectDelay is the maximum wait before reconnecting
+	DefaultMaxReconnectDelay = time.Minute
+)
+
+// Compression is the algorithm used to compress UDP messages
+type Compression int
+
+const (
+	// CompressNone sends messages uncompressed
+	CompressNone Compression = iota
+	// CompressGzip compresses messages using gzip
+	CompressGzip
+	// CompressZlib compresses messages using zlib
+	CompressZlib
+)
+
+// Config describes the Graylog input to send the entries to
+type Config struct {
+	// TLSConfig enables TLS on TCP connections
+	TLSConfig *tls.Config
+	// Dial optionally replaces net.Dialer, or tls.Dialer when
+	// TLSConfig is set, for establishing connections
+	Dial func(ctx context.Context, network, address string) (net.Conn, error)
+	// Clock timestamps the entries and paces the reconnection
+	// delays. Defaults to the system clock
+	Clock cblog.Clock
+	// OnError is called when connecting or writing fails,
+	// instead of reporting it using slog.ReportError()
+	OnError func(error)
+
+	// Network is "udp" or "tcp", and their 4 and 6 variants
+	Network string
+	// Address is the host:port of the GELF input
+	Address string
+	// Host identifies the source of the entries. Defaults to
+	// os.Hostname()
+	Host string
+
+	// Compression only applies to UDP
+	Compression Compression
+	// ChunkSize is the maximum size of a UDP datagram.
+	// Defaults to DefaultChunkSize
+	ChunkSize int
+	// BufferSize is the number of entries that can wait to be
+	// sent before logging blocks. Defaults to
+	// cblog.DefaultOutputBufferSize
+	BufferSize int
+	// DialTimeout is the maximum time to wait for a connection.
+	// Defaults to DefaultDialTimeout
+	DialTimeout time.Duration
+	// WriteTimeout is the maximum time to wait for a message to
+	// be written. Defaults to DefaultWriteTimeout
+	WriteTimeout time.Duration
+	// ReconnectDelay is the initial wait before reconnecting,
+	// doubled on every failure up to MaxReconnectDelay.
+	// Defaults to DefaultReconnectDelay and
+	// DefaultMaxReconnectDelay
+	ReconnectDelay    time.Duration
+	MaxReconnectDelay time.Duration
+}
+
+// SetDefaults sets the network, host, chunk size, buffers and
+// timeouts not specified, and the dialer if Dial isn't set
+func (cfg *Config) SetDefaults() {
+	if cfg.Clock == nil {
+		cfg.Clock = internal.SystemClock
+	}
+	if cfg.Network == "" {
+		cfg.Network = "udp"
+	}
+	if cfg.Host == "" {
+		cfg.Host, _ = os.Hostname()
+	}
+	if cfg.ChunkSize <= chunkHeaderSize {
+		cfg.ChunkSize = DefaultChunkSize
+	}
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = cblog.DefaultOutputBufferSize
+	}
+	if cfg.DialTimeout <= 0 {
+		cfg.DialTimeout = DefaultDialTimeout
+	}
+	if cfg.WriteTimeout <= 0 {
+		cfg.WriteTimeout = DefaultWriteTimeout
+	}
+	if cfg.ReconnectDelay <= 0 {
+		cfg.ReconnectDelay = DefaultReconnectDelay
+	}
+	if cfg.MaxReconnectDelay < cfg.ReconnectDelay {
+		cfg.MaxReconnectDelay = max(DefaultMaxReconnectDelay, cfg.ReconnectDelay)
+	}
+
+	if cfg.Dial == nil {
+		cfg.Dial = newDialer(cfg.TLSConfig)
+	}
+}
+
+// Validate checks the Address is set, and the Network and
+// Compression are supported, TLS only over TCP
+func (cfg *Config) Validate() error {
+	switch {
+	case cfg.Address == "":
+		return core.Wrap(core.ErrInvalid, "gelf: address not specified")
+	case cfg.Compression < CompressNone || cfg.Compression > CompressZlib:
+		return core.Wrapf(core.ErrInvalid, "gelf: invalid compression %v", cfg.Compression)
+	}
+
+	switch cfg.Network {
+	case "udp", "udp4", "udp6":
+		if cfg.TLSConfig != nil {
+			return core.Wrap(core.ErrInvalid, "gelf: TLS requires TCP")
+		}
+		return nil
+	case "tcp", "tcp4", "tcp6":
+		return nil
+	default:
+		return core.Wrapf(core.ErrInvalid, "gelf: unsupported network %q", cfg.Network)
+	}
+}
+
+// Client sends the entries of its slog.Logger to Graylog
+type Client struct {
+	logger  *cblog.Logger
+	conn    net.Conn
+	retryAt time.Time
+	lastErr internal.LastError
+	dropped atomic.Uint64
+	delay   time.Duration
+	cfg     Config
+}
+
+// New creates a Client sending entries to the GELF input described
+// by the Config. Connections are established lazily, kept open, and
+// reestablished when they fail. After a failed attempt, entries are
+// dropped until ReconnectDelay passes, doubling up to MaxReconnectDelay
+// while the input remains unreachable.
+func New(cfg *Config) (*Client, error) {
+	if cfg == nil {
+		return nil, core.Wrap(core.ErrInvalid, "gelf: config not specified")
+	}
+
+	c := &Client{cfg: *cfg}
+	c.cfg.SetDefaults()
+	if err := c.cfg.Validate(); err != nil {
+		return nil, err
+	}
+	c.delay = c.cfg.ReconnectDelay
+
+	c.logger = cblog.NewWithCallback(c.cfg.BufferSize, c.handle,
+		cblog.WithClock(c.cfg.Clock),
+		cblog.WithHealthCheck(c.Health),
+		cblog.WithStats(c.Stats))
+	return c, nil
+}
+
+// Logger returns the slog.Logger whose entries are sent to Graylog
+func (c *Client) Logger() slog.Logger {
+	return c.logger
+}
+
+// Health returns the last error sending to Graylog,
+// until sending an entry succeeds
+func (c *Client) Health() error {
+	return c.lastErr.Err()
+}
+
+// DroppedCount returns the number of entries that couldn't be sent,
+// including those discarded while waiting to reconnect
+func (c *Client) DroppedCount() uint64 {
+	return c.dropped.Load()
+}
+
+// Stats returns the number of entries dropped by the Client, as
+// slog.DroppedStat, when they can't be sent, including while waiting
+// to reconnect. Entries waiting to be sent are reported by the
+// cblog.Logger returned by Logger().
+func (c *Client) Stats() slog.Stats {
+	return slog.Stats{
+		slog.DroppedStat: c.dropped.Load(),
+	}
+}
+
+// Close stops accepting new entries and waits, up to
+// cblog.DefaultShutdownTimeout, for the pending ones to be sent.
+func (c *Client) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), cblog.DefaultShutdownTimeout)
+	defer cancel()
+
+	return c.Shutdown(ctx)
+}
+
+// Shutdown stops accepting new entries and waits for the pending
+// ones to be sent, or the context to be cancelled.
+func (c *Client) Shutdown(ctx context.Context) error {
+	err := c.logger.Shutdown(ctx)
+	if err == nil {
+		// the worker is done
+		c.disconnect()
+	}
+	return err
+}
+
+func (c *Client) handle(msg cblog.LogMsg) {
+	b, err := Encode(msg, c.cfg.Host)
+	if err == nil {
+		err = c.send(b)
+	}
+
+	switch {
+	case err == nil:
+		c.lastErr.Set(nil)
+	case err == errBackoff:
+		// the failure to connect was already reported
+		c.dropped.Add(1)
+	default:
+		c.dropped.Add(1)
+		c.reportError(err)
+	}
+}
+
+func (c *Client) reportError(err error) {
+	c.lastErr.Set(err)
+
+	if fn := c.cfg.OnError; fn != nil {
+		fn(err)
+	} else {
+		slog.ReportError("gelf", err)
+	}
+}
@@ -0,0 +1,110 @@
+package gelf
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	slogtest "darvaza.org/slog/internal/testing"
+)
+
+// testDialer fails until told otherwise, and then connects
+// to a net.Pipe counting the messages received
+type testDialer struct {
+	dials    atomic.Int32
+	up       atomic.Bool
+	received atomic.Int32
+}
+
+func (d *testDialer) Dial(context.Context, string, string) (net.Conn, error) {
+	d.dials.Add(1)
+	if !d.up.Load() {
+		return nil, errors.New("unreachable")
+	}
+
+	client, server := net.Pipe()
+	go func() {
+		r := bufio.NewReader(server)
+		for {
+			if _, err := r.ReadBytes(0); err != nil {
+				return
+			}
+			d.received.Add(1)
+		}
+	}()
+	return client, nil
+}
+
+func TestReconnectBackoff(t *testing.T) {
+	clock := slogtest.NewManualClock(time.Now())
+	d := new(testDialer)
+
+	c, err := New(&Config{
+		Network:        "tcp",
+		Address:        "graylog",
+		Clock:          clock,
+		Dial:           d.Dial,
+		OnError:        func(error) {},
+		ReconnectDelay: time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+
+	send := func(n int) {
+		t.Helper()
+		for i := 0; i < n; i++ {
+			c.Logger().Info().Print("hello")
+		}
+		if err := c.logger.Flush(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expect := func(dials, dropped int) {
+		t.Helper()
+		if got := int(d.dials.Load()); got != dials {
+			t.Errorf("expected %d dials, got %d", dials, got)
+		}
+		if got := int(c.DroppedCount()); got != dropped {
+			t.Errorf("expected %d dropped, got %d", dropped, got)
+		}
+	}
+
+	// a single attempt while unreachable
+	send(3)
+	expect(1, 3)
+	if c.Health() == nil {
+		t.Error("dial failure not reported by Health()")
+	}
+
+	// delay doubled after the second failure
+	clock.Advance(time.Second)
+	send(1)
+	expect(2, 4)
+	clock.Advance(time.Second)
+	send(1)
+	expect(2, 5)
+
+	// connection reused once established
+	d.up.Store(true)
+	clock.Advance(time.Second)
+	send(3)
+	expect(3, 5)
+	if err := c.Health(); err != nil {
+		t.Errorf("unexpected error after reconnecting: %v", err)
+	}
+
+	// the reader counts after the write returns
+	deadline := time.Now().Add(time.Second)
+	for d.received.Load() < 3 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if got := d.received.Load(); got != 3 {
+		t.Errorf("expected 3 messages received, got %d", got)
+	}
+}
@@ -0,0 +1,180 @@
+package indirect
+
+import (
+	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"darvaza.org/slog"
+	"darvaza.org/slog/internal"
+)
+
+// DefaultBufferSize is the number of entries held until
+// the first target is set when no size is given
+const DefaultBufferSize = 1024
+
+// handle is shared by all the loggers derived from the same New()
+type handle struct {
+	target  atomic.Pointer[holder]
+	mu      sync.Mutex
+	pending []entry
+	// draining is the target set while the held entries
+	// are being passed to it
+	draining slog.Logger
+	dropped  atomic.Uint64
+	clock    internal.Clock
+	size     int
+}
+
+// holder allows storing any slog.Logger atomically
+type holder struct {
+	slog.Logger
+}
+
+// entry is held until the first target is set
+type entry struct {
+	msg string
+	ll  internal.Loglet
+}
+
+func (h *handle) current() slog.Logger {
+	if p := h.target.Load(); p != nil {
+		return p.Logger
+	}
+	return nil
+}
+
+func (h *handle) pendingCount() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return len(h.pending)
+}
+
+// setTarget replaces the target. The first time, the held entries
+// are delivered without holding the lock, so the target can log
+// back, while new entries keep being held until all are delivered.
+func (h *handle) setTarget(target slog.Logger) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	switch {
+	case h.draining != nil:
+		// the call draining will store it
+		h.draining = target
+		return
+	case len(h.pending) == 0:
+		h.target.Store(&holder{target})
+		return
+	}
+
+	h.draining = target
+	for len(h.pending) > 0 {
+		pending, target := h.pending, h.draining
+		h.pending = nil
+
+		h.mu.Unlock()
+		for _, e := range pending {
+			deliver(target, &e.ll, e.msg)
+		}
+		h.mu.Lock()
+	}
+
+	h.target.Store(&holder{h.draining})
+	h.draining = nil
+}
+
+func (h *handle) print(ll *internal.Loglet, msg string) {
+	msg = strings.TrimSpace(msg)
+
+	if t := h.current(); t != nil {
+		deliver(t, ll, msg)
+		return
+	}
+
+	h.mu.Lock()
+	if t := h.current(); t != nil {
+		// set while waiting
+		h.mu.Unlock()
+		deliver(t, ll, msg)
+		return
+	}
+
+	switch level := ll.Level(); level {
+	case slog.Fatal, slog.Panic:
+		if t := h.draining; t != nil {
+			// it won't wait for those held
+			h.mu.Unlock()
+			deliver(t, ll, msg)
+			return
+		}
+
+		// there is no one to take it
+		h.mu.Unlock()
+		terminate(level, msg)
+	default:
+		h.hold(ll, msg)
+		h.mu.Unlock()
+	}
+}
+
+// hold appends an entry to the buffer, with the time it was
+// added unless it has one, or counts it if full
+func (h *handle) hold(ll *internal.Loglet, msg string) {
+	if len(h.pending) >= h.size {
+		h.dropped.Add(1)
+		return
+	}
+
+	e := entry{msg: msg, ll: *ll}
+	if ll.Time().IsZero() {
+		e.ll = ll.WithTime(h.clock.Now())
+	}
+	h.pending = append(h.pending, e)
+}
+
+// deliver passes an entry to the target, attaching the
+// level, call stack, time, name and fields recorded
+func deliver(target slog.Logger, ll *internal.Loglet, msg string) {
+	l := target
+	if level := ll.Level(); level > slog.UndefinedLevel {
+		l = l.WithLevel(level)
+	}
+
+	l, ok := l.WithEnabled()
+	if !ok {
+		return
+	}
+
+	if st := ll.CallStack(); len(st) > 0 {
+		l = slog.WithCallStack(l, st)
+	}
+	if t := ll.Time(); !t.IsZero() {
+		l = slog.WithTime(l, t)
+	}
+
+	if ll.FieldsCount() > 0 {
+		fields := ll.FieldsMap()
+		if name, ok := fields[slog.NameFieldName].(string); ok {
+			delete(fields, slog.NameFieldName)
+			l = slog.WithName(l, name)
+		}
+		if len(fields) > 0 {
+			l = l.WithFields(fields)
+		}
+	}
+
+	l.Print(msg)
+}
+
+// terminate writes Fatal and Panic entries using the standard
+// library's log package when there is no target to take them
+func terminate(level slog.LogLevel, msg string) {
+	_ = log.Output(4, msg)
+
+	if level == slog.Panic {
+		panic(msg)
+	}
+	slog.Exit(1)
+}
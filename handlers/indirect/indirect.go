@@ -0,0 +1,253 @@
+// Package indirect provides a slog.Logger whose backend can be
+// replaced at any time, holding early entries until the first
+// one is set
+package indirect
+
+import (
+	"fmt"
+	"time"
+
+	"darvaza.org/core"
+	"darvaza.org/slog"
+	"darvaza.org/slog/internal"
+)
+
+var (
+	_ slog.Logger        = (*Logger)(nil)
+	_ slog.CallerLogger  = (*Logger)(nil)
+	_ slog.NamedLogger   = (*Logger)(nil)
+	_ slog.KVLogger      = (*Logger)(nil)
+	_ slog.TimedLogger   = (*Logger)(nil)
+	_ slog.StackLogger   = (*Logger)(nil)
+	_ slog.Unwrapper     = (*Logger)(nil)
+	_ slog.StatsReporter = (*Logger)(nil)
+)
+
+// Logger is a slog.Logger passing its entries to a target
+// that can be replaced at any time using SetTarget().
+// Until the first target is set entries are held in memory.
+type Logger struct {
+	internal.Loglet
+
+	h *handle
+}
+
+// New creates a Logger holding up to size entries until the first
+// target is set, DefaultBufferSize if zero or negative. Further
+// entries are discarded and counted.
+func New(size int) *Logger {
+	if size <= 0 {
+		size = DefaultBufferSize
+	}
+
+	return &Logger{
+		h: &handle{
+			size:  size,
+			clock: internal.SystemClock,
+		},
+	}
+}
+
+// SetTarget replaces the logger entries are passed to. The first time
+// the entries held so far are passed to it, in order, before any new
+// one. A nil target is ignored.
+func (l *Logger) SetTarget(target slog.Logger) {
+	if target != nil {
+		l.h.setTarget(target)
+	}
+}
+
+// Unwrap returns the current target, or nil if none has been set,
+// so slog.Close() and slog.Flush() can walk the chain
+func (l *Logger) Unwrap() slog.Logger {
+	return l.h.current()
+}
+
+// DroppedCount returns the number of entries discarded because
+// the buffer was full before a target was set
+func (l *Logger) DroppedCount() uint64 {
+	return l.h.dropped.Load()
+}
+
+// Stats returns the number of entries dropped and held
+// waiting for a target
+func (l *Logger) Stats() slog.Stats {
+	return slog.Stats{
+		slog.DroppedStat: l.h.dropped.Load(),
+		slog.PendingStat: uint64(l.h.pendingCount()),
+	}
+}
+
+// Enabled tells if the target would log entries of this level,
+// or true if no target has been set yet
+func (l *Logger) Enabled() bool {
+	t := l.h.current()
+	switch {
+	case t == nil:
+		return true
+	case l.Level() <= slog.UndefinedLevel:
+		return t.Enabled()
+	default:
+		return t.WithLevel(l.Level()).Enabled()
+	}
+}
+
+// WithEnabled passes the logger and if it's enabled
+func (l *Logger) WithEnabled() (slog.Logger, bool) {
+	return l, l.Enabled()
+}
+
+// Print adds a log entry with arguments handled in the manner of fmt.Print
+func (l *Logger) Print(args ...any) {
+	l.h.print(&l.Loglet, fmt.Sprint(args...))
+}
+
+// Println adds a log entry with arguments handled in the manner of fmt.Println
+func (l *Logger) Println(args ...any) {
+	l.h.print(&l.Loglet, fmt.Sprintln(args...))
+}
+
+// Printf adds a log entry with arguments handled in the manner of fmt.Printf
+func (l *Logger) Printf(format string, args ...any) {
+	l.h.print(&l.Loglet, internal.Sprintf(format, args...))
+}
+
+// Debug returns a new logger set to add entries as level Debug
+func (l *Logger) Debug() slog.Logger {
+	return l.WithLevel(slog.Debug)
+}
+
+// Info returns a new logger set to add entries as level Info
+func (l *Logger) Info() slog.Logger {
+	return l.WithLevel(slog.Info)
+}
+
+// Warn returns a new logger set to add entries as level Warn
+func (l *Logger) Warn() slog.Logger {
+	return l.WithLevel(slog.Warn)
+}
+
+// Error returns a new logger set to add entries as level Error
+func (l *Logger) Error() slog.Logger {
+	return l.WithLevel(slog.Error)
+}
+
+// Fatal returns a new logger set to add entries as level Fatal
+func (l *Logger) Fatal() slog.Logger {
+	return l.WithLevel(slog.Fatal)
+}
+
+// Panic returns a new logger set to add entries as level Panic
+func (l *Logger) Panic() slog.Logger {
+	return l.WithLevel(slog.Panic)
+}
+
+// WithLevel returns a new logger set to add entries to the specified level
+func (l *Logger) WithLevel(level slog.LogLevel) slog.Logger {
+	if level <= slog.UndefinedLevel {
+		// fix your code
+		l.Panic().WithStack(1).Printf("slog: invalid log level %v", level)
+	} else if level == l.Level() {
+		return l
+	}
+
+	out := &Logger{
+		Loglet: l.Loglet.WithLevel(level),
+		h:      l.h,
+	}
+	return out
+}
+
+// WithStack attaches a call stack to a new logger
+func (l *Logger) WithStack(skip int) slog.Logger {
+	out := &Logger{
+		Loglet: l.Loglet.WithStack(skip + 1),
+		h:      l.h,
+	}
+	return out
+}
+
+// WithCallStack attaches a given call stack to a new logger
+func (l *Logger) WithCallStack(st core.Stack) slog.Logger {
+	out := &Logger{
+		Loglet: l.Loglet.WithCallStack(st),
+		h:      l.h,
+	}
+	return out
+}
+
+// WithTime records when the event happened on a new logger,
+// passed to the target instead of when the entry was added
+func (l *Logger) WithTime(t time.Time) slog.Logger {
+	out := &Logger{
+		Loglet: l.Loglet.WithTime(t),
+		h:      l.h,
+	}
+	return out
+}
+
+// WithCaller attaches the immediate caller to a new logger,
+// as slog.CallerFieldName field of core.Frame type
+func (l *Logger) WithCaller(skip int) slog.Logger {
+	out := &Logger{
+		Loglet: l.Loglet.WithCaller(skip + 1),
+		h:      l.h,
+	}
+	return out
+}
+
+// WithName appends a component to the dot-separated name of a
+// new logger, stored as slog.NameFieldName field
+func (l *Logger) WithName(name string) slog.Logger {
+	if name != "" {
+		ll := l.Loglet.WithName(name)
+		out := &Logger{
+			Loglet: ll.Compact(),
+			h:      l.h,
+		}
+		return out
+	}
+	return l
+}
+
+// WithField returns a new logger with a field attached
+func (l *Logger) WithField(label string, value any) slog.Logger {
+	if label != "" {
+		ll := l.Loglet.WithField(label, value)
+		out := &Logger{
+			Loglet: ll.Compact(),
+			h:      l.h,
+		}
+		return out
+	}
+	return l
+}
+
+// WithFields returns a new logger with a set of fields attached
+func (l *Logger) WithFields(fields map[string]any) slog.Logger {
+	delete(fields, "")
+
+	if len(fields) > 0 {
+		ll := l.Loglet.WithFields(fields)
+		out := &Logger{
+			Loglet: ll.Compact(),
+			h:      l.h,
+		}
+		return out
+	}
+	return l
+}
+
+// WithKV returns a new logger with fields attached from
+// alternating key/value pairs
+func (l *Logger) WithKV(pairs ...any) slog.Logger {
+	if len(pairs) > 0 {
+		ll := l.Loglet.WithKV(pairs...)
+		out := &Logger{
+			Loglet: ll.Compact(),
+			h:      l.h,
+		}
+		return out
+	}
+	return l
+}
@@ -0,0 +1,119 @@
+package indirect
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"darvaza.org/slog"
+	"darvaza.org/slog/internal"
+	slogtest "darvaza.org/slog/internal/testing"
+)
+
+func TestHeldUntilTarget(t *testing.T) {
+	l := New(0)
+	start := time.Now()
+
+	l.Info().WithField("key", "value").Print("first")
+	slog.WithName(l.Warn(), "component").Print("second")
+	if n := l.Stats()[slog.PendingStat]; n != 2 {
+		t.Fatalf("expected 2 entries held, got %d", n)
+	}
+
+	rec := slogtest.NewRecorder()
+	l.SetTarget(rec)
+	l.Error().Print("third")
+
+	msgs := rec.Messages()
+	slogtest.AssertMessagesInOrder(t, msgs,
+		slogtest.Expect().Level(slog.Info).Message("first").Field("key", "value"),
+		slogtest.Expect().Level(slog.Warn).Message("second").
+			Field(slog.NameFieldName, "component"),
+		slogtest.Expect().Level(slog.Error).Message("third"))
+	slogtest.AssertWithin(t, msgs, start, time.Second)
+
+	if n := l.Stats()[slog.PendingStat]; n != 0 {
+		t.Errorf("%d entries still held", n)
+	}
+}
+
+func TestHeldLimit(t *testing.T) {
+	l := New(2)
+	for i := 0; i < 5; i++ {
+		l.Info().Print(i)
+	}
+
+	rec := slogtest.NewRecorder()
+	l.SetTarget(rec)
+
+	if n := l.DroppedCount(); n != 3 {
+		t.Errorf("expected 3 entries dropped, got %d", n)
+	}
+	slogtest.AssertMessagesInOrder(t, rec.Messages(),
+		slogtest.Expect().Message("0"),
+		slogtest.Expect().Message("1"))
+}
+
+func TestTargetLogsBack(t *testing.T) {
+	l := New(0)
+	l.Info().Print("first")
+	l.Info().Print("second")
+
+	// the target logs back an echo of what it receives
+	rec := slogtest.NewRecorder()
+	target := internal.NewWrapper(rec, &internal.WrapperHooks{
+		Print: func(e *internal.Entry) bool {
+			if !strings.HasPrefix(e.Message, "echo") {
+				l.Info().Print("echo " + e.Message)
+			}
+			return true
+		},
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		l.SetTarget(target)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("SetTarget deadlocked")
+	}
+
+	slogtest.AssertMessagesInOrder(t, rec.Messages(),
+		slogtest.Expect().Message("first"),
+		slogtest.Expect().Message("second"),
+		slogtest.Expect().Message("echo first"),
+		slogtest.Expect().Message("echo second"))
+}
+
+func TestReplaceTarget(t *testing.T) {
+	l := New(0)
+	info := l.Info()
+
+	first := slogtest.NewRecorder()
+	l.SetTarget(first)
+	info.Print("to first")
+
+	second := slogtest.NewRecorder()
+	l.SetTarget(second)
+	info.Print("to second")
+
+	if l.Unwrap() != second {
+		t.Error("Unwrap doesn't return the current target")
+	}
+	slogtest.AssertMessagesInOrder(t, first.Messages(),
+		slogtest.Expect().Level(slog.Info).Message("to first"))
+	slogtest.AssertMessagesInOrder(t, second.Messages(),
+		slogtest.Expect().Level(slog.Info).Message("to second"))
+}
+
+func TestProperties(t *testing.T) {
+	slogtest.CheckProperties(t, func(parent slog.Logger) slog.Logger {
+		l := New(0)
+		l.SetTarget(parent)
+		return l
+	})
+}
@@ -0,0 +1,19 @@
+module darvaza.org/slog/handlers/kafka
+
+go 1.22
+
+replace (
+	darvaza.org/slog => ../../
+	darvaza.org/slog/handlers/cblog => ../cblog
+)
+
+require (
+	darvaza.org/core v0.16.0
+	darvaza.org/slog v0.6.0
+	darvaza.org/slog/handlers/cblog v0.0.0
+)
+
+require (
+	golang.org/x/net v0.34.0 // indirect
+	golang.org/x/text v0.21.0 // indirect
+)
@@ -0,0 +1,196 @@
+// Package kafka provides a slog.Logger that publishes entries
+// as JSON to a Kafka topic
+package kafka
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"darvaza.org/core"
+	"darvaza.org/slog"
+	"darvaza.org/slog/handlers/cblog"
+	"darvaza.org/slog/internal"
+)
+
+const (
+	// DefaultBatchSize is the maximum number of entries
+	// produced at once
+	DefaultBatchSize = 100
+	// DefaultBatchInterval is how long entries can wait before
+	// being produced if the batch isn't full
+	DefaultBatchInterval = time.Second
+	// DefaultWriteTimeout is the maximum time to wait for a
+	// batch to be produced
+	DefaultWriteTimeout = 10 * time.Second
+)
+
+// FailedStat is the name of the counter of entries that failed
+// to be published, reported by Stats()
+const FailedStat = "failed"
+
+// Message is a record to be published
+type Message struct {
+	Time  time.Time
+	Key   []byte
+	Value []byte
+}
+
+// Producer publishes batches of messages to a Kafka topic.
+// Producers are expected to handle retries, and an error
+// means the batch was not delivered. Each batch is passed
+// in a new slice, which the Producer may keep.
+type Producer interface {
+	Produce(ctx context.Context, topic string, msgs []Message) error
+}
+
+// ProducerFunc is a function implementing the Producer interface
+type ProducerFunc func(ctx context.Context, topic string, msgs []Message) error
+
+// Produce calls the function
+func (fn ProducerFunc) Produce(ctx context.Context, topic string, msgs []Message) error {
+	return fn(ctx, topic, msgs)
+}
+
+// Config describes the topic and how entries are produced
+type Config struct {
+	// Producer is the Kafka client publishing the messages
+	Producer Producer
+	// Fallback, if set, receives the entries that couldn't
+	// be delivered
+	Fallback slog.Logger
+	// Clock timestamps the entries and paces the BatchInterval.
+	// Defaults to the system clock
+	Clock cblog.Clock
+	// OnError is called when producing a batch fails,
+	// instead of reporting it using slog.ReportError()
+	OnError func(error)
+
+	// Topic is the Kafka topic entries are published to
+	Topic string
+	// KeyField is the field used as partition key. Entries
+	// without it are produced without key
+	KeyField string
+
+	// BatchSize is the maximum number of entries produced at
+	// once. Defaults to DefaultBatchSize
+	BatchSize int
+	// BufferSize is the number of entries that can wait to be
+	// batched before logging blocks. Defaults to
+	// cblog.DefaultOutputBufferSize
+	BufferSize int
+	// BatchInterval is how long entries can wait before being
+	// produced if the batch isn't full. Defaults to
+	// DefaultBatchInterval
+	BatchInterval time.Duration
+	// WriteTimeout is the maximum time the Producer is given for
+	// each batch. Defaults to DefaultWriteTimeout
+	WriteTimeout time.Duration
+}
+
+// SetDefaults sets the batching and timeouts not specified,
+// and the system clock
+func (cfg *Config) SetDefaults() {
+	if cfg.Clock == nil {
+		cfg.Clock = internal.SystemClock
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = DefaultBatchSize
+	}
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = cblog.DefaultOutputBufferSize
+	}
+	if cfg.BatchInterval <= 0 {
+		cfg.BatchInterval = DefaultBatchInterval
+	}
+	if cfg.WriteTimeout <= 0 {
+		cfg.WriteTimeout = DefaultWriteTimeout
+	}
+}
+
+// Validate checks the Producer and Topic are set
+func (cfg *Config) Validate() error {
+	switch {
+	case cfg.Producer == nil:
+		return core.Wrap(core.ErrInvalid, "kafka: producer not specified")
+	case cfg.Topic == "":
+		return core.Wrap(core.ErrInvalid, "kafka: topic not specified")
+	default:
+		return nil
+	}
+}
+
+// Client publishes the entries of its slog.Logger to Kafka
+type Client struct {
+	logger  *cblog.Logger
+	failed  atomic.Uint64
+	backend internal.Backend
+	cfg     Config
+}
+
+// New creates a Client and starts publishing entries to the
+// Kafka topic described by the Config.
+func New(cfg *Config) (*Client, error) {
+	if cfg == nil {
+		return nil, core.Wrap(core.ErrInvalid, "kafka: config not specified")
+	}
+
+	c := &Client{
+		cfg: *cfg,
+	}
+	c.cfg.SetDefaults()
+	if err := c.cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	c.backend.Init("kafka", c.cfg.OnError)
+
+	logger, ch := cblog.New(make(chan cblog.LogMsg, c.cfg.BufferSize),
+		cblog.WithClock(c.cfg.Clock),
+		cblog.WithHealthCheck(c.Health),
+		cblog.WithStats(c.Stats),
+		cblog.WithFlush(c.backend.Flush))
+	c.logger = logger
+
+	c.backend.Start(logger.Shutdown, func() { c.run(ch) })
+	return c, nil
+}
+
+// Logger returns the slog.Logger whose entries are published
+func (c *Client) Logger() slog.Logger {
+	return c.logger
+}
+
+// FailedCount returns the number of entries that couldn't be
+// delivered, whether passed to the Fallback logger or not
+func (c *Client) FailedCount() uint64 {
+	return c.failed.Load()
+}
+
+// Health returns the last error producing to Kafka,
+// until producing a batch succeeds
+func (c *Client) Health() error {
+	return c.backend.Err()
+}
+
+// Stats returns the number of entries that failed to be published,
+// as FailedStat. Entries waiting to be batched are reported by the
+// cblog.Logger returned by Logger().
+func (c *Client) Stats() slog.Stats {
+	return slog.Stats{
+		FailedStat: c.failed.Load(),
+	}
+}
+
+// Close stops accepting new entries and waits, up to
+// cblog.DefaultShutdownTimeout, for the pending ones to be produced.
+func (c *Client) Close() error {
+	return c.backend.Close(cblog.DefaultShutdownTimeout)
+}
+
+// Shutdown stops accepting new entries and waits for the pending
+// ones to be produced. If the context is cancelled first, the
+// batch in flight is aborted.
+func (c *Client) Shutdown(ctx context.Context) error {
+	return c.backend.Shutdown(ctx)
+}
@@ -0,0 +1,169 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"darvaza.org/slog"
+	slogtest "darvaza.org/slog/internal/testing"
+)
+
+// recordingProducer keeps the batches it's given
+type recordingProducer struct {
+	mu      sync.Mutex
+	batches [][]Message
+	err     error
+}
+
+func (p *recordingProducer) Produce(_ context.Context, _ string, msgs []Message) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.batches = append(p.batches, msgs)
+	return p.err
+}
+
+func (p *recordingProducer) Batches() [][]Message {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([][]Message(nil), p.batches...)
+}
+
+func newTestClient(t *testing.T, cfg *Config) *Client {
+	t.Helper()
+
+	cfg.Topic = "logs"
+	cfg.BatchInterval = time.Hour
+	c, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+	return c
+}
+
+func flush(t *testing.T, c *Client) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := slog.Flush(ctx, c.Logger()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func decode(t *testing.T, msg Message) map[string]any {
+	t.Helper()
+
+	var out map[string]any
+	if err := json.Unmarshal(msg.Value, &out); err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func TestBatchesKept(t *testing.T) {
+	p := new(recordingProducer)
+	c := newTestClient(t, &Config{
+		Producer:  p,
+		BatchSize: 2,
+	})
+
+	l := c.Logger().Info()
+	for _, s := range []string{"a", "b", "c", "d", "e"} {
+		l.Print(s)
+	}
+	flush(t, c)
+
+	// batches retained by the Producer must not be overwritten
+	// by the following ones
+	var got []string
+	for _, b := range p.Batches() {
+		if len(b) > 2 {
+			t.Errorf("batch of %d entries", len(b))
+		}
+		for _, msg := range b {
+			got = append(got, decode(t, msg)[MessageKey].(string))
+		}
+	}
+	if s := strings.Join(got, " "); s != "a b c d e" {
+		t.Errorf("expected \"a b c d e\", got %q", s)
+	}
+}
+
+func TestKeyField(t *testing.T) {
+	p := new(recordingProducer)
+	c := newTestClient(t, &Config{
+		Producer: p,
+		KeyField: "user",
+	})
+
+	l := c.Logger().Info()
+	l.WithField("user", 42).Print("with key")
+	l.Print("without key")
+	flush(t, c)
+
+	batches := p.Batches()
+	if len(batches) != 1 || len(batches[0]) != 2 {
+		t.Fatalf("expected one batch of 2 entries, got %v", batches)
+	}
+	if k := string(batches[0][0].Key); k != "42" {
+		t.Errorf("expected key \"42\", got %q", k)
+	}
+	if k := batches[0][1].Key; k != nil {
+		t.Errorf("expected no key, got %q", k)
+	}
+
+	out := decode(t, batches[0][0])
+	if out[LevelKey] != slog.Info.String() || out["user"] != float64(42) {
+		t.Errorf("unexpected encoding: %v", out)
+	}
+}
+
+func TestFallback(t *testing.T) {
+	rec := slogtest.NewRecorder()
+	p := &recordingProducer{err: errors.New("unreachable")}
+	var reported []error
+	c := newTestClient(t, &Config{
+		Producer: p,
+		Fallback: rec,
+		OnError:  func(err error) { reported = append(reported, err) },
+	})
+
+	l := c.Logger()
+	l.Info().WithField("key", "value").Print("first")
+	l.Warn().Print("second")
+	flush(t, c)
+
+	slogtest.AssertMessagesInOrder(t, rec.Messages(),
+		slogtest.Expect().Level(slog.Info).Message("first").Field("key", "value"),
+		slogtest.Expect().Level(slog.Warn).Message("second"))
+
+	if n := c.FailedCount(); n != 2 {
+		t.Errorf("expected 2 failed entries, got %d", n)
+	}
+	if n := c.Stats()[FailedStat]; n != 2 {
+		t.Errorf("expected %s 2, got %d", FailedStat, n)
+	}
+	if len(reported) != 1 || c.Health() == nil {
+		t.Errorf("expected the error reported once, got %v", reported)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, cfg := range []*Config{
+		nil,
+		{Topic: "logs"},
+		{Producer: new(recordingProducer)},
+	} {
+		if _, err := New(cfg); err == nil {
+			t.Errorf("%+v: expected error", cfg)
+		}
+	}
+}
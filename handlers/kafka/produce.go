@@ -0,0 +1,151 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"darvaza.org/core"
+	"darvaza.org/slog"
+	"darvaza.org/slog/handlers/cblog"
+	"darvaza.org/slog/internal"
+)
+
+// Keys used by the JSON representation of an entry. Fields using
+// these names are overridden.
+const (
+	TimeKey    = "time"
+	LevelKey   = "level"
+	MessageKey = "message"
+	StackKey   = "stack"
+)
+
+// batch holds the entries waiting to be produced, and their
+// encoded form
+type batch struct {
+	entries []cblog.LogMsg
+	msgs    []Message
+}
+
+// reset empties the batch. Messages go in a new slice as
+// the Producer may hold on to the one it was given.
+func (b *batch) reset() {
+	b.entries = b.entries[:0]
+	b.msgs = nil
+}
+
+func (c *Client) run(ch <-chan cblog.LogMsg) {
+	b := new(batch)
+
+	tick := c.cfg.Clock.After(c.cfg.BatchInterval)
+
+	for {
+		select {
+		case msg, ok := <-ch:
+			if !ok {
+				c.produce(b)
+				return
+			}
+
+			c.add(b, msg)
+			if len(b.msgs) >= c.cfg.BatchSize {
+				c.produce(b)
+			}
+		case <-tick:
+			tick = c.cfg.Clock.After(c.cfg.BatchInterval)
+			c.produce(b)
+		case req := <-c.backend.Flushes():
+			c.produce(b)
+			close(req)
+		}
+	}
+}
+
+func (c *Client) add(b *batch, msg cblog.LogMsg) {
+	value, err := Encode(msg)
+	if err != nil {
+		c.backend.ReportError(err)
+		c.fallback([]cblog.LogMsg{msg})
+		return
+	}
+
+	b.entries = append(b.entries, msg)
+	b.msgs = append(b.msgs, Message{
+		Time:  msg.Time,
+		Key:   c.key(msg),
+		Value: value,
+	})
+}
+
+// key returns the partition key of an entry, if it has
+// the KeyField
+func (c *Client) key(msg cblog.LogMsg) []byte {
+	if c.cfg.KeyField == "" {
+		return nil
+	}
+
+	v, ok := msg.Fields[c.cfg.KeyField]
+	if !ok {
+		return nil
+	}
+	return []byte(internal.TextValue(v))
+}
+
+// produce publishes the batch, passing its entries to the
+// Fallback logger if it fails, and resets it
+func (c *Client) produce(b *batch) {
+	if len(b.msgs) == 0 {
+		return
+	}
+	defer b.reset()
+
+	ctx, cancel := context.WithTimeout(c.backend.Context(), c.cfg.WriteTimeout)
+	defer cancel()
+
+	if err := c.cfg.Producer.Produce(ctx, c.cfg.Topic, b.msgs); err != nil {
+		c.backend.ReportError(core.Wrap(err, "kafka: produce"))
+		c.fallback(b.entries)
+	} else {
+		c.backend.ClearError()
+	}
+}
+
+// fallback passes entries that couldn't be delivered to the
+// Fallback logger. Fatal and Panic entries are downgraded
+// to Error as their callers have already terminated.
+func (c *Client) fallback(entries []cblog.LogMsg) {
+	c.failed.Add(uint64(len(entries)))
+
+	l := c.cfg.Fallback
+	if l == nil {
+		return
+	}
+
+	for _, msg := range entries {
+		level := msg.Level
+		if level < slog.Error {
+			level = slog.Error
+		}
+
+		ll := slog.WithTime(l.WithLevel(level), msg.Time)
+		if len(msg.Fields) > 0 {
+			ll = ll.WithFields(msg.Fields)
+		}
+		ll.Print(msg.Message)
+	}
+}
+
+// Encode renders an entry as JSON, with its fields at
+// the top level
+func Encode(msg cblog.LogMsg) ([]byte, error) {
+	out := internal.JSONFields(msg.Fields, 4)
+
+	out[TimeKey] = msg.Time.Format(time.RFC3339Nano)
+	out[LevelKey] = msg.Level.String()
+	out[MessageKey] = msg.Message
+	if len(msg.Stack) > 0 {
+		out[StackKey] = msg.StackFrames()
+	}
+
+	return json.Marshal(out)
+}
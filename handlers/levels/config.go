@@ -0,0 +1,134 @@
+package levels
+
+import (
+	"os"
+	"sort"
+	"strings"
+
+	"darvaza.org/core"
+	"darvaza.org/slog"
+)
+
+const (
+	// DefaultEnvVar is the environment variable read by FromEnv
+	// when none is specified
+	DefaultEnvVar = "LOG_LEVELS"
+
+	// Wildcard is the name matching all loggers
+	Wildcard = "*"
+)
+
+// Config maps logger names to thresholds. A rule applies to the
+// logger with the given name and all its descendants, the most
+// specific one winning. Config is immutable.
+type Config struct {
+	rules []rule
+	def   slog.LogLevel
+}
+
+type rule struct {
+	name  string
+	level slog.LogLevel
+}
+
+// NewConfig creates a Config from a map of logger names to thresholds.
+// The Wildcard sets the threshold of loggers not matching any name,
+// otherwise def is used.
+func NewConfig(def slog.LogLevel, levels map[string]slog.LogLevel) *Config {
+	cfg := &Config{def: def}
+	for name, level := range levels {
+		if name == Wildcard || name == "" {
+			cfg.def = level
+		} else {
+			cfg.rules = append(cfg.rules, rule{name: name, level: level})
+		}
+	}
+
+	// most specific first
+	sort.Slice(cfg.rules, func(i, j int) bool {
+		a, b := cfg.rules[i].name, cfg.rules[j].name
+		if len(a) != len(b) {
+			return len(a) > len(b)
+		}
+		return a < b
+	})
+	return cfg
+}
+
+// Parse reads a Config from a comma separated list of name=level
+// pairs, e.g. "proxy.tls=debug,*=info". A level without name applies
+// to all loggers, and names may end in ".*". Loggers not matching
+// any rule use def.
+func Parse(s string, def slog.LogLevel) (*Config, error) {
+	levels := make(map[string]slog.LogLevel)
+
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		name, value, ok := strings.Cut(part, "=")
+		if !ok {
+			name, value = Wildcard, name
+		}
+		name = strings.TrimSuffix(strings.TrimSpace(name), ".*")
+
+		level, err := slog.ParseLevel(value)
+		if err != nil {
+			return nil, core.Wrapf(err, "levels: %q", part)
+		}
+		levels[name] = level
+	}
+
+	return NewConfig(def, levels), nil
+}
+
+// FromEnv reads a Config from an environment variable, DefaultEnvVar
+// if none is specified. If the variable isn't set, all loggers
+// use def.
+func FromEnv(name string, def slog.LogLevel) (*Config, error) {
+	if name == "" {
+		name = DefaultEnvVar
+	}
+	return Parse(os.Getenv(name), def)
+}
+
+// Threshold returns the most verbose level enabled for a logger
+func (cfg *Config) Threshold(name string) slog.LogLevel {
+	if cfg == nil {
+		return slog.UndefinedLevel
+	}
+
+	for _, r := range cfg.rules {
+		if matchName(name, r.name) {
+			return r.level
+		}
+	}
+	return cfg.def
+}
+
+// matchName tells if a logger name is the prefix or
+// one of its descendants
+func matchName(name, prefix string) bool {
+	if !strings.HasPrefix(name, prefix) {
+		return false
+	}
+	return len(name) == len(prefix) || name[len(prefix)] == '.'
+}
+
+// String renders the Config in the format read by Parse
+func (cfg *Config) String() string {
+	if cfg == nil {
+		return ""
+	}
+
+	parts := make([]string, 0, len(cfg.rules)+1)
+	for _, r := range cfg.rules {
+		parts = append(parts, r.name+"="+r.level.String())
+	}
+	if cfg.def > slog.UndefinedLevel {
+		parts = append(parts, Wildcard+"="+cfg.def.String())
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,108 @@
+// Package levels provides per-component thresholds for
+// slog.Logger, based on the name of the loggers
+package levels
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"darvaza.org/slog"
+	"darvaza.org/slog/internal"
+)
+
+// Registry holds the Config in use by loggers, allowing
+// it to be replaced atomically while they are in use
+type Registry struct {
+	cfg      atomic.Pointer[Config]
+	watchers internal.Observers[*watcher]
+	mu       sync.Mutex
+}
+
+// watcher follows the threshold of a logger name
+type watcher struct {
+	fn    func(old, level slog.LogLevel)
+	name  string
+	level slog.LogLevel
+}
+
+// NewRegistry creates a Registry using the given Config
+func NewRegistry(cfg *Config) *Registry {
+	r := new(Registry)
+	r.Set(cfg)
+	return r
+}
+
+// Set replaces the Config, affecting all loggers
+// using the Registry, and calls the functions registered
+// using Watch() whose threshold changed
+func (r *Registry) Set(cfg *Config) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cfg.Store(cfg)
+	r.watchers.Each(func(w *watcher) {
+		if level := cfg.Threshold(w.name); level != w.level {
+			old := w.level
+			w.level = level
+			w.fn(old, level)
+		}
+	})
+}
+
+// Watch registers a function to be called when Set() changes
+// the threshold of loggers with the given name, returning a
+// function to unregister it. The function is called by Set()
+// and can't call Set() or Watch() itself.
+func (r *Registry) Watch(name string, fn func(old, level slog.LogLevel)) (remove func()) {
+	if fn == nil {
+		return func() {}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.watchers.Add(&watcher{
+		fn:    fn,
+		name:  name,
+		level: r.Threshold(name),
+	})
+}
+
+// Get returns the current Config
+func (r *Registry) Get() *Config {
+	return r.cfg.Load()
+}
+
+// Threshold returns the most verbose level enabled for
+// a logger using the current Config
+func (r *Registry) Threshold(name string) slog.LogLevel {
+	return r.Get().Threshold(name)
+}
+
+// Enabled tells if entries of the given level are enabled
+// for a logger. Loggers without level and rules without
+// threshold are always enabled.
+func (r *Registry) Enabled(name string, level slog.LogLevel) bool {
+	if level <= slog.UndefinedLevel {
+		return true
+	}
+
+	threshold := r.Threshold(name)
+	return threshold <= slog.UndefinedLevel || level <= threshold
+}
+
+// New creates a slog.Logger that enforces the thresholds of the
+// Registry on top of the parent's, based on the name given using
+// slog.WithName(). Fatal and Panic entries are always passed to
+// the parent.
+func New(parent slog.Logger, r *Registry) slog.Logger {
+	if parent == nil || r == nil {
+		return parent
+	}
+
+	return internal.NewWrapper(parent, &internal.WrapperHooks{
+		Enabled: func(ll *internal.Loglet) bool {
+			return r.Enabled(ll.Name(), ll.Level())
+		},
+	})
+}
@@ -0,0 +1,117 @@
+package levels
+
+import (
+	"reflect"
+	"testing"
+
+	"darvaza.org/slog"
+	slogtest "darvaza.org/slog/internal/testing"
+)
+
+func TestParse(t *testing.T) {
+	cfg, err := Parse("proxy=warn, proxy.tls.*=debug,error", slog.Info)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s := cfg.String(); s != "proxy.tls=debug,proxy=warn,*=error" {
+		t.Errorf("unexpected config %q", s)
+	}
+
+	for _, s := range []string{"proxy=loud", "=", "x=1=2"} {
+		if _, err := Parse(s, slog.Info); err == nil {
+			t.Errorf("%q: accepted", s)
+		}
+	}
+}
+
+func TestThreshold(t *testing.T) {
+	cfg := NewConfig(slog.Info, map[string]slog.LogLevel{
+		"proxy":     slog.Warn,
+		"proxy.tls": slog.Debug,
+	})
+
+	for name, want := range map[string]slog.LogLevel{
+		"":              slog.Info,
+		"proxy":         slog.Warn,
+		"proxy.http":    slog.Warn,
+		"proxy.tls":     slog.Debug,
+		"proxy.tls.sni": slog.Debug,
+		"proxyish":      slog.Info,
+	} {
+		if got := cfg.Threshold(name); got != want {
+			t.Errorf("%q: expected %s, got %s", name, want, got)
+		}
+	}
+}
+
+func TestNamedLoggers(t *testing.T) {
+	cfg, _ := Parse("proxy=warn,proxy.tls=debug", slog.Info)
+	r := NewRegistry(cfg)
+
+	rec := slogtest.NewRecorder()
+	l := New(rec, r)
+	proxy := slog.WithName(l, "proxy")
+	tls := slog.WithName(proxy, "tls")
+
+	l.Debug().Print("root debug")
+	l.Info().Print("root info")
+	proxy.Info().Print("proxy info")
+	proxy.Warn().Print("proxy warn")
+	tls.Debug().Print("tls debug")
+
+	slogtest.AssertMessagesInOrder(t, rec.Messages(),
+		slogtest.Expect().Level(slog.Info).Message("root info"),
+		slogtest.Expect().Level(slog.Warn).Message("proxy warn").
+			Field(slog.NameFieldName, "proxy"),
+		slogtest.Expect().Level(slog.Debug).Message("tls debug").
+			Field(slog.NameFieldName, "proxy.tls"))
+
+	// affects loggers in use
+	r.Set(NewConfig(slog.Error, nil))
+	rec.Reset()
+	tls.Warn().Print("tls warn")
+	proxy.Error().Print("proxy error")
+	l.Fatal().Print("fatal")
+
+	slogtest.AssertMessagesInOrder(t, rec.Messages(),
+		slogtest.Expect().Level(slog.Error).Message("proxy error"),
+		slogtest.Expect().Level(slog.Fatal).Message("fatal"))
+}
+
+func TestWatch(t *testing.T) {
+	r := NewRegistry(NewConfig(slog.Info, nil))
+
+	var calls []string
+	watch := func(id string) func(old, level slog.LogLevel) {
+		return func(old, level slog.LogLevel) {
+			calls = append(calls, id+":"+old.String()+">"+level.String())
+		}
+	}
+
+	stop := r.Watch("proxy", watch("a"))
+	r.Watch("proxy.tls", watch("b"))
+	r.Watch("other", watch("c"))
+	r.Watch("proxy", nil)()
+
+	r.Set(NewConfig(slog.Info, map[string]slog.LogLevel{"proxy": slog.Debug}))
+	stop()
+	r.Set(NewConfig(slog.Warn, nil))
+
+	want := []string{
+		"a:info>debug",
+		"b:info>debug",
+		"b:debug>warn",
+		"c:info>warn",
+	}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("expected %q, got %q", want, calls)
+	}
+}
+
+func TestProperties(t *testing.T) {
+	r := NewRegistry(NewConfig(slog.Debug, nil))
+	slogtest.CheckProperties(t, func(parent slog.Logger) slog.Logger {
+		return New(parent, r)
+	})
+}
@@ -13,7 +13,8 @@ import (
 )
 
 var (
-	_ slog.Logger = (*Logger)(nil)
+	_ slog.Logger      = (*Logger)(nil)
+	_ slog.NamedLogger = (*Logger)(nil)
 )
 
 const (
@@ -132,7 +133,7 @@ func (rl *Logger) WithStack(skip int) slog.Logger {
 
 			entry := rl.entry.WithFields(logrus.Fields{
 				CallerFieldName: fmt.Sprintf("%+n", caller),
-				StackFieldName:  fmt.Sprintf("%+n", frames),
+				StackFieldName:  slog.FormatStack(frames),
 			})
 
 			return rl.dup(entry)
@@ -141,11 +142,22 @@ func (rl *Logger) WithStack(skip int) slog.Logger {
 	return rl
 }
 
+// WithName appends a component to the dot-separated name of the
+// log entry, stored as slog.NameFieldName field
+func (rl *Logger) WithName(name string) slog.Logger {
+	if rl.Enabled() && name != "" {
+		parent, _ := rl.entry.Data[slog.NameFieldName].(string)
+		entry := rl.entry.WithField(slog.NameFieldName, slog.JoinName(parent, name))
+		return rl.dup(entry)
+	}
+	return rl
+}
+
 // WithField adds a field to the log entry
 func (rl *Logger) WithField(label string, value any) slog.Logger {
 	if rl.Enabled() && label != "" {
 		entry := rl.entry.WithFields(logrus.Fields{
-			label: value,
+			label: slog.ResolveValue(value),
 		})
 		return rl.dup(entry)
 	}
@@ -158,7 +170,12 @@ func (rl *Logger) WithFields(fields map[string]any) slog.Logger {
 		delete(fields, "")
 
 		if len(fields) > 0 {
-			entry := rl.entry.WithFields(fields)
+			m := make(logrus.Fields, len(fields))
+			for k, v := range fields {
+				m[k] = slog.ResolveValue(v)
+			}
+
+			entry := rl.entry.WithFields(m)
 			return rl.dup(entry)
 		}
 	}
@@ -0,0 +1,194 @@
+// Package loki provides a slog.Logger that pushes entries
+// to Grafana Loki
+package loki
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"darvaza.org/core"
+	"darvaza.org/slog"
+	"darvaza.org/slog/handlers/cblog"
+	"darvaza.org/slog/internal"
+)
+
+const (
+	// DefaultBatchSize is the maximum number of entries per push
+	DefaultBatchSize = 256
+	// DefaultBatchInterval is how long entries can wait before
+	// being pushed if the batch isn't full
+	DefaultBatchInterval = time.Second
+	// DefaultMaxRetries is the number of times a failed push
+	// is retried before discarding the batch
+	DefaultMaxRetries = 5
+	// DefaultMinBackoff is the wait before the first retry
+	DefaultMinBackoff = 500 * time.Millisecond
+	// DefaultMaxBackoff is the maximum wait between retries
+	DefaultMaxBackoff = 30 * time.Second
+
+	// LevelLabel is the label carrying the level of the entries
+	LevelLabel = "level"
+)
+
+// Config describes the Loki endpoint and how entries are pushed
+type Config struct {
+	// Client is the HTTP client used. Defaults to http.DefaultClient
+	Client *http.Client
+	// Header is added to every request, e.g. Authorization or
+	// X-Scope-OrgID
+	Header http.Header
+	// Labels are attached to every stream
+	Labels map[string]string
+	// Clock timestamps the entries and paces the BatchInterval
+	// and retries. Defaults to the system clock
+	Clock cblog.Clock
+	// OnError is called when a push fails,
+	// instead of reporting it using slog.ReportError()
+	OnError func(error)
+
+	// URL is the push endpoint, e.g.
+	// http://localhost:3100/loki/api/v1/push
+	URL string
+
+	// LabelFields are fields promoted to labels instead of
+	// being included in the line
+	LabelFields []string
+
+	// BatchSize is the maximum number of entries per push.
+	// Defaults to DefaultBatchSize
+	BatchSize int
+	// MaxRetries is the number of times a failed push is retried
+	// before dropping the batch. Defaults to DefaultMaxRetries,
+	// and negative values disable retries
+	MaxRetries int
+	// BufferSize is the number of entries that can wait to be
+	// batched before logging blocks. Defaults to
+	// cblog.DefaultOutputBufferSize
+	BufferSize int
+	// BatchInterval is how long entries can wait before being
+	// pushed if the batch isn't full. Defaults to
+	// DefaultBatchInterval
+	BatchInterval time.Duration
+	// MinBackoff is the wait before the first retry, doubled on
+	// each one up to MaxBackoff. Defaults to DefaultMinBackoff
+	// and DefaultMaxBackoff
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+// SetDefaults sets the HTTP client, batching and retries not
+// specified, and the system clock
+func (cfg *Config) SetDefaults() {
+	if cfg.Clock == nil {
+		cfg.Clock = internal.SystemClock
+	}
+	if cfg.Client == nil {
+		cfg.Client = http.DefaultClient
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = DefaultBatchSize
+	}
+	if cfg.MaxRetries < 0 {
+		cfg.MaxRetries = 0
+	} else if cfg.MaxRetries == 0 {
+		cfg.MaxRetries = DefaultMaxRetries
+	}
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = cblog.DefaultOutputBufferSize
+	}
+	if cfg.BatchInterval <= 0 {
+		cfg.BatchInterval = DefaultBatchInterval
+	}
+	if cfg.MinBackoff <= 0 {
+		cfg.MinBackoff = DefaultMinBackoff
+	}
+	if cfg.MaxBackoff < cfg.MinBackoff {
+		cfg.MaxBackoff = max(DefaultMaxBackoff, cfg.MinBackoff)
+	}
+}
+
+// Validate checks the push URL is set
+func (cfg *Config) Validate() error {
+	if cfg.URL == "" {
+		return core.Wrap(core.ErrInvalid, "loki: URL not specified")
+	}
+	return nil
+}
+
+// Client pushes the entries of its slog.Logger to Loki
+type Client struct {
+	logger  *cblog.Logger
+	dropped atomic.Uint64
+	backend internal.Backend
+	cfg     Config
+}
+
+// New creates a Client and starts pushing entries to the
+// Loki endpoint described by the Config.
+func New(cfg *Config) (*Client, error) {
+	if cfg == nil {
+		return nil, core.Wrap(core.ErrInvalid, "loki: config not specified")
+	}
+
+	c := &Client{
+		cfg: *cfg,
+	}
+	c.cfg.SetDefaults()
+	if err := c.cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	c.backend.Init("loki", c.cfg.OnError)
+
+	logger, ch := cblog.New(make(chan cblog.LogMsg, c.cfg.BufferSize),
+		cblog.WithClock(c.cfg.Clock),
+		cblog.WithHealthCheck(c.Health),
+		cblog.WithStats(c.Stats),
+		cblog.WithFlush(c.backend.Flush))
+	c.logger = logger
+
+	c.backend.Start(logger.Shutdown, func() { c.run(ch) })
+	return c, nil
+}
+
+// Logger returns the slog.Logger whose entries are pushed
+func (c *Client) Logger() slog.Logger {
+	return c.logger
+}
+
+// DroppedCount returns the number of entries discarded after
+// exhausting the retries
+func (c *Client) DroppedCount() uint64 {
+	return c.dropped.Load()
+}
+
+// Health returns the last error pushing to Loki,
+// until a push succeeds
+func (c *Client) Health() error {
+	return c.backend.Err()
+}
+
+// Stats returns the number of entries dropped by the Client, as
+// slog.DroppedStat, when a push fails after MaxRetries or can't be
+// encoded. Entries waiting to be batched are reported by the
+// cblog.Logger returned by Logger().
+func (c *Client) Stats() slog.Stats {
+	return slog.Stats{
+		slog.DroppedStat: c.dropped.Load(),
+	}
+}
+
+// Close stops accepting new entries and waits, up to
+// cblog.DefaultShutdownTimeout, for the pending ones to be pushed.
+func (c *Client) Close() error {
+	return c.backend.Close(cblog.DefaultShutdownTimeout)
+}
+
+// Shutdown stops accepting new entries and waits for the pending
+// ones to be pushed. If the context is cancelled first, retries
+// are aborted.
+func (c *Client) Shutdown(ctx context.Context) error {
+	return c.backend.Shutdown(ctx)
+}
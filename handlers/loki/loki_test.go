@@ -0,0 +1,190 @@
+package loki
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"darvaza.org/slog"
+)
+
+// testServer is a Loki push endpoint recording the requests,
+// and answering with the given status codes in turn
+type testServer struct {
+	mu       sync.Mutex
+	requests []pushRequest
+	headers  []http.Header
+	codes    []int
+}
+
+func (s *testServer) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	var body pushRequest
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, body)
+	s.headers = append(s.headers, req.Header)
+
+	code := http.StatusNoContent
+	if n := len(s.codes); n > 0 {
+		code = s.codes[0]
+		if n > 1 {
+			s.codes = s.codes[1:]
+		}
+	}
+	rw.WriteHeader(code)
+}
+
+func (s *testServer) Requests() []pushRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]pushRequest(nil), s.requests...)
+}
+
+func newTestClient(t *testing.T, cfg *Config, codes ...int) (*Client, *testServer) {
+	t.Helper()
+
+	s := &testServer{codes: codes}
+	srv := httptest.NewServer(s)
+	t.Cleanup(srv.Close)
+
+	cfg.URL = srv.URL
+	cfg.BatchInterval = time.Hour
+	cfg.MinBackoff = time.Millisecond
+	c, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+	return c, s
+}
+
+func flush(t *testing.T, c *Client) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := slog.Flush(ctx, c.Logger()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPush(t *testing.T) {
+	c, s := newTestClient(t, &Config{
+		Header:      http.Header{"X-Scope-Orgid": {"tenant"}},
+		Labels:      map[string]string{"app": "test"},
+		LabelFields: []string{"component"},
+	})
+
+	l := c.Logger()
+	l.Info().WithField("component", "db").WithField("n", 1).Print("first")
+	l.Info().WithField("component", "db").Print("second")
+	l.Warn().Print("third")
+	flush(t, c)
+
+	reqs := s.Requests()
+	if len(reqs) != 1 {
+		t.Fatalf("expected 1 push, got %d", len(reqs))
+	}
+	if h := s.headers[0].Get("X-Scope-OrgID"); h != "tenant" {
+		t.Errorf("expected X-Scope-OrgID header, got %q", h)
+	}
+
+	streams := make(map[string]stream)
+	for _, st := range reqs[0].Streams {
+		if st.Stream["app"] != "test" {
+			t.Errorf("missing app label: %v", st.Stream)
+		}
+		streams[labelsKey(st.Stream)] = st
+	}
+
+	db := streams[labelsKey(map[string]string{
+		"app": "test", "component": "db", LevelLabel: "info",
+	})]
+	if len(db.Values) != 2 {
+		t.Fatalf("expected 2 entries on the db stream, got %v", reqs[0].Streams)
+	}
+	if line := db.Values[0][1]; line != `{"message":"first","n":1}` {
+		t.Errorf("unexpected line %s", line)
+	}
+
+	warn := streams[labelsKey(map[string]string{"app": "test", LevelLabel: "warn"})]
+	if len(warn.Values) != 1 || warn.Values[0][1] != `{"message":"third"}` {
+		t.Errorf("unexpected warn stream: %v", warn)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	c, s := newTestClient(t, &Config{},
+		http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusNoContent)
+
+	c.Logger().Info().Print("retried")
+	flush(t, c)
+
+	if n := len(s.Requests()); n != 3 {
+		t.Errorf("expected 3 attempts, got %d", n)
+	}
+	if n := c.DroppedCount(); n != 0 {
+		t.Errorf("unexpected %d entries dropped", n)
+	}
+	if err := c.Health(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestDropped(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		code     int
+		retries  int
+		attempts int
+	}{
+		{"retries exhausted", http.StatusInternalServerError, 2, 3},
+		{"retries disabled", http.StatusInternalServerError, -1, 1},
+		{"not retryable", http.StatusBadRequest, 2, 1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var reported int
+			c, s := newTestClient(t, &Config{
+				MaxRetries: tc.retries,
+				OnError:    func(error) { reported++ },
+			}, tc.code)
+
+			l := c.Logger().Info()
+			l.Print("first")
+			l.Print("second")
+			flush(t, c)
+
+			if n := len(s.Requests()); n != tc.attempts {
+				t.Errorf("expected %d attempts, got %d", tc.attempts, n)
+			}
+			if reported != tc.attempts {
+				t.Errorf("expected %d errors reported, got %d", tc.attempts, reported)
+			}
+			if n := c.Stats()[slog.DroppedStat]; n != 2 {
+				t.Errorf("expected %s 2, got %d", slog.DroppedStat, n)
+			}
+			if c.Health() == nil {
+				t.Error("expected unhealthy")
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, cfg := range []*Config{nil, {}} {
+		if _, err := New(cfg); err == nil {
+			t.Errorf("%+v: expected error", cfg)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package loki
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"darvaza.org/core"
+	"darvaza.org/slog"
+	"darvaza.org/slog/handlers/cblog"
+	"darvaza.org/slog/internal"
+)
+
+type pushRequest struct {
+	Streams []stream `json:"streams"`
+}
+
+type stream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// batch groups entries by their labels
+type batch struct {
+	streams map[string]*stream
+	size    int
+}
+
+func (c *Client) run(ch <-chan cblog.LogMsg) {
+	b := new(batch)
+
+	tick := c.cfg.Clock.After(c.cfg.BatchInterval)
+
+	for {
+		select {
+		case msg, ok := <-ch:
+			if !ok {
+				c.push(b)
+				return
+			}
+
+			b.add(c.labels(msg), c.line(msg), msg.Time)
+			if b.size >= c.cfg.BatchSize {
+				c.push(b)
+			}
+		case <-tick:
+			tick = c.cfg.Clock.After(c.cfg.BatchInterval)
+			c.push(b)
+		case req := <-c.backend.Flushes():
+			c.push(b)
+			close(req)
+		}
+	}
+}
+
+func (b *batch) add(labels map[string]string, line string, ts time.Time) {
+	key := labelsKey(labels)
+
+	if b.streams == nil {
+		b.streams = make(map[string]*stream)
+	}
+
+	s, ok := b.streams[key]
+	if !ok {
+		s = &stream{Stream: labels}
+		b.streams[key] = s
+	}
+
+	s.Values = append(s.Values, [2]string{
+		strconv.FormatInt(ts.UnixNano(), 10),
+		line,
+	})
+	b.size++
+}
+
+func (b *batch) reset() {
+	b.streams = nil
+	b.size = 0
+}
+
+func labelsKey(labels map[string]string) string {
+	var buf strings.Builder
+
+	for _, k := range core.SortedKeys(labels) {
+		_, _ = fmt.Fprintf(&buf, "%s=%q,", k, labels[k])
+	}
+	return buf.String()
+}
+
+// labels returns the labels of the stream an entry belongs to
+func (c *Client) labels(msg cblog.LogMsg) map[string]string {
+	labels := make(map[string]string, len(c.cfg.Labels)+len(c.cfg.LabelFields)+1)
+	for k, v := range c.cfg.Labels {
+		labels[k] = v
+	}
+
+	for _, k := range c.cfg.LabelFields {
+		if v, ok := msg.Fields[k]; ok {
+			labels[k] = internal.TextValue(v)
+		}
+	}
+
+	labels[LevelLabel] = msg.Level.String()
+	return labels
+}
+
+// line renders an entry as JSON, excluding the fields
+// promoted to labels
+func (c *Client) line(msg cblog.LogMsg) string {
+	out := internal.JSONFields(msg.Fields, 2)
+
+	for _, k := range c.cfg.LabelFields {
+		delete(out, k)
+	}
+
+	out["message"] = msg.Message
+	if len(msg.Stack) > 0 {
+		out["stack"] = slog.FormatStack(msg.Stack)
+	}
+
+	b, err := json.Marshal(out)
+	if err != nil {
+		return msg.Message
+	}
+	return string(b)
+}
+
+// push sends the batch, retrying with exponential backoff, and
+// resets it
+func (c *Client) push(b *batch) {
+	if b.size == 0 {
+		return
+	}
+	defer b.reset()
+
+	req := pushRequest{
+		Streams: make([]stream, 0, len(b.streams)),
+	}
+	for _, s := range b.streams {
+		req.Streams = append(req.Streams, *s)
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		c.backend.ReportError(err)
+		c.dropped.Add(uint64(b.size))
+		return
+	}
+
+	backoff := c.cfg.MinBackoff
+	for retries := 0; ; retries++ {
+		retry, err := c.send(body)
+		switch {
+		case err == nil:
+			c.backend.ClearError()
+			return
+		case !retry, retries >= c.cfg.MaxRetries:
+			c.backend.ReportError(err)
+			c.dropped.Add(uint64(b.size))
+			return
+		}
+
+		c.backend.ReportError(err)
+		if !c.sleep(backoff) {
+			// aborted
+			c.dropped.Add(uint64(b.size))
+			return
+		}
+		backoff = min(2*backoff, c.cfg.MaxBackoff)
+	}
+}
+
+// send makes one push attempt, and tells if a failure
+// is worth retrying
+func (c *Client) send(body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(c.backend.Context(), http.MethodPost,
+		c.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+
+	for k, vv := range c.cfg.Header {
+		req.Header[k] = vv
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.cfg.Client.Do(req)
+	if err != nil {
+		return c.backend.Context().Err() == nil, err
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	switch code := resp.StatusCode; {
+	case code >= 200 && code < 300:
+		return false, nil
+	case code == http.StatusTooManyRequests, code >= 500:
+		return true, fmt.Errorf("loki: %s", resp.Status)
+	default:
+		return false, fmt.Errorf("loki: %s", resp.Status)
+	}
+}
+
+func (c *Client) sleep(d time.Duration) bool {
+	select {
+	case <-c.cfg.Clock.After(d):
+		return true
+	case <-c.backend.Context().Done():
+		return false
+	}
+}
@@ -0,0 +1,159 @@
+package mask
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Detector finds a kind of sensitive data in text
+type Detector struct {
+	// Pattern matches the sensitive data
+	Pattern *regexp.Regexp
+	// Valid, if set, confirms a match is really sensitive data,
+	// e.g. checking the digits of a card number
+	Valid func(match string) bool
+
+	// Name identifies the kind of data, and is used as mask
+	// when none is given, e.g. "[email]"
+	Name string
+	// Hint, if set, tells cheaply if the text may contain
+	// the data, so the Pattern is only tried when it does
+	Hint func(s string) bool
+	// Mask replaces each match, and can refer to submatches
+	// as described by regexp.Regexp.Expand
+	Mask string
+}
+
+// Built-in detectors
+var (
+	// Email detects e-mail addresses
+	Email = &Detector{
+		Name:    "email",
+		Hint:    containsString("@"),
+		Pattern: regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9\-]+(?:\.[A-Za-z0-9\-]+)*\.[A-Za-z]{2,}`),
+	}
+
+	// CreditCard detects payment card numbers, optionally grouped
+	// by spaces or dashes, passing the Luhn check
+	CreditCard = &Detector{
+		Name:    "card",
+		Hint:    countDigits(13),
+		Pattern: regexp.MustCompile(`\b(?:\d[ \-]?){12,18}\d\b`),
+		Valid:   luhnValid,
+	}
+
+	// IPv4 detects IPv4 addresses
+	IPv4 = &Detector{
+		Name:    "ipv4",
+		Hint:    countByte('.', 3),
+		Pattern: regexp.MustCompile(`\b(?:(?:25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)\.){3}(?:25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)\b`),
+	}
+
+	// BearerToken detects the credentials of bearer authorisation
+	// headers, keeping the scheme
+	BearerToken = &Detector{
+		Name:    "token",
+		Hint:    containsFold("bearer"),
+		Pattern: regexp.MustCompile(`(?i)\b(bearer\s+)[A-Za-z0-9\-._~+/]+=*`),
+		Mask:    "${1}[token]",
+	}
+)
+
+// DefaultDetectors returns the built-in detectors
+func DefaultDetectors() []*Detector {
+	return []*Detector{Email, CreditCard, IPv4, BearerToken}
+}
+
+// Replace masks every occurrence of the sensitive data in s
+func (d *Detector) Replace(s string) string {
+	switch {
+	case d.Hint != nil && !d.Hint(s), !d.Pattern.MatchString(s):
+		// untouched, and without allocations
+		return s
+	}
+
+	mask := d.mask()
+	if d.Valid == nil {
+		return d.Pattern.ReplaceAllString(s, mask)
+	}
+
+	return d.Pattern.ReplaceAllStringFunc(s, func(match string) string {
+		if !d.Valid(match) {
+			return match
+		}
+		return d.Pattern.ReplaceAllString(match, mask)
+	})
+}
+
+func (d *Detector) mask() string {
+	if d.Mask != "" {
+		return d.Mask
+	}
+	return "[" + d.Name + "]"
+}
+
+// luhnValid checks the digits of a card number
+// using the Luhn algorithm
+func luhnValid(s string) bool {
+	var sum, n int
+
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+
+		d := int(c - '0')
+		if n%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return n > 0 && sum%10 == 0
+}
+
+func containsString(sub string) func(string) bool {
+	return func(s string) bool {
+		return strings.Contains(s, sub)
+	}
+}
+
+// containsFold checks for an ASCII lowercase word
+// regardless of case
+func containsFold(word string) func(string) bool {
+	return func(s string) bool {
+		for i := 0; i+len(word) <= len(s); i++ {
+			if strings.EqualFold(s[i:i+len(word)], word) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// countByte checks for at least n occurrences of c
+func countByte(c byte, n int) func(string) bool {
+	return func(s string) bool {
+		return strings.Count(s, string(c)) >= n
+	}
+}
+
+// countDigits checks for at least n decimal digits
+func countDigits(n int) func(string) bool {
+	return func(s string) bool {
+		count := 0
+		for i := 0; i < len(s); i++ {
+			if s[i] >= '0' && s[i] <= '9' {
+				count++
+				if count == n {
+					return true
+				}
+			}
+		}
+		return false
+	}
+}
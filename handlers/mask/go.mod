@@ -0,0 +1,13 @@
+module darvaza.org/slog/handlers/mask
+
+go 1.22
+
+replace darvaza.org/slog => ../../
+
+require darvaza.org/slog v0.6.0
+
+require (
+	darvaza.org/core v0.16.0 // indirect
+	golang.org/x/net v0.34.0 // indirect
+	golang.org/x/text v0.21.0 // indirect
+)
@@ -0,0 +1,98 @@
+// Package mask provides a slog.Logger wrapper that masks personal
+// and sensitive data in messages and field values before passing
+// them to the backend
+package mask
+
+import (
+	"fmt"
+
+	"darvaza.org/slog"
+	"darvaza.org/slog/internal"
+)
+
+// Config describes what is masked
+type Config struct {
+	// Detectors find the data to be masked.
+	// Defaults to DefaultDetectors()
+	Detectors []*Detector
+
+	// SkipMessage leaves messages untouched
+	SkipMessage bool
+	// SkipFields leaves field values untouched
+	SkipFields bool
+}
+
+// SetDefaults uses DefaultDetectors() if none are given
+func (cfg *Config) SetDefaults() {
+	if len(cfg.Detectors) == 0 {
+		cfg.Detectors = DefaultDetectors()
+	}
+}
+
+// New creates a slog.Logger that masks what the Config's Detectors
+// find in messages and field values before passing them to the parent.
+// Strings are masked, as are the text of errors and fmt.Stringers, which
+// are replaced by it only if something was found.
+func New(parent slog.Logger, cfg *Config) slog.Logger {
+	if parent == nil {
+		return nil
+	}
+
+	var c Config
+	if cfg != nil {
+		c = *cfg
+	}
+	c.SetDefaults()
+
+	hooks := new(internal.WrapperHooks)
+	if !c.SkipMessage {
+		hooks.Print = c.maskMessage
+	}
+	if !c.SkipFields {
+		hooks.Field = c.maskField
+	}
+
+	return internal.NewWrapper(parent, hooks)
+}
+
+// String masks the sensitive data found in s
+func (cfg *Config) String(s string) string {
+	for _, d := range cfg.Detectors {
+		s = d.Replace(s)
+	}
+	return s
+}
+
+func (cfg *Config) maskMessage(e *internal.Entry) bool {
+	e.Message = cfg.String(e.Message)
+	return true
+}
+
+func (cfg *Config) maskField(key string, value any) (string, any, bool) {
+	if lazy, ok := value.(slog.Lazy); ok {
+		return key, slog.LazyValue(func() any {
+			return cfg.maskValue(lazy.Value())
+		}), true
+	}
+	return key, cfg.maskValue(value), true
+}
+
+func (cfg *Config) maskValue(value any) any {
+	var s string
+
+	switch v := value.(type) {
+	case string:
+		return cfg.String(v)
+	case error:
+		s = v.Error()
+	case fmt.Stringer:
+		s = v.String()
+	default:
+		return value
+	}
+
+	if masked := cfg.String(s); masked != s {
+		return masked
+	}
+	return value
+}
@@ -0,0 +1,85 @@
+package mask
+
+import (
+	"errors"
+	"testing"
+
+	slogtest "darvaza.org/slog/internal/testing"
+)
+
+func TestString(t *testing.T) {
+	var cfg Config
+	cfg.SetDefaults()
+
+	for _, tc := range []struct {
+		in, expected string
+	}{
+		{"nothing to see", "nothing to see"},
+		{"mail john.doe@example.com now", "mail [email] now"},
+		{"card 4111 1111 1111 1111 charged", "card [card] charged"},
+		{"not a card 4111 1111 1111 1112", "not a card 4111 1111 1111 1112"},
+		{"from 192.168.0.1", "from [ipv4]"},
+		{"version 1.2.3.400", "version 1.2.3.400"},
+		{"Authorization: Bearer abc.def-123", "Authorization: Bearer [token]"},
+	} {
+		if got := cfg.String(tc.in); got != tc.expected {
+			t.Errorf("%q: expected %q, got %q", tc.in, tc.expected, got)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	rec := slogtest.NewRecorder()
+	l := New(rec, nil)
+
+	l.Info().
+		WithField("email", "john.doe@example.com").
+		WithField("err", errors.New("denied to 10.0.0.1")).
+		WithField("count", 1).
+		Print("login by john.doe@example.com")
+
+	msgs := rec.Messages()
+	if len(msgs) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(msgs))
+	}
+
+	m := msgs[0]
+	switch {
+	case m.Message != "login by [email]":
+		t.Errorf("message not masked: %q", m.Message)
+	case m.Fields["email"] != "[email]":
+		t.Errorf("field not masked: %v", m.Fields["email"])
+	case m.Fields["err"] != "denied to [ipv4]":
+		t.Errorf("error not masked: %v", m.Fields["err"])
+	case m.Fields["count"] != 1:
+		t.Errorf("field modified: %v", m.Fields["count"])
+	}
+}
+
+func BenchmarkString(b *testing.B) {
+	var cfg Config
+	cfg.SetDefaults()
+
+	for name, s := range map[string]string{
+		"clean": "GET /api/v1/items returned 200 in 15ms",
+		"dirty": "login by john.doe@example.com from 192.168.0.1 with Bearer abc123",
+	} {
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = cfg.String(s)
+			}
+		})
+	}
+}
+
+func BenchmarkField(b *testing.B) {
+	var cfg Config
+	cfg.SetDefaults()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _, _ = cfg.maskField("user", "john.doe@example.com")
+		_, _, _ = cfg.maskField("count", i)
+	}
+}
@@ -0,0 +1,112 @@
+package metrics
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"darvaza.org/core"
+	"darvaza.org/slog"
+)
+
+var (
+	_ Recorder = (*Counters)(nil)
+)
+
+// levels is the number of slog.LogLevel values, including
+// slog.UndefinedLevel
+const levels = int(slog.Debug) + 1
+
+type levelCounters [levels]atomic.Uint64
+
+// Counters is a Recorder keeping in-memory counts of entries
+// per level and logger name
+type Counters struct {
+	mu    sync.RWMutex
+	names map[string]*levelCounters
+}
+
+// NewCounters creates a new Counters
+func NewCounters() *Counters {
+	return &Counters{
+		names: make(map[string]*levelCounters),
+	}
+}
+
+// Record counts an entry
+func (c *Counters) Record(level slog.LogLevel, name string) {
+	if level < slog.UndefinedLevel || int(level) >= levels {
+		level = slog.UndefinedLevel
+	}
+
+	c.get(name)[level].Add(1)
+}
+
+func (c *Counters) get(name string) *levelCounters {
+	c.mu.RLock()
+	lc, ok := c.names[name]
+	c.mu.RUnlock()
+
+	if !ok {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		if lc, ok = c.names[name]; !ok {
+			lc = new(levelCounters)
+			c.names[name] = lc
+		}
+	}
+	return lc
+}
+
+// Count returns the number of entries of the given level
+// across all loggers
+func (c *Counters) Count(level slog.LogLevel) uint64 {
+	var n uint64
+
+	c.Each(func(_ string, lvl slog.LogLevel, count uint64) {
+		if lvl == level {
+			n += count
+		}
+	})
+	return n
+}
+
+// CountName returns the number of entries of the given level
+// for the given logger name
+func (c *Counters) CountName(name string, level slog.LogLevel) uint64 {
+	if level < slog.UndefinedLevel || int(level) >= levels {
+		return 0
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if lc, ok := c.names[name]; ok {
+		return lc[level].Load()
+	}
+	return 0
+}
+
+// Each calls the given function for every logger name and level
+// with entries, sorted by name and then by level
+func (c *Counters) Each(fn func(name string, level slog.LogLevel, count uint64)) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, name := range core.SortedKeys(c.names) {
+		lc := c.names[name]
+		for i := range lc {
+			if n := lc[i].Load(); n > 0 {
+				fn(name, slog.LogLevel(i), n)
+			}
+		}
+	}
+}
+
+// Reset sets all counters back to zero
+func (c *Counters) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.names = make(map[string]*levelCounters)
+}
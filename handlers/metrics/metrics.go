@@ -0,0 +1,68 @@
+// Package metrics provides a slog.Logger wrapper that counts
+// entries per level and logger name
+package metrics
+
+import (
+	"fmt"
+
+	"darvaza.org/slog"
+	"darvaza.org/slog/internal"
+)
+
+// DefaultNameField is the field used to identify the logger
+// an entry belongs to when none is specified
+const DefaultNameField = slog.NameFieldName
+
+// Recorder receives a notification for every entry emitted
+type Recorder interface {
+	Record(level slog.LogLevel, name string)
+}
+
+// Options configures the metrics handler
+type Options struct {
+	// NameField is the field containing the name of the logger.
+	// Defaults to DefaultNameField
+	NameField string
+}
+
+// New creates a slog.Logger that notifies the given Recorder of
+// every entry emitted through it before passing it to the parent.
+// If no Recorder is given, a new Counters is used and can be
+// retrieved using the returned value.
+func New(parent slog.Logger, rec Recorder, opts *Options) (slog.Logger, Recorder) {
+	if parent == nil {
+		return nil, nil
+	}
+	if rec == nil {
+		rec = NewCounters()
+	}
+
+	nameField := DefaultNameField
+	if opts != nil && opts.NameField != "" {
+		nameField = opts.NameField
+	}
+
+	l := internal.NewWrapper(parent, &internal.WrapperHooks{
+		Print: func(e *internal.Entry) bool {
+			rec.Record(e.Level(), entryName(e.Loglet, nameField))
+			return true
+		},
+	})
+	return l, rec
+}
+
+func entryName(ll *internal.Loglet, field string) string {
+	v, ok := ll.Field(field)
+	if !ok {
+		return ""
+	}
+
+	switch name := slog.ResolveValue(v).(type) {
+	case string:
+		return name
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(name)
+	}
+}
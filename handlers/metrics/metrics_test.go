@@ -0,0 +1,114 @@
+package metrics
+
+import (
+	"sync"
+	"testing"
+
+	"darvaza.org/slog"
+	slogtest "darvaza.org/slog/internal/testing"
+)
+
+// counted is a Recorder call
+type counted struct {
+	level slog.LogLevel
+	name  string
+}
+
+type testRecorder []counted
+
+func (r *testRecorder) Record(level slog.LogLevel, name string) {
+	*r = append(*r, counted{level, name})
+}
+
+func TestRecorder(t *testing.T) {
+	var calls testRecorder
+
+	rec := slogtest.NewRecorder()
+	l, got := New(rec, &calls, &Options{NameField: "component"})
+	if got != &calls {
+		t.Fatal("Recorder not returned")
+	}
+
+	l.Info().Print("unnamed")
+	l.WithField("component", "db").Warn().Print("named")
+	l.WithField("component", slog.LazyValue(func() any { return 42 })).
+		Error().Print("lazy")
+	l.Debug().Print("debug")
+
+	want := testRecorder{
+		{slog.Info, ""},
+		{slog.Warn, "db"},
+		{slog.Error, "42"},
+		{slog.Debug, ""},
+	}
+	if !slogtest.SameValue(calls, want) {
+		t.Errorf("expected %v, got %v", want, calls)
+	}
+	if n := rec.Count(); n != 4 {
+		t.Errorf("expected 4 entries on the parent, got %d", n)
+	}
+}
+
+func TestCounters(t *testing.T) {
+	rec := slogtest.NewDiscardRecorder()
+	l, r := New(rec, nil, nil)
+	c, ok := r.(*Counters)
+	if !ok {
+		t.Fatalf("expected *Counters, got %T", r)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			api := slog.WithName(l, "api")
+			for j := 0; j < 100; j++ {
+				api.Info().Print("request")
+			}
+		}()
+	}
+	wg.Wait()
+
+	l.Warn().Print("root")
+	c.Record(slog.LogLevel(100), "api")
+
+	if n := c.CountName("api", slog.Info); n != 400 {
+		t.Errorf("expected 400, got %d", n)
+	}
+	if n := c.Count(slog.Warn); n != 1 {
+		t.Errorf("expected 1, got %d", n)
+	}
+	if n := c.CountName("api", slog.UndefinedLevel); n != 1 {
+		t.Errorf("expected the invalid level counted as undefined, got %d", n)
+	}
+	if n := c.CountName("other", slog.Info); n != 0 {
+		t.Errorf("unexpected %d entries", n)
+	}
+
+	var seen []counted
+	c.Each(func(name string, level slog.LogLevel, _ uint64) {
+		seen = append(seen, counted{level, name})
+	})
+	want := []counted{
+		{slog.Warn, ""},
+		{slog.UndefinedLevel, "api"},
+		{slog.Info, "api"},
+	}
+	if !slogtest.SameValue(seen, want) {
+		t.Errorf("expected %v, got %v", want, seen)
+	}
+
+	c.Reset()
+	if n := c.Count(slog.Info); n != 0 {
+		t.Errorf("unexpected %d entries after Reset", n)
+	}
+}
+
+func TestProperties(t *testing.T) {
+	slogtest.CheckProperties(t, func(parent slog.Logger) slog.Logger {
+		l, _ := New(parent, nil, nil)
+		return l
+	})
+}
@@ -0,0 +1,28 @@
+module darvaza.org/slog/handlers/metrics/prometheus
+
+go 1.22
+
+replace (
+	darvaza.org/slog => ../../../
+	darvaza.org/slog/handlers/metrics => ../
+)
+
+require (
+	darvaza.org/slog v0.6.0
+	darvaza.org/slog/handlers/metrics v0.0.0
+	github.com/prometheus/client_golang v1.20.5
+)
+
+require (
+	darvaza.org/core v0.16.0 // indirect
+	github.com/beorn7/perks v1.0.1 // indirect
+	github.com/cespare/xxhash/v2 v2.3.0 // indirect
+	github.com/munnerz/goautoneg v0.0.0-20191010083416-a7dc8b61c822 // indirect
+	github.com/prometheus/client_model v0.6.1 // indirect
+	github.com/prometheus/common v0.55.0 // indirect
+	github.com/prometheus/procfs v0.15.1 // indirect
+	golang.org/x/net v0.34.0 // indirect
+	golang.org/x/sys v0.29.0 // indirect
+	golang.org/x/text v0.21.0 // indirect
+	google.golang.org/protobuf v1.34.2 // indirect
+)
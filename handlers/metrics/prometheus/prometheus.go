@@ -0,0 +1,54 @@
+// Package prometheus exposes the counters of the slog metrics
+// handler as a Prometheus collector
+package prometheus
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"darvaza.org/slog"
+	"darvaza.org/slog/handlers/metrics"
+)
+
+var (
+	_ prometheus.Collector = (*Collector)(nil)
+)
+
+// DefaultMetricName is the name of the metric when none is specified
+const DefaultMetricName = "log_entries_total"
+
+// Collector is a prometheus.Collector exposing metrics.Counters
+type Collector struct {
+	counters *metrics.Counters
+	desc     *prometheus.Desc
+}
+
+// NewCollector creates a prometheus.Collector for the given counters.
+// If the name is empty DefaultMetricName is used.
+func NewCollector(counters *metrics.Counters, name string) *Collector {
+	if counters == nil {
+		return nil
+	}
+	if name == "" {
+		name = DefaultMetricName
+	}
+
+	return &Collector{
+		counters: counters,
+		desc: prometheus.NewDesc(name,
+			"Number of log entries emitted, per logger and level.",
+			[]string{"logger", "level"}, nil),
+	}
+}
+
+// Describe sends the description of the metric
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+// Collect sends the current value of the counters
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.counters.Each(func(name string, level slog.LogLevel, count uint64) {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.CounterValue,
+			float64(count), name, level.String())
+	})
+}
@@ -0,0 +1,55 @@
+package prometheus
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"darvaza.org/slog"
+	"darvaza.org/slog/handlers/metrics"
+)
+
+func TestCollector(t *testing.T) {
+	counters := metrics.NewCounters()
+	counters.Record(slog.Info, "api")
+	counters.Record(slog.Info, "api")
+	counters.Record(slog.Error, "")
+
+	reg := prometheus.NewPedanticRegistry()
+	if err := reg.Register(NewCollector(counters, "")); err != nil {
+		t.Fatal(err)
+	}
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(families) != 1 || families[0].GetName() != DefaultMetricName {
+		t.Fatalf("unexpected metrics: %v", families)
+	}
+
+	got := make(map[string]float64)
+	for _, m := range families[0].GetMetric() {
+		labels := make(map[string]string)
+		for _, lp := range m.GetLabel() {
+			labels[lp.GetName()] = lp.GetValue()
+		}
+		got[labels["logger"]+"/"+labels["level"]] = m.GetCounter().GetValue()
+	}
+
+	want := map[string]float64{"api/info": 2, "/error": 1}
+	if len(got) != len(want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s: expected %v, got %v", k, v, got[k])
+		}
+	}
+}
+
+func TestNewCollector(t *testing.T) {
+	if c := NewCollector(nil, ""); c != nil {
+		t.Error("expected nil collector")
+	}
+}
@@ -0,0 +1,207 @@
+// Package nats provides a slog.Logger that publishes entries
+// as JSON to NATS subjects, optionally using JetStream
+package nats
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+
+	"darvaza.org/core"
+	"darvaza.org/slog"
+	"darvaza.org/slog/handlers/cblog"
+	"darvaza.org/slog/internal"
+)
+
+const (
+	// DefaultSubject is the subject template used if none is given
+	DefaultSubject = "logs.{level}"
+	// DefaultRetryBufferSize is the maximum number of entries kept
+	// while the server is unreachable
+	DefaultRetryBufferSize = 1024
+	// DefaultRetryInterval is how long to wait before publishing
+	// again after a failure
+	DefaultRetryInterval = time.Second
+	// DefaultPublishTimeout is the maximum time to wait for
+	// JetStream to acknowledge an entry
+	DefaultPublishTimeout = 5 * time.Second
+)
+
+// Config describes the NATS connection and how entries are published
+type Config struct {
+	// Conn is the NATS connection used. It isn't closed by the Client
+	Conn *nats.Conn
+	// Clock timestamps the entries and paces the RetryInterval.
+	// Defaults to the system clock
+	Clock cblog.Clock
+	// OnError is called when publishing fails,
+	// instead of reporting it using slog.ReportError()
+	OnError func(error)
+
+	// Subject is the template of the subject entries are published
+	// to. {level} is replaced by the level of the entry, and any
+	// other {field} by the value of that field, or MissingToken.
+	// Defaults to DefaultSubject
+	Subject string
+
+	// JetStream publishes entries to a JetStream stream, waiting
+	// for their acknowledgement, instead of using Core NATS
+	JetStream bool
+
+	// RetryBufferSize is the maximum number of entries kept while
+	// the server is unreachable, the oldest being dropped.
+	// Defaults to DefaultRetryBufferSize
+	RetryBufferSize int
+	// BufferSize is the number of entries that can wait to be
+	// published before logging blocks. Defaults to
+	// cblog.DefaultOutputBufferSize
+	BufferSize int
+	// RetryInterval is how long to wait before publishing again
+	// after a failure. Defaults to DefaultRetryInterval
+	RetryInterval time.Duration
+	// PublishTimeout is the maximum time to wait for JetStream
+	// to acknowledge an entry. Defaults to DefaultPublishTimeout
+	PublishTimeout time.Duration
+}
+
+// SetDefaults sets the subject, buffers and timeouts not
+// specified, and the system clock
+func (cfg *Config) SetDefaults() {
+	if cfg.Clock == nil {
+		cfg.Clock = internal.SystemClock
+	}
+	if cfg.Subject == "" {
+		cfg.Subject = DefaultSubject
+	}
+	if cfg.RetryBufferSize <= 0 {
+		cfg.RetryBufferSize = DefaultRetryBufferSize
+	}
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = cblog.DefaultOutputBufferSize
+	}
+	if cfg.RetryInterval <= 0 {
+		cfg.RetryInterval = DefaultRetryInterval
+	}
+	if cfg.PublishTimeout <= 0 {
+		cfg.PublishTimeout = DefaultPublishTimeout
+	}
+}
+
+// Validate checks the connection is set and the Subject
+// template can be parsed
+func (cfg *Config) Validate() error {
+	if cfg.Conn == nil {
+		return core.Wrap(core.ErrInvalid, "nats: connection not specified")
+	}
+	_, err := parseSubject(cfg.Subject)
+	return err
+}
+
+// Client publishes the entries of its slog.Logger to NATS
+type Client struct {
+	logger  *cblog.Logger
+	publish func(context.Context, *nats.Msg) error
+	subject subject
+	dropped atomic.Uint64
+	backend internal.Backend
+	cfg     Config
+}
+
+// New creates a Client and starts publishing entries to the
+// NATS subjects described by the Config.
+func New(cfg *Config) (*Client, error) {
+	if cfg == nil {
+		return nil, core.Wrap(core.ErrInvalid, "nats: config not specified")
+	}
+
+	c := &Client{
+		cfg: *cfg,
+	}
+	c.cfg.SetDefaults()
+	if err := c.cfg.Validate(); err != nil {
+		return nil, err
+	}
+	c.subject, _ = parseSubject(c.cfg.Subject)
+
+	if err := c.setPublisher(); err != nil {
+		return nil, err
+	}
+
+	c.backend.Init("nats", c.cfg.OnError)
+
+	logger, ch := cblog.New(make(chan cblog.LogMsg, c.cfg.BufferSize),
+		cblog.WithClock(c.cfg.Clock),
+		cblog.WithHealthCheck(c.Health),
+		cblog.WithStats(c.Stats),
+		cblog.WithFlush(c.backend.Flush))
+	c.logger = logger
+
+	c.backend.Start(logger.Shutdown, func() { c.run(ch) })
+	return c, nil
+}
+
+func (c *Client) setPublisher() error {
+	if !c.cfg.JetStream {
+		c.publish = func(_ context.Context, m *nats.Msg) error {
+			return c.cfg.Conn.PublishMsg(m)
+		}
+		return nil
+	}
+
+	js, err := jetstream.New(c.cfg.Conn)
+	if err != nil {
+		return core.Wrap(err, "nats: jetstream")
+	}
+
+	c.publish = func(ctx context.Context, m *nats.Msg) error {
+		ctx, cancel := context.WithTimeout(ctx, c.cfg.PublishTimeout)
+		defer cancel()
+
+		_, err := js.PublishMsg(ctx, m)
+		return err
+	}
+	return nil
+}
+
+// Logger returns the slog.Logger whose entries are published
+func (c *Client) Logger() slog.Logger {
+	return c.logger
+}
+
+// DroppedCount returns the number of entries discarded while
+// the server was unreachable
+func (c *Client) DroppedCount() uint64 {
+	return c.dropped.Load()
+}
+
+// Health returns the last error publishing to NATS,
+// until publishing succeeds
+func (c *Client) Health() error {
+	return c.backend.Err()
+}
+
+// Stats returns the number of entries dropped by the Client, as
+// slog.DroppedStat, when the retry buffer overflows or pending
+// entries can't be published on close. Entries waiting to be
+// published are reported by the cblog.Logger returned by Logger().
+func (c *Client) Stats() slog.Stats {
+	return slog.Stats{
+		slog.DroppedStat: c.dropped.Load(),
+	}
+}
+
+// Close stops accepting new entries and waits, up to
+// cblog.DefaultShutdownTimeout, for the pending ones to be published.
+func (c *Client) Close() error {
+	return c.backend.Close(cblog.DefaultShutdownTimeout)
+}
+
+// Shutdown stops accepting new entries and waits for the pending
+// ones to be published. If the context is cancelled first,
+// publishing is aborted.
+func (c *Client) Shutdown(ctx context.Context) error {
+	return c.backend.Shutdown(ctx)
+}
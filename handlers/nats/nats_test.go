@@ -0,0 +1,275 @@
+package nats
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+
+	"darvaza.org/slog"
+	"darvaza.org/slog/handlers/cblog"
+)
+
+// published is a message received by a testServer
+type published struct {
+	subject string
+	data    []byte
+}
+
+// testServer speaks enough of the NATS protocol to accept
+// a client and record what it publishes
+type testServer struct {
+	ln  net.Listener
+	mu  sync.Mutex
+	msg []published
+}
+
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	s := &testServer{ln: ln}
+	go s.serve()
+	return s
+}
+
+func (s *testServer) URL() string {
+	return "nats://" + s.ln.Addr().String()
+}
+
+func (s *testServer) serve() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *testServer) handle(conn net.Conn) {
+	defer conn.Close()
+
+	_, _ = fmt.Fprintf(conn, "INFO {\"server_id\":\"test\",\"version\":\"2.10.0\","+
+		"\"proto\":1,\"max_payload\":1048576}\r\n")
+
+	r := bufio.NewReader(conn)
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		verb, args, _ := strings.Cut(strings.TrimSpace(line), " ")
+		switch strings.ToUpper(verb) {
+		case "PING":
+			_, _ = io.WriteString(conn, "PONG\r\n")
+		case "PUB":
+			if !s.readPub(r, strings.Fields(args)) {
+				return
+			}
+		case "HPUB":
+			// headers aren't used
+			return
+		}
+	}
+}
+
+func (s *testServer) readPub(r *bufio.Reader, args []string) bool {
+	if len(args) < 2 {
+		return false
+	}
+
+	n, err := strconv.Atoi(args[len(args)-1])
+	if err != nil {
+		return false
+	}
+
+	data := make([]byte, n+2)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.msg = append(s.msg, published{subject: args[0], data: data[:n]})
+	return true
+}
+
+func (s *testServer) Published() []published {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]published(nil), s.msg...)
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestPublish(t *testing.T) {
+	s := newTestServer(t)
+
+	conn, err := nats.Connect(s.URL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	c, err := New(&Config{
+		Conn:    conn,
+		Subject: "logs.{level}.{component}",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l := c.Logger()
+	l.Info().WithField("component", "db.pool").WithField("n", 1).Print("first")
+	l.Warn().Print("second")
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	waitFor(t, func() bool { return len(s.Published()) == 2 })
+
+	msgs := s.Published()
+	for i, want := range []string{"logs.info.db_pool", "logs.warn." + MissingToken} {
+		if msgs[i].subject != want {
+			t.Errorf("#%d: expected subject %q, got %q", i, want, msgs[i].subject)
+		}
+	}
+
+	var out map[string]any
+	if err := json.Unmarshal(msgs[0].data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out[MessageKey] != "first" || out[LevelKey] != "info" || out["n"] != float64(1) {
+		t.Errorf("unexpected entry: %v", out)
+	}
+}
+
+func TestParseSubject(t *testing.T) {
+	for _, s := range []string{"logs", "logs.{level}", "{app}.{level}.x"} {
+		if _, err := parseSubject(s); err != nil {
+			t.Errorf("%q: %v", s, err)
+		}
+	}
+
+	for _, s := range []string{"logs.{level", "logs.}", "logs.{}", "logs.}{level}"} {
+		if _, err := parseSubject(s); err == nil {
+			t.Errorf("%q: expected error", s)
+		}
+	}
+}
+
+func TestSubjectTokens(t *testing.T) {
+	sub, err := parseSubject("logs.{level}.{user}")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		fields map[string]any
+		want   string
+	}{
+		{nil, "logs.error._"},
+		{map[string]any{"user": ""}, "logs.error._"},
+		{map[string]any{"user": "a.b *>c"}, "logs.error.a_b___c"},
+		{map[string]any{"user": 42}, "logs.error.42"},
+	} {
+		msg := cblog.LogMsg{Level: slog.Error, Fields: tc.fields}
+		if got := sub.render(msg); got != tc.want {
+			t.Errorf("%v: expected %q, got %q", tc.fields, tc.want, got)
+		}
+	}
+}
+
+// newTestSender creates a sender whose entries are published
+// using the given function
+func newTestSender(size int, publish func(*nats.Msg) error) *sender {
+	c := &Client{
+		cfg: Config{RetryBufferSize: size},
+		publish: func(_ context.Context, m *nats.Msg) error {
+			return publish(m)
+		},
+	}
+	c.subject, _ = parseSubject(DefaultSubject)
+	c.backend.Init("nats", func(error) {})
+	return &sender{c: c}
+}
+
+func TestRetryBuffer(t *testing.T) {
+	var sent []string
+	unreachable := true
+	s := newTestSender(2, func(m *nats.Msg) error {
+		if unreachable {
+			return errors.New("unreachable")
+		}
+		sent = append(sent, string(m.Data))
+		return nil
+	})
+
+	for _, text := range []string{"first", "second", "third"} {
+		s.push(cblog.LogMsg{Level: slog.Info, Message: text})
+		if s.flush() {
+			t.Fatal("flushed while unreachable")
+		}
+	}
+
+	// the oldest is discarded
+	if n := s.c.DroppedCount(); n != 1 {
+		t.Errorf("expected 1 entry dropped, got %d", n)
+	}
+	if s.c.Health() == nil {
+		t.Error("expected unhealthy")
+	}
+
+	unreachable = false
+	if !s.flush() {
+		t.Fatal("not flushed")
+	}
+	if len(sent) != 2 || !strings.Contains(sent[0], "second") ||
+		!strings.Contains(sent[1], "third") {
+		t.Errorf("unexpected entries published: %q", sent)
+	}
+	if err := s.c.Health(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, cfg := range []*Config{
+		nil,
+		{},
+		{Conn: new(nats.Conn), Subject: "logs.{level"},
+	} {
+		if _, err := New(cfg); err == nil {
+			t.Errorf("%+v: expected error", cfg)
+		}
+	}
+}
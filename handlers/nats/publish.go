@@ -0,0 +1,206 @@
+package nats
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go"
+
+	"darvaza.org/core"
+	"darvaza.org/slog"
+	"darvaza.org/slog/handlers/cblog"
+	"darvaza.org/slog/internal"
+)
+
+// Keys used by the JSON representation of an entry. Fields using
+// these names are overridden.
+const (
+	TimeKey    = "time"
+	LevelKey   = "level"
+	MessageKey = "message"
+	StackKey   = "stack"
+)
+
+// MissingToken replaces placeholders of the subject template
+// whose field isn't present
+const MissingToken = "_"
+
+// subject is a parsed subject template. Even elements are
+// literals, odd ones field names or LevelKey.
+type subject []string
+
+func parseSubject(s string) (subject, error) {
+	var out subject
+
+	for {
+		i := strings.IndexByte(s, '{')
+		if i < 0 {
+			break
+		} else if strings.IndexByte(s[:i], '}') >= 0 {
+			return nil, core.Wrap(core.ErrInvalid, "nats: unbalanced placeholder in subject")
+		}
+
+		j := strings.IndexByte(s[i:], '}')
+		if j < 0 {
+			return nil, core.Wrapf(core.ErrInvalid, "nats: unterminated placeholder in %q", s)
+		}
+		j += i
+
+		key := s[i+1 : j]
+		if key == "" {
+			return nil, core.Wrap(core.ErrInvalid, "nats: empty placeholder in subject")
+		}
+
+		out = append(out, s[:i], key)
+		s = s[j+1:]
+	}
+
+	if strings.IndexByte(s, '}') >= 0 {
+		return nil, core.Wrap(core.ErrInvalid, "nats: unbalanced placeholder in subject")
+	}
+	return append(out, s), nil
+}
+
+// render composes the subject of an entry
+func (s subject) render(msg cblog.LogMsg) string {
+	if len(s) == 1 {
+		return s[0]
+	}
+
+	var buf strings.Builder
+	for i, part := range s {
+		if i%2 == 0 {
+			buf.WriteString(part)
+		} else {
+			buf.WriteString(subjectToken(msg, part))
+		}
+	}
+	return buf.String()
+}
+
+func subjectToken(msg cblog.LogMsg, key string) string {
+	var s string
+	if key == LevelKey {
+		s = msg.Level.String()
+	} else if v, ok := msg.Fields[key]; ok {
+		s = internal.TextValue(v)
+	}
+
+	if s == "" {
+		return MissingToken
+	}
+
+	// tokens can't contain separators or wildcards
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '.', '*', '>', ' ', '\t', '\r', '\n':
+			return '_'
+		default:
+			return r
+		}
+	}, s)
+}
+
+// sender holds the entries waiting to be published
+type sender struct {
+	c       *Client
+	pending []*nats.Msg
+}
+
+func (c *Client) run(ch <-chan cblog.LogMsg) {
+	s := &sender{c: c}
+	defer s.close()
+
+	var retry <-chan time.Time
+
+	for {
+		select {
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+
+			s.push(msg)
+			if retry == nil && !s.flush() {
+				retry = c.cfg.Clock.After(c.cfg.RetryInterval)
+			}
+		case <-retry:
+			retry = nil
+			if !s.flush() {
+				retry = c.cfg.Clock.After(c.cfg.RetryInterval)
+			}
+		case req := <-c.backend.Flushes():
+			if retry == nil && !s.flush() {
+				retry = c.cfg.Clock.After(c.cfg.RetryInterval)
+			}
+			close(req)
+		}
+	}
+}
+
+func (s *sender) push(msg cblog.LogMsg) {
+	b, err := Encode(msg)
+	if err != nil {
+		s.c.backend.ReportError(err)
+		return
+	}
+
+	if n := len(s.pending) - s.c.cfg.RetryBufferSize + 1; n > 0 {
+		// discard the oldest
+		s.pending = s.pending[n:]
+		s.c.dropped.Add(uint64(n))
+	}
+
+	s.pending = append(s.pending, &nats.Msg{
+		Subject: s.c.subject.render(msg),
+		Data:    b,
+	})
+}
+
+// flush publishes pending entries in order, and tells if
+// all of them were published
+func (s *sender) flush() bool {
+	for len(s.pending) > 0 {
+		if err := s.c.publish(s.c.backend.Context(), s.pending[0]); err != nil {
+			s.c.backend.ReportError(core.Wrap(err, "nats: publish"))
+			return false
+		}
+
+		s.pending[0] = nil
+		s.pending = s.pending[1:]
+	}
+
+	s.c.backend.ClearError()
+	return true
+}
+
+// close makes a last attempt to publish the pending entries
+func (s *sender) close() {
+	if s.flush() && !s.c.cfg.JetStream {
+		if err := s.c.cfg.Conn.FlushWithContext(s.c.backend.Context()); err != nil {
+			s.c.backend.ReportError(core.Wrap(err, "nats: flush"))
+		}
+	}
+
+	if n := len(s.pending); n > 0 {
+		s.c.dropped.Add(uint64(n))
+		s.c.backend.ReportError(fmt.Errorf("nats: %w: %v", slog.ErrDropped, n))
+	}
+}
+
+// Encode renders an entry as JSON, with its fields at
+// the top level
+func Encode(msg cblog.LogMsg) ([]byte, error) {
+	out := internal.JSONFields(msg.Fields, 4)
+
+	out[TimeKey] = msg.Time.Format(time.RFC3339Nano)
+	out[LevelKey] = msg.Level.String()
+	out[MessageKey] = msg.Message
+	if len(msg.Stack) > 0 {
+		out[StackKey] = msg.StackFrames()
+	}
+
+	return json.Marshal(out)
+}
@@ -0,0 +1,172 @@
+package netlog
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"darvaza.org/slog"
+	"darvaza.org/slog/handlers/cblog"
+)
+
+// sender owns the connection and the entries waiting to be written
+type sender struct {
+	c       *Client
+	conn    net.Conn
+	pending [][]byte
+	retryAt time.Time
+	delay   time.Duration
+}
+
+func (c *Client) run(ch <-chan cblog.LogMsg) {
+	s := &sender{
+		c:     c,
+		delay: c.cfg.ReconnectDelay,
+	}
+	defer s.close()
+
+	tick := c.cfg.Clock.After(c.cfg.FlushInterval)
+
+	for {
+		select {
+		case msg, ok := <-ch:
+			if !ok {
+				// last attempt
+				s.retryAt = time.Time{}
+				return
+			}
+
+			s.push(msg)
+			if len(s.pending) >= c.cfg.BatchSize {
+				s.flush()
+			}
+		case <-tick:
+			tick = c.cfg.Clock.After(c.cfg.FlushInterval)
+			s.flush()
+		case req := <-c.backend.Flushes():
+			s.flush()
+			close(req)
+		}
+	}
+}
+
+func (s *sender) push(msg cblog.LogMsg) {
+	b, err := Encode(msg)
+	if err != nil {
+		s.c.backend.ReportError(err)
+		return
+	}
+
+	if n := len(s.pending) - s.c.cfg.RetryBufferSize + 1; n > 0 {
+		// discard the oldest
+		s.pending = s.pending[n:]
+		s.c.dropped.Add(uint64(n))
+	}
+	s.pending = append(s.pending, b)
+}
+
+func (s *sender) flush() {
+	if len(s.pending) == 0 || !s.connect() {
+		return
+	}
+
+	n, err := s.write()
+	s.pending = s.pending[n:]
+	if err != nil {
+		s.c.backend.ReportError(err)
+		s.disconnect()
+	} else {
+		s.c.backend.ClearError()
+	}
+}
+
+// write sends as many pending entries as possible, one datagram
+// per entry on UDP, and returns how many were written
+func (s *sender) write() (int, error) {
+	deadline := time.Now().Add(s.c.cfg.WriteTimeout)
+	if err := s.conn.SetWriteDeadline(deadline); err != nil {
+		return 0, err
+	}
+
+	batch := s.pending[:min(len(s.pending), s.c.cfg.BatchSize)]
+	if strings.HasPrefix(s.c.cfg.Network, "udp") {
+		for i, b := range batch {
+			if _, err := s.conn.Write(b); err != nil {
+				return i, err
+			}
+		}
+		return len(batch), nil
+	}
+
+	buf := make(net.Buffers, len(batch))
+	copy(buf, batch)
+	written, err := buf.WriteTo(s.conn)
+	if err != nil {
+		// partially written entries are retried
+		return countWritten(batch, written), err
+	}
+	return len(batch), nil
+}
+
+// countWritten returns how many entries were written completely
+func countWritten(batch [][]byte, written int64) int {
+	for i, b := range batch {
+		written -= int64(len(b))
+		if written < 0 {
+			return i
+		}
+	}
+	return len(batch)
+}
+
+// connect makes sure there is a connection, unless it's
+// too soon to try again
+func (s *sender) connect() bool {
+	switch {
+	case s.conn != nil:
+		return true
+	case s.c.cfg.Clock.Now().Before(s.retryAt):
+		return false
+	}
+
+	cfg := &s.c.cfg
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.DialTimeout)
+	defer cancel()
+
+	conn, err := cfg.Dial(ctx, cfg.Network, cfg.Address)
+	if err != nil {
+		s.c.backend.ReportError(err)
+
+		// exponential backoff
+		s.retryAt = cfg.Clock.Now().Add(s.delay)
+		s.delay = min(2*s.delay, cfg.MaxReconnectDelay)
+		return false
+	}
+
+	s.conn = conn
+	s.delay = cfg.ReconnectDelay
+	return true
+}
+
+func (s *sender) disconnect() {
+	if s.conn != nil {
+		_ = s.conn.Close()
+		s.conn = nil
+	}
+	s.retryAt = s.c.cfg.Clock.Now().Add(s.delay)
+}
+
+func (s *sender) close() {
+	for len(s.pending) > 0 && s.connect() {
+		s.flush()
+	}
+	s.disconnect()
+
+	if n := len(s.pending); n > 0 {
+		s.c.dropped.Add(uint64(n))
+		s.c.backend.ReportError(fmt.Errorf("netlog: %w: %v", slog.ErrDropped, n))
+		s.pending = nil
+	}
+}
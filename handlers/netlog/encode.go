@@ -0,0 +1,37 @@
+package netlog
+
+import (
+	"encoding/json"
+	"time"
+
+	"darvaza.org/slog/handlers/cblog"
+	"darvaza.org/slog/internal"
+)
+
+// Keys used by the JSON representation of an entry. Fields using
+// these names are overridden.
+const (
+	TimeKey    = "time"
+	LevelKey   = "level"
+	MessageKey = "message"
+	StackKey   = "stack"
+)
+
+// Encode renders an entry as a single line of JSON, with its
+// fields at the top level
+func Encode(msg cblog.LogMsg) ([]byte, error) {
+	out := internal.JSONFields(msg.Fields, 4)
+
+	out[TimeKey] = msg.Time.Format(time.RFC3339Nano)
+	out[LevelKey] = msg.Level.String()
+	out[MessageKey] = msg.Message
+	if len(msg.Stack) > 0 {
+		out[StackKey] = msg.StackFrames()
+	}
+
+	b, err := json.Marshal(out)
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
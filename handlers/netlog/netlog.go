@@ -0,0 +1,207 @@
+// Package netlog provides a slog.Logger that forwards entries
+// as JSON over TCP or UDP
+package netlog
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"time"
+
+	"darvaza.org/core"
+	"darvaza.org/slog"
+	"darvaza.org/slog/handlers/cblog"
+	"darvaza.org/slog/internal"
+)
+
+const (
+	// DefaultBatchSize is the number of entries written at once
+	DefaultBatchSize = 64
+	// DefaultFlushInterval is how long entries can wait before
+	// being written if the batch isn't full
+	DefaultFlushInterval = time.Second
+	// DefaultRetryBufferSize is the maximum number of entries kept
+	// while the endpoint is unreachable
+	DefaultRetryBufferSize = 1024
+	// DefaultDialTimeout is the maximum time to wait for a connection
+	DefaultDialTimeout = 5 * time.Second
+	// DefaultWriteTimeout is the maximum time to wait for a write
+	DefaultWriteTimeout = 5 * time.Second
+	// DefaultReconnectDelay is the initial wait before reconnecting
+	DefaultReconnectDelay = time.Second
+	// DefaultMaxReconnectDelay is the maximum wait before reconnecting
+	DefaultMaxReconnectDelay = time.Minute
+)
+
+// Config describes the endpoint and behaviour of a Client
+type Config struct {
+	// Dial optionally replaces net.Dialer for establishing connections
+	Dial func(ctx context.Context, network, address string) (net.Conn, error)
+	// Clock timestamps the entries and paces the FlushInterval
+	// and reconnection delays. Defaults to the system clock
+	Clock cblog.Clock
+	// OnError is called when connecting or writing fails,
+	// instead of reporting it using slog.ReportError()
+	OnError func(error)
+
+	// Network is "tcp", "tcp4", "tcp6", "udp", "udp4" or "udp6"
+	Network string
+	// Address is the host:port of the endpoint
+	Address string
+
+	// BatchSize is the number of entries written at once.
+	// Defaults to DefaultBatchSize
+	BatchSize int
+	// RetryBufferSize is the maximum number of entries kept while
+	// the endpoint is unreachable, the oldest being dropped.
+	// Defaults to DefaultRetryBufferSize, and never below BatchSize
+	RetryBufferSize int
+	// BufferSize is the number of entries that can wait to be
+	// batched before logging blocks. Defaults to
+	// cblog.DefaultOutputBufferSize
+	BufferSize int
+	// FlushInterval is how long entries can wait before being
+	// written if the batch isn't full. Defaults to
+	// DefaultFlushInterval
+	FlushInterval time.Duration
+	// DialTimeout is the maximum time to wait for a connection.
+	// Defaults to DefaultDialTimeout
+	DialTimeout time.Duration
+	// WriteTimeout is the maximum time to wait for a batch to be
+	// written. Defaults to DefaultWriteTimeout
+	WriteTimeout time.Duration
+	// ReconnectDelay is the initial wait before reconnecting,
+	// doubled on every failure up to MaxReconnectDelay.
+	// Defaults to DefaultReconnectDelay and
+	// DefaultMaxReconnectDelay
+	ReconnectDelay    time.Duration
+	MaxReconnectDelay time.Duration
+}
+
+// SetDefaults sets the network, batching, buffers and timeouts
+// not specified, and a net.Dialer if Dial isn't set
+func (cfg *Config) SetDefaults() {
+	if cfg.Clock == nil {
+		cfg.Clock = internal.SystemClock
+	}
+	if cfg.Network == "" {
+		cfg.Network = "tcp"
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = DefaultBatchSize
+	}
+	if cfg.RetryBufferSize < cfg.BatchSize {
+		cfg.RetryBufferSize = max(DefaultRetryBufferSize, cfg.BatchSize)
+	}
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = cblog.DefaultOutputBufferSize
+	}
+
+	setDefaultDuration(&cfg.FlushInterval, DefaultFlushInterval)
+	setDefaultDuration(&cfg.DialTimeout, DefaultDialTimeout)
+	setDefaultDuration(&cfg.WriteTimeout, DefaultWriteTimeout)
+	setDefaultDuration(&cfg.ReconnectDelay, DefaultReconnectDelay)
+	setDefaultDuration(&cfg.MaxReconnectDelay, DefaultMaxReconnectDelay)
+
+	if cfg.Dial == nil {
+		d := &net.Dialer{Timeout: cfg.DialTimeout}
+		cfg.Dial = d.DialContext
+	}
+}
+
+func setDefaultDuration(p *time.Duration, def time.Duration) {
+	if *p <= 0 {
+		*p = def
+	}
+}
+
+// Validate checks the Network is supported and the Address set
+func (cfg *Config) Validate() error {
+	switch cfg.Network {
+	case "tcp", "tcp4", "tcp6", "udp", "udp4", "udp6":
+	default:
+		return core.Wrapf(core.ErrInvalid, "netlog: unsupported network %q", cfg.Network)
+	}
+
+	if cfg.Address == "" {
+		return core.Wrap(core.ErrInvalid, "netlog: address not specified")
+	}
+	return nil
+}
+
+// Client forwards the entries of its slog.Logger to a network endpoint
+type Client struct {
+	logger  *cblog.Logger
+	dropped atomic.Uint64
+	backend internal.Backend
+	cfg     Config
+}
+
+// New creates a Client and starts forwarding entries to the
+// endpoint described by the Config. Connections are established
+// lazily and reestablished when they fail.
+func New(cfg *Config) (*Client, error) {
+	if cfg == nil {
+		return nil, core.Wrap(core.ErrInvalid, "netlog: config not specified")
+	}
+
+	c := &Client{
+		cfg: *cfg,
+	}
+	c.cfg.SetDefaults()
+	if err := c.cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	c.backend.Init("netlog", c.cfg.OnError)
+
+	logger, ch := cblog.New(make(chan cblog.LogMsg, c.cfg.BufferSize),
+		cblog.WithClock(c.cfg.Clock),
+		cblog.WithHealthCheck(c.Health),
+		cblog.WithStats(c.Stats),
+		cblog.WithFlush(c.backend.Flush))
+	c.logger = logger
+
+	c.backend.Start(logger.Shutdown, func() { c.run(ch) })
+	return c, nil
+}
+
+// Logger returns the slog.Logger whose entries are forwarded
+func (c *Client) Logger() slog.Logger {
+	return c.logger
+}
+
+// DroppedCount returns the number of entries discarded because
+// the retry buffer was full, or the endpoint was unreachable
+// when the Client was closed
+func (c *Client) DroppedCount() uint64 {
+	return c.dropped.Load()
+}
+
+// Health returns the last error writing to the endpoint,
+// until a write succeeds
+func (c *Client) Health() error {
+	return c.backend.Err()
+}
+
+// Stats returns the number of entries dropped by the Client, as
+// slog.DroppedStat, when the retry buffer overflows or pending
+// entries can't be written on close. Entries waiting to be batched
+// are reported by the cblog.Logger returned by Logger().
+func (c *Client) Stats() slog.Stats {
+	return slog.Stats{
+		slog.DroppedStat: c.dropped.Load(),
+	}
+}
+
+// Close stops accepting new entries and waits, up to
+// cblog.DefaultShutdownTimeout, for the pending ones to be written.
+func (c *Client) Close() error {
+	return c.backend.Close(cblog.DefaultShutdownTimeout)
+}
+
+// Shutdown stops accepting new entries and waits for the pending
+// ones to be written, or the context to be cancelled.
+func (c *Client) Shutdown(ctx context.Context) error {
+	return c.backend.Shutdown(ctx)
+}
@@ -0,0 +1,75 @@
+package netlog
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"darvaza.org/slog"
+)
+
+// newPipeClient creates a Client writing to a net.Pipe, returning
+// the lines written to it through a channel
+func newPipeClient(t *testing.T) (*Client, <-chan string) {
+	lines := make(chan string, 16)
+
+	c, err := New(&Config{
+		Address:       "pipe",
+		FlushInterval: time.Hour,
+		Dial: func(context.Context, string, string) (net.Conn, error) {
+			client, server := net.Pipe()
+			go func() {
+				defer close(lines)
+
+				scanner := bufio.NewScanner(server)
+				for scanner.Scan() {
+					lines <- scanner.Text()
+				}
+			}()
+			return client, nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+	return c, lines
+}
+
+func expectLines(t *testing.T, lines <-chan string, n int) {
+	t.Helper()
+
+	timeout := time.After(time.Second)
+	for i := 0; i < n; i++ {
+		select {
+		case <-lines:
+		case <-timeout:
+			t.Fatalf("expected %d lines, got %d", n, i)
+		}
+	}
+}
+
+func TestFatalIsDelivered(t *testing.T) {
+	c, lines := newPipeClient(t)
+
+	var exited bool
+	slog.SetExitFunc(func(int) {
+		exited = true
+		// delivered before exiting, not by Close()
+		expectLines(t, lines, 2)
+	})
+	defer slog.SetExitFunc(nil)
+
+	l := c.Logger()
+	l.Info().Print("before")
+	l.Fatal().Print("fatal")
+
+	if !exited {
+		t.Fatal("Fatal didn't exit")
+	}
+	if err := c.Health(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,119 @@
+// Package noop provides a shareable slog.Logger that
+// doesn't log anything
+package noop
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"darvaza.org/slog"
+)
+
+var (
+	_ slog.Logger      = (*Logger)(nil)
+	_ slog.NamedLogger = (*Logger)(nil)
+)
+
+// loggers are the singletons for each level, so deriving
+// never allocates
+var loggers = [...]Logger{
+	slog.UndefinedLevel: {slog.UndefinedLevel},
+	slog.Panic:          {slog.Panic},
+	slog.Fatal:          {slog.Fatal},
+	slog.Error:          {slog.Error},
+	slog.Warn:           {slog.Warn},
+	slog.Info:           {slog.Info},
+	slog.Debug:          {slog.Debug},
+}
+
+// Logger is a slog.Logger that discards everything except
+// Fatal and Panic entries, which are written using the standard
+// logger before terminating the process or panicking respectively.
+// Fields and call stacks are always discarded.
+type Logger struct {
+	level slog.LogLevel
+}
+
+// Enabled tells if the logger is set to Fatal or Panic level
+func (l *Logger) Enabled() bool {
+	return l != nil && l.level >= slog.Panic && l.level <= slog.Fatal
+}
+
+// WithEnabled returns itself and if it's enabled
+func (l *Logger) WithEnabled() (slog.Logger, bool) {
+	return l, l.Enabled()
+}
+
+// Print discards the entry unless the level is Fatal or Panic
+func (l *Logger) Print(args ...any) {
+	if l.Enabled() {
+		l.terminate(fmt.Sprint(args...))
+	}
+}
+
+// Println discards the entry unless the level is Fatal or Panic
+func (l *Logger) Println(args ...any) {
+	if l.Enabled() {
+		l.terminate(fmt.Sprintln(args...))
+	}
+}
+
+// Printf discards the entry unless the level is Fatal or Panic
+func (l *Logger) Printf(format string, args ...any) {
+	if l.Enabled() {
+		l.terminate(fmt.Sprintf(format, args...))
+	}
+}
+
+func (l *Logger) terminate(msg string) {
+	msg = strings.TrimSpace(msg)
+	_ = log.Output(3, msg)
+
+	if l.level != slog.Fatal {
+		panic(msg)
+	}
+	slog.Exit(1)
+}
+
+// Debug returns the shared Debug logger
+func (*Logger) Debug() slog.Logger { return &loggers[slog.Debug] }
+
+// Info returns the shared Info logger
+func (*Logger) Info() slog.Logger { return &loggers[slog.Info] }
+
+// Warn returns the shared Warn logger
+func (*Logger) Warn() slog.Logger { return &loggers[slog.Warn] }
+
+// Error returns the shared Error logger
+func (*Logger) Error() slog.Logger { return &loggers[slog.Error] }
+
+// Fatal returns the shared Fatal logger
+func (*Logger) Fatal() slog.Logger { return &loggers[slog.Fatal] }
+
+// Panic returns the shared Panic logger
+func (*Logger) Panic() slog.Logger { return &loggers[slog.Panic] }
+
+// WithLevel returns the shared logger of the given level
+func (l *Logger) WithLevel(level slog.LogLevel) slog.Logger {
+	if level <= slog.UndefinedLevel || int(level) >= len(loggers) {
+		// fix your code
+		l.Panic().Printf("slog: invalid log level %v", level)
+	}
+	return &loggers[level]
+}
+
+// WithStack returns itself
+func (l *Logger) WithStack(int) slog.Logger { return l }
+
+// WithName returns itself
+func (l *Logger) WithName(string) slog.Logger { return l }
+
+// WithField returns itself
+func (l *Logger) WithField(string, any) slog.Logger { return l }
+
+// WithFields returns itself
+func (l *Logger) WithFields(map[string]any) slog.Logger { return l }
+
+// New returns the shared no-op logger
+func New() slog.Logger { return &loggers[slog.UndefinedLevel] }
@@ -0,0 +1,79 @@
+package noop
+
+import (
+	"bytes"
+	"log"
+	"testing"
+
+	"darvaza.org/slog"
+)
+
+// captureLog redirects the standard logger for the duration of the test
+func captureLog(t *testing.T) *bytes.Buffer {
+	var buf bytes.Buffer
+
+	flags, out := log.Flags(), log.Writer()
+	log.SetFlags(0)
+	log.SetOutput(&buf)
+	t.Cleanup(func() {
+		log.SetFlags(flags)
+		log.SetOutput(out)
+	})
+	return &buf
+}
+
+func TestShared(t *testing.T) {
+	buf := captureLog(t)
+	l := New()
+
+	if l != New() {
+		t.Error("expected the shared logger")
+	}
+	if l.Info() != l.WithField("k", "v").Info() {
+		t.Error("expected the shared Info logger")
+	}
+	if n := testing.AllocsPerRun(10, func() {
+		_ = l.Debug().WithField("k", "v").WithStack(0).Warn()
+	}); n != 0 {
+		t.Errorf("unexpected %v allocations", n)
+	}
+
+	for _, ll := range []slog.Logger{l, l.Debug(), l.Info(), l.Warn(), l.Error()} {
+		if ll.Enabled() {
+			t.Error("unexpected enabled logger")
+		}
+		ll.Print("discarded")
+	}
+	if buf.Len() != 0 {
+		t.Errorf("unexpected output %q", buf)
+	}
+}
+
+func TestFatal(t *testing.T) {
+	buf := captureLog(t)
+
+	var code int
+	slog.SetExitFunc(func(c int) { code = c })
+	t.Cleanup(func() { slog.SetExitFunc(nil) })
+
+	New().Fatal().Printf("fatal %d", 1)
+
+	if code != 1 || buf.String() != "fatal 1\n" {
+		t.Errorf("unexpected exit code %d and output %q", code, buf)
+	}
+}
+
+func TestPanic(t *testing.T) {
+	buf := captureLog(t)
+
+	defer func() {
+		if r := recover(); r != "slog: invalid log level LogLevel(42)" {
+			t.Errorf("unexpected panic %v", r)
+		}
+		if buf.Len() == 0 {
+			t.Error("panic not logged")
+		}
+	}()
+
+	New().WithLevel(slog.LogLevel(42))
+}
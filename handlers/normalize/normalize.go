@@ -0,0 +1,97 @@
+// Package normalize provides a slog.Logger wrapper that converts
+// field values into consistent representations before passing
+// them to the backend
+package normalize
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"darvaza.org/slog"
+	"darvaza.org/slog/internal"
+)
+
+// Func converts a field value
+type Func func(any) any
+
+// Value applies the standard treatment to a field value.
+//
+//   - slog.Lazy values are computed
+//   - values of types with an encoder registered using
+//     slog.RegisterEncoder are converted by it
+//   - errors are replaced by their message
+//   - time.Time values are formatted as RFC3339 with nanoseconds
+//   - time.Duration values are formatted using their String() method
+//   - other fmt.Stringer values are replaced by their String()
+//   - functions, channels and unsafe pointers, which backends
+//     can't represent, are replaced by their type name
+//
+// Everything else is returned unchanged.
+func Value(v any) any {
+	v = slog.ResolveValue(v)
+
+	switch x := v.(type) {
+	case nil:
+		return nil
+	case error:
+		return x.Error()
+	case time.Time:
+		return x.Format(time.RFC3339Nano)
+	case time.Duration:
+		return x.String()
+	case fmt.Stringer:
+		return x.String()
+	}
+
+	switch reflect.TypeOf(v).Kind() {
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return fmt.Sprintf("%T", v)
+	default:
+		return v
+	}
+}
+
+// New creates a slog.Logger that passes field values through the
+// given Func before handing them to the parent. If none is given
+// Value is used.
+func New(parent slog.Logger, fn Func) slog.Logger {
+	if parent == nil {
+		return nil
+	}
+	if fn == nil {
+		fn = Value
+	}
+
+	return internal.NewWrapper(parent, &internal.WrapperHooks{
+		Field: func(key string, value any) (string, any, bool) {
+			return key, apply(fn, value), true
+		},
+	})
+}
+
+// Keys creates a slog.Logger that renames the keys of fields using
+// the given slog.KeyMapper before handing them to the parent, e.g.
+// slog.SnakeCase, for backends that don't honour one themselves
+func Keys(parent slog.Logger, m slog.KeyMapper) slog.Logger {
+	if parent == nil || m == nil {
+		return parent
+	}
+
+	return internal.NewWrapper(parent, &internal.WrapperHooks{
+		Field: func(key string, value any) (string, any, bool) {
+			return m(key), value, true
+		},
+	})
+}
+
+// apply calls fn on the value, deferring the call
+// until emission for slog.Lazy values
+func apply(fn Func, value any) any {
+	if lazy, ok := value.(slog.Lazy); ok {
+		return slog.LazyValue(func() any {
+			return fn(lazy.Value())
+		})
+	}
+	return fn(value)
+}
@@ -0,0 +1,104 @@
+package normalize
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"darvaza.org/slog"
+	slogtest "darvaza.org/slog/internal/testing"
+)
+
+type point struct{ X, Y int }
+
+func init() {
+	slog.RegisterEncoder(func(p point) any {
+		return []int{p.X, p.Y}
+	})
+}
+
+func TestValue(t *testing.T) {
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)
+
+	for _, tc := range []struct {
+		name  string
+		value any
+		want  any
+	}{
+		{"nil", nil, nil},
+		{"string", "text", "text"},
+		{"int", 42, 42},
+		{"error", errors.New("failed"), "failed"},
+		{"time", ts, "2024-01-02T03:04:05.000000006Z"},
+		{"duration", 1500 * time.Millisecond, "1.5s"},
+		{"stringer", slog.Warn, "warn"},
+		{"func", func() {}, "func()"},
+		{"chan", make(chan int), "chan int"},
+		{"lazy", slog.LazyValue(func() any { return errors.New("lazy") }), "lazy"},
+		{"encoder", point{1, 2}, []int{1, 2}},
+	} {
+		if got := Value(tc.value); !slogtest.SameValue(got, tc.want) {
+			t.Errorf("%s: expected %#v, got %#v", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	rec := slogtest.NewRecorder()
+	l := New(rec, nil)
+
+	var computed int
+	ll := l.WithField("err", errors.New("denied")).
+		WithField("lazy", slog.LazyValue(func() any {
+			computed++
+			return time.Second
+		}))
+
+	// lazy values are normalised when emitted
+	if computed != 0 {
+		t.Fatal("lazy value computed early")
+	}
+	ll.Info().Print("normalised")
+
+	slogtest.AssertMessagesInOrder(t, rec.Messages(),
+		slogtest.Expect().Message("normalised").
+			Field("err", "denied").
+			Field("lazy", "1s"))
+	if computed != 1 {
+		t.Errorf("expected the lazy value computed once, got %d", computed)
+	}
+}
+
+func TestCustom(t *testing.T) {
+	rec := slogtest.NewRecorder()
+	l := New(rec, func(v any) any {
+		if _, ok := v.(int); ok {
+			return "number"
+		}
+		return v
+	})
+
+	l.WithFields(map[string]any{"n": 1, "s": "text"}).Info().Print("custom")
+
+	slogtest.AssertMessagesInOrder(t, rec.Messages(),
+		slogtest.Expect().Field("n", "number").Field("s", "text"))
+}
+
+func TestKeys(t *testing.T) {
+	rec := slogtest.NewRecorder()
+	if Keys(rec, nil) != slog.Logger(rec) {
+		t.Error("expected the parent without KeyMapper")
+	}
+
+	l := Keys(rec, slog.SnakeCase)
+	l.WithField("requestID", 1).Info().Print("renamed")
+
+	slogtest.AssertMessagesInOrder(t, rec.Messages(),
+		slogtest.Expect().Field("request_id", 1).NoField("requestID"))
+}
+
+func TestProperties(t *testing.T) {
+	slogtest.CheckProperties(t, func(parent slog.Logger) slog.Logger {
+		return New(parent, nil)
+	})
+}
@@ -0,0 +1,113 @@
+// Package sampling provides a slog.Logger that limits how many
+// repeated entries reach its parent
+package sampling
+
+import (
+	"sync"
+	"time"
+
+	"darvaza.org/slog"
+	"darvaza.org/slog/internal"
+)
+
+// DefaultTick is the period after which counters are reset
+const DefaultTick = time.Second
+
+// Clock is the source of time used to reset the counters
+type Clock = internal.Clock
+
+// Config describes the sampling policy. Within every Tick, the
+// First entries with the same level and message are passed to the
+// parent, and then only one of every Thereafter. If Thereafter is
+// zero, entries over First are dropped until the next Tick.
+type Config struct {
+	// Clock defaults to the system clock
+	Clock Clock
+	// OnDrop, if set, is called for every entry dropped
+	OnDrop func(level slog.LogLevel, msg string)
+
+	First      int
+	Thereafter int
+	Tick       time.Duration
+}
+
+// sampler counts entries per level and message
+type sampler struct {
+	mu     sync.Mutex
+	counts map[sampleKey]int
+	reset  time.Time
+	cfg    Config
+}
+
+type sampleKey struct {
+	msg   string
+	level slog.LogLevel
+}
+
+// New creates a slog.Logger that samples entries before passing
+// them to the parent. Fatal and Panic entries are never dropped.
+func New(parent slog.Logger, cfg *Config) slog.Logger {
+	if parent == nil || cfg == nil {
+		return parent
+	}
+
+	s := newSampler(cfg)
+	return internal.NewWrapper(parent, &internal.WrapperHooks{
+		Print: s.print,
+	})
+}
+
+func newSampler(cfg *Config) *sampler {
+	s := &sampler{
+		cfg:    *cfg,
+		counts: make(map[sampleKey]int),
+	}
+	s.cfg.Clock = internal.ClockOrSystem(s.cfg.Clock)
+	if s.cfg.Tick <= 0 {
+		s.cfg.Tick = DefaultTick
+	}
+	s.reset = s.cfg.Clock.Now().Add(s.cfg.Tick)
+	return s
+}
+
+func (s *sampler) print(e *internal.Entry) bool {
+	level := e.Level()
+	switch level {
+	case slog.Fatal, slog.Panic:
+		return true
+	}
+
+	if s.sample(level, e.Message) {
+		return true
+	}
+
+	if fn := s.cfg.OnDrop; fn != nil {
+		fn(level, e.Message)
+	}
+	return false
+}
+
+// sample tells if an entry should be passed to the parent
+func (s *sampler) sample(level slog.LogLevel, msg string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now := s.cfg.Clock.Now(); !now.Before(s.reset) {
+		// new period
+		clear(s.counts)
+		s.reset = now.Add(s.cfg.Tick)
+	}
+
+	key := sampleKey{msg: msg, level: level}
+	n := s.counts[key] + 1
+	s.counts[key] = n
+
+	switch {
+	case n <= s.cfg.First:
+		return true
+	case s.cfg.Thereafter <= 0:
+		return false
+	default:
+		return (n-s.cfg.First)%s.cfg.Thereafter == 0
+	}
+}
@@ -0,0 +1,98 @@
+package sampling
+
+import (
+	"testing"
+	"time"
+
+	"darvaza.org/slog"
+	"darvaza.org/slog/internal"
+	slogtest "darvaza.org/slog/internal/testing"
+)
+
+func newTestSampler(first, thereafter int) (*sampler, *slogtest.ManualClock) {
+	clock := slogtest.NewManualClock(time.Unix(0, 0))
+	return newSampler(&Config{
+		Clock:      clock,
+		First:      first,
+		Thereafter: thereafter,
+		Tick:       time.Second,
+	}), clock
+}
+
+// sampled returns which of n repeated entries are passed on
+func sampled(s *sampler, n int) []bool {
+	out := make([]bool, n)
+	for i := range out {
+		out[i] = s.sample(slog.Info, "repeated")
+	}
+	return out
+}
+
+func assertSampled(t *testing.T, got []bool, want ...bool) {
+	t.Helper()
+
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("entry #%d: expected %v, got %v", i+1, want[i], got[i])
+		}
+	}
+}
+
+func TestFirstThereafter(t *testing.T) {
+	s, _ := newTestSampler(2, 3)
+	assertSampled(t, sampled(s, 8),
+		true, true, false, false, true, false, false, true)
+}
+
+func TestThereafterZero(t *testing.T) {
+	s, _ := newTestSampler(1, 0)
+	assertSampled(t, sampled(s, 4), true, false, false, false)
+}
+
+func TestTickResets(t *testing.T) {
+	s, clock := newTestSampler(1, 0)
+	assertSampled(t, sampled(s, 2), true, false)
+
+	clock.Advance(time.Second)
+	assertSampled(t, sampled(s, 2), true, false)
+}
+
+func TestKeys(t *testing.T) {
+	s, _ := newTestSampler(1, 0)
+
+	for _, tc := range []struct {
+		msg   string
+		level slog.LogLevel
+		want  bool
+	}{
+		{"a", slog.Info, true},
+		{"a", slog.Info, false},
+		{"a", slog.Warn, true},
+		{"b", slog.Info, true},
+	} {
+		if got := s.sample(tc.level, tc.msg); got != tc.want {
+			t.Errorf("%s %q: expected %v, got %v", tc.level, tc.msg, tc.want, got)
+		}
+	}
+}
+
+func TestTerminalNeverDropped(t *testing.T) {
+	var dropped []string
+	s, _ := newTestSampler(0, 0)
+	s.cfg.OnDrop = func(_ slog.LogLevel, msg string) {
+		dropped = append(dropped, msg)
+	}
+
+	var base internal.Loglet
+	for _, level := range []slog.LogLevel{slog.Fatal, slog.Panic, slog.Error} {
+		ll := base.WithLevel(level)
+		e := &internal.Entry{Loglet: &ll, Message: level.String()}
+		if got, want := s.print(e), level != slog.Error; got != want {
+			t.Errorf("%s: expected %v, got %v", level, want, got)
+		}
+	}
+
+	if len(dropped) != 1 || dropped[0] != slog.Error.String() {
+		t.Errorf("unexpected OnDrop calls: %q", dropped)
+	}
+}
@@ -0,0 +1,43 @@
+// Package scope provides a slog.Logger wrapper adding the fields
+// pushed by the current goroutine using slog.PushScopeFields(), or
+// carried by the context using slog.WithScopeFields()
+package scope
+
+import (
+	"darvaza.org/slog"
+	"darvaza.org/slog/internal"
+)
+
+// New creates a slog.Logger that adds the scope fields, as returned
+// by slog.ScopeFields(), before passing entries to the parent. Those
+// pushed by the goroutine printing the entry come first, then those
+// carried by the context recorded using WithContext(), if any.
+// Fields attached to the logger take precedence over them.
+func New(parent slog.Logger) slog.Logger {
+	if parent == nil {
+		return nil
+	}
+
+	return internal.NewWrapper(parent, &internal.WrapperHooks{
+		Print: addScopeFields,
+	})
+}
+
+func addScopeFields(e *internal.Entry) bool {
+	fields := slog.ScopeFields(e.Loglet.Context())
+	if len(fields) == 0 {
+		return true
+	}
+
+	out := make(slog.Fields, len(fields))
+	for k, v := range fields {
+		if _, ok := e.Loglet.Field(k); !ok {
+			out[k] = v
+		}
+	}
+
+	if len(out) > 0 {
+		e.Logger = e.Logger.WithFields(out)
+	}
+	return true
+}
@@ -0,0 +1,104 @@
+package scope
+
+import (
+	"context"
+	"testing"
+
+	"darvaza.org/slog"
+	slogtest "darvaza.org/slog/internal/testing"
+)
+
+func TestScopeFields(t *testing.T) {
+	rec := slogtest.NewRecorder()
+	logger := New(rec).WithField("user", "logger")
+
+	ctx := slog.WithScopeFields(context.Background(),
+		slog.Fields{"request": "outer", "user": "scope"})
+	inner := slog.WithScopeFields(ctx, slog.Fields{"request": "inner"})
+
+	logger.Info().Print("none")
+	slog.WithContext(ctx, logger).Info().Print("outer")
+	slog.WithContext(inner, logger).Info().Print("inner")
+
+	msgs := rec.Messages()
+	if len(msgs) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(msgs))
+	}
+
+	for i, want := range []string{"", "outer", "inner"} {
+		fields := msgs[i].Fields
+		if got, _ := fields["request"].(string); got != want {
+			t.Errorf("%s: request %q, expected %q", msgs[i].Message, got, want)
+		}
+		if got := fields["user"]; got != "logger" {
+			t.Errorf("%s: user %v, expected the logger's", msgs[i].Message, got)
+		}
+	}
+}
+
+func TestWithScopeFieldsCopies(t *testing.T) {
+	fields := slog.Fields{"key": "before"}
+	ctx := slog.WithScopeFields(context.Background(), fields)
+	fields["key"] = "after"
+
+	if got := slog.ScopeFields(ctx)["key"]; got != "before" {
+		t.Errorf("scope fields modified through the caller's map: %v", got)
+	}
+}
+
+func TestPushScopeFields(t *testing.T) {
+	rec := slogtest.NewRecorder()
+	logger := New(rec)
+
+	slog.PushScopeFields(slog.Fields{"request": "outer", "user": "outer"})
+	slog.PushScopeFields(slog.Fields{"request": "inner"})
+	logger.Info().Print("inner")
+
+	// the context takes precedence
+	ctx := slog.WithScopeFields(context.Background(), slog.Fields{"user": "ctx"})
+	slog.WithContext(ctx, logger).Info().Print("ctx")
+
+	// not inherited by other goroutines
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		logger.Info().Print("other")
+	}()
+	<-done
+
+	slog.PopScopeFields()
+	logger.Info().Print("outer")
+	slog.PopScopeFields()
+	logger.Info().Print("none")
+
+	slogtest.AssertMessagesInOrder(t, rec.Messages(),
+		slogtest.Expect().Message("inner").Field("request", "inner").Field("user", "outer"),
+		slogtest.Expect().Message("ctx").Field("request", "inner").Field("user", "ctx"),
+		slogtest.Expect().Message("other").NoField("request"),
+		slogtest.Expect().Message("outer").Field("request", "outer"),
+		slogtest.Expect().Message("none").NoField("request").NoField("user"),
+	)
+}
+
+func TestRunWithScopeFields(t *testing.T) {
+	rec := slogtest.NewRecorder()
+	logger := New(rec)
+
+	func() {
+		defer func() { _ = recover() }()
+
+		slog.RunWithScopeFields(slog.Fields{"request": "x"}, func() {
+			logger.Info().Print("inside")
+			panic("boom")
+		})
+	}()
+	logger.Info().Print("after")
+
+	slogtest.AssertMessagesInOrder(t, rec.Messages(),
+		slogtest.Expect().Message("inside").Field("request", "x"),
+		slogtest.Expect().Message("after").NoField("request"),
+	)
+	if fields := slog.ScopeFields(context.Background()); fields != nil {
+		t.Errorf("fields left behind: %v", fields)
+	}
+}
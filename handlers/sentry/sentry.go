@@ -0,0 +1,276 @@
+// Package sentry provides a slog.Logger that sends Error, Fatal
+// and Panic entries to Sentry, passing them to a parent logger
+// as well
+package sentry
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"runtime"
+	"time"
+
+	sentrygo "github.com/getsentry/sentry-go"
+
+	"darvaza.org/core"
+	"darvaza.org/slog"
+	"darvaza.org/slog/internal"
+)
+
+const (
+	// DefaultFlushTimeout is the time Close() waits for
+	// pending events to be delivered
+	DefaultFlushTimeout = 2 * time.Second
+
+	// DefaultMaxErrorDepth is the number of wrapped errors
+	// reported as exceptions
+	DefaultMaxErrorDepth = 10
+)
+
+// Config describes which entries are sent to Sentry and how
+type Config struct {
+	// Hub is the Sentry hub used. Defaults to sentry.CurrentHub()
+	Hub *sentrygo.Hub
+
+	// TagFields lists the fields sent as tags. Other fields are
+	// sent as extra data
+	TagFields []string
+
+	// SampleRate is the fraction of entries sent, between 0 and 1.
+	// Zero means all of them
+	SampleRate float64
+
+	// FlushTimeout is how long Close() waits for pending events.
+	// Defaults to DefaultFlushTimeout
+	FlushTimeout time.Duration
+
+	// Threshold is the least severe level sent to Sentry.
+	// Defaults to slog.Error
+	Threshold slog.LogLevel
+
+	// Exclusive stops entries sent to Sentry from being passed
+	// to the parent logger too
+	Exclusive bool
+}
+
+// SetDefaults uses the current hub, DefaultFlushTimeout and
+// the Error threshold if not specified
+func (cfg *Config) SetDefaults() {
+	if cfg.Hub == nil {
+		cfg.Hub = sentrygo.CurrentHub()
+	}
+	if cfg.FlushTimeout <= 0 {
+		cfg.FlushTimeout = DefaultFlushTimeout
+	}
+	if cfg.Threshold <= slog.UndefinedLevel {
+		cfg.Threshold = slog.Error
+	}
+}
+
+// Client sends entries to Sentry
+type Client struct {
+	logger slog.Logger
+	tags   map[string]struct{}
+	cfg    Config
+}
+
+// New creates a Client sending entries of the Threshold level or
+// more severe to Sentry, and passing the rest, and those too unless
+// Exclusive, to the parent logger
+func New(parent slog.Logger, cfg *Config) (*Client, error) {
+	if parent == nil {
+		return nil, core.Wrap(core.ErrInvalid, "parent logger not specified")
+	}
+
+	var c Config
+	if cfg != nil {
+		c = *cfg
+	}
+	c.SetDefaults()
+
+	if c.SampleRate < 0 || c.SampleRate > 1 {
+		return nil, core.Wrapf(core.ErrInvalid, "invalid sample rate %v", c.SampleRate)
+	}
+
+	s := &Client{
+		cfg:  c,
+		tags: make(map[string]struct{}, len(c.TagFields)),
+	}
+	for _, k := range c.TagFields {
+		s.tags[k] = struct{}{}
+	}
+
+	s.logger = internal.NewWrapper(parent, &internal.WrapperHooks{
+		Print: s.print,
+		Flush: s.Flush,
+		Close: s.Close,
+	})
+	return s, nil
+}
+
+// Logger returns the slog.Logger sending entries through the Client
+func (s *Client) Logger() slog.Logger {
+	return s.logger
+}
+
+// Flush waits until pending events are delivered or the
+// context is cancelled
+func (s *Client) Flush(ctx context.Context) error {
+	if !s.cfg.Hub.FlushWithContext(ctx) {
+		return core.Wrap(ctx.Err(), "sentry: flush")
+	}
+	return nil
+}
+
+// Close waits, up to FlushTimeout, for pending events to
+// be delivered
+func (s *Client) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.cfg.FlushTimeout)
+	defer cancel()
+
+	return s.Flush(ctx)
+}
+
+func (s *Client) print(e *internal.Entry) bool {
+	level := e.Level()
+	if level <= slog.UndefinedLevel || level > s.cfg.Threshold {
+		return true
+	}
+
+	if s.sampled() {
+		s.cfg.Hub.CaptureEvent(s.newEvent(e))
+	}
+
+	switch {
+	case level == slog.Fatal || level == slog.Panic:
+		// deliver before terminating
+		_ = s.Close()
+		return s.terminate(e)
+	case s.cfg.Exclusive:
+		return false
+	default:
+		return true
+	}
+}
+
+// terminate lets the parent handle Fatal and Panic, or
+// handles them if Exclusive
+func (s *Client) terminate(e *internal.Entry) bool {
+	if !s.cfg.Exclusive {
+		return true
+	}
+
+	if e.Level() == slog.Fatal {
+		slog.Exit(1)
+	}
+	panic(core.NewPanicError(3, e.Message))
+}
+
+func (s *Client) sampled() bool {
+	rate := s.cfg.SampleRate
+	return rate == 0 || rate == 1 || rand.Float64() < rate
+}
+
+func (s *Client) newEvent(e *internal.Entry) *sentrygo.Event {
+	ev := sentrygo.NewEvent()
+	ev.Level = Level(e.Level())
+	ev.Message = e.Message
+	ev.Logger = e.Loglet.Name()
+	ev.Timestamp = e.Loglet.Time()
+	if ev.Timestamp.IsZero() {
+		ev.Timestamp = time.Now()
+	}
+
+	var err error
+	iter := e.Loglet.UniqueFields()
+	for iter.Next() {
+		k, v := iter.Field()
+		s.addField(ev, k, v)
+
+		if k == slog.ErrorFieldName && err == nil {
+			err, _ = v.(error)
+		}
+	}
+
+	st := Stacktrace(e.Loglet.CallStack())
+	if err != nil {
+		ev.SetException(err, DefaultMaxErrorDepth)
+		if n := len(ev.Exception); st != nil && n > 0 {
+			// the outermost error is last, and the call stack
+			// attached to the logger replaces the one taken here
+			ev.Exception[n-1].Stacktrace = st
+		}
+	} else if st != nil {
+		ev.Threads = []sentrygo.Thread{{
+			Stacktrace: st,
+			Crashed:    e.Level() <= slog.Fatal,
+			Current:    true,
+		}}
+	}
+
+	return ev
+}
+
+func (s *Client) addField(ev *sentrygo.Event, k string, v any) {
+	switch {
+	case k == slog.NameFieldName:
+		// Logger
+	case s.isTag(k):
+		ev.Tags[k] = fmt.Sprint(v)
+	default:
+		ev.Extra[k] = extraValue(v)
+	}
+}
+
+func (s *Client) isTag(k string) bool {
+	_, ok := s.tags[k]
+	return ok
+}
+
+func extraValue(v any) any {
+	switch x := v.(type) {
+	case core.Frame:
+		return slog.FormatCaller(x)
+	case error:
+		return x.Error()
+	case fmt.Stringer:
+		return x.String()
+	default:
+		return v
+	}
+}
+
+// Level converts a slog.LogLevel into a Sentry level
+func Level(level slog.LogLevel) sentrygo.Level {
+	switch level {
+	case slog.Panic, slog.Fatal:
+		return sentrygo.LevelFatal
+	case slog.Error:
+		return sentrygo.LevelError
+	case slog.Warn:
+		return sentrygo.LevelWarning
+	case slog.Info:
+		return sentrygo.LevelInfo
+	default:
+		return sentrygo.LevelDebug
+	}
+}
+
+// Stacktrace converts a call stack into a Sentry stack trace,
+// or nil if empty
+func Stacktrace(stack core.Stack) *sentrygo.Stacktrace {
+	if len(stack) == 0 {
+		return nil
+	}
+
+	// Sentry wants the oldest frame first
+	frames := make([]sentrygo.Frame, len(stack))
+	for i, f := range stack {
+		frames[len(stack)-1-i] = sentrygo.NewFrame(runtime.Frame{
+			Function: f.Name(),
+			File:     f.File(),
+			Line:     f.Line(),
+		})
+	}
+	return &sentrygo.Stacktrace{Frames: frames}
+}
@@ -0,0 +1,202 @@
+package sentry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	sentrygo "github.com/getsentry/sentry-go"
+
+	"darvaza.org/core"
+	"darvaza.org/slog"
+	slogtest "darvaza.org/slog/internal/testing"
+)
+
+// testTransport records the events sent
+type testTransport struct {
+	mu      sync.Mutex
+	events  []*sentrygo.Event
+	flushes int
+}
+
+func (*testTransport) Configure(sentrygo.ClientOptions) {}
+func (*testTransport) Close()                           {}
+
+func (tr *testTransport) SendEvent(ev *sentrygo.Event) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	tr.events = append(tr.events, ev)
+}
+
+func (tr *testTransport) Flush(time.Duration) bool {
+	return tr.FlushWithContext(context.Background())
+}
+
+func (tr *testTransport) FlushWithContext(context.Context) bool {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	tr.flushes++
+	return true
+}
+
+func (tr *testTransport) Events() []*sentrygo.Event {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	return append([]*sentrygo.Event(nil), tr.events...)
+}
+
+func newTestClient(t *testing.T, cfg *Config) (*Client, *testTransport, *slogtest.Recorder) {
+	t.Helper()
+
+	tr := new(testTransport)
+	client, err := sentrygo.NewClient(sentrygo.ClientOptions{Transport: tr})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := slogtest.NewRecorder()
+	cfg.Hub = sentrygo.NewHub(client, sentrygo.NewScope())
+	s, err := New(rec, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, tr, rec
+}
+
+func TestEvents(t *testing.T) {
+	s, tr, rec := newTestClient(t, &Config{TagFields: []string{"user"}})
+	l := slog.WithName(s.Logger(), "api")
+
+	l.Warn().Print("not sent")
+	l.Error().WithFields(map[string]any{
+		"user":              "alice",
+		"attempt":           3,
+		slog.ErrorFieldName: fmt.Errorf("login: %w", errors.New("denied")),
+	}).Print("failed")
+	l.Error().WithStack(0).Print("with stack")
+
+	// everything reaches the parent
+	slogtest.AssertMessagesInOrder(t, rec.Messages(),
+		slogtest.Expect().Level(slog.Warn).Message("not sent"),
+		slogtest.Expect().Level(slog.Error).Message("failed"),
+		slogtest.Expect().Level(slog.Error).Message("with stack"))
+
+	events := tr.Events()
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+
+	ev := events[0]
+	if ev.Level != sentrygo.LevelError || ev.Message != "failed" || ev.Logger != "api" {
+		t.Errorf("unexpected event: %+v", ev)
+	}
+	if ev.Tags["user"] != "alice" || ev.Extra["attempt"] != 3 {
+		t.Errorf("unexpected tags %v and extra %v", ev.Tags, ev.Extra)
+	}
+	if _, ok := ev.Extra["user"]; ok {
+		t.Error("tag sent as extra too")
+	}
+	if n := len(ev.Exception); n != 2 || ev.Exception[n-1].Value != "login: denied" {
+		t.Errorf("unexpected exceptions: %+v", ev.Exception)
+	}
+
+	ev = events[1]
+	if len(ev.Threads) != 1 || ev.Threads[0].Stacktrace == nil {
+		t.Fatalf("expected a thread with stack trace, got %+v", ev.Threads)
+	}
+	frames := ev.Threads[0].Stacktrace.Frames
+	if top := frames[len(frames)-1]; !strings.HasSuffix(top.Function, "TestEvents") {
+		t.Errorf("expected TestEvents as newest frame, got %q", top.Function)
+	}
+}
+
+func TestExclusive(t *testing.T) {
+	s, tr, rec := newTestClient(t, &Config{
+		Threshold: slog.Warn,
+		Exclusive: true,
+	})
+	l := s.Logger()
+
+	l.Info().Print("parent only")
+	l.Warn().Print("sentry only")
+
+	slogtest.AssertMessagesInOrder(t, rec.Messages(),
+		slogtest.Expect().Level(slog.Info).Message("parent only"))
+	if events := tr.Events(); len(events) != 1 || events[0].Level != sentrygo.LevelWarning {
+		t.Errorf("unexpected events: %v", events)
+	}
+}
+
+func TestFatal(t *testing.T) {
+	s, tr, rec := newTestClient(t, &Config{})
+
+	s.Logger().Fatal().Print("fatal")
+
+	// sent and flushed before the parent terminates
+	if n := len(tr.Events()); n != 1 || tr.flushes == 0 {
+		t.Errorf("expected the event flushed, got %d events and %d flushes", n, tr.flushes)
+	}
+	slogtest.AssertMessagesInOrder(t, rec.Messages(),
+		slogtest.Expect().Level(slog.Fatal).Message("fatal"))
+}
+
+func TestExclusivePanic(t *testing.T) {
+	s, tr, rec := newTestClient(t, &Config{Exclusive: true})
+
+	defer func() {
+		err, ok := recover().(*core.PanicError)
+		if !ok {
+			t.Fatalf("expected *core.PanicError, got %v", err)
+		}
+		if fmt.Sprint(err.Recovered()) != "panic" {
+			t.Errorf("unexpected payload %v", err.Recovered())
+		}
+		if n := len(tr.Events()); n != 1 {
+			t.Errorf("expected 1 event, got %d", n)
+		}
+		if n := rec.Count(); n != 0 {
+			t.Errorf("unexpected %d entries on the parent", n)
+		}
+	}()
+
+	s.Logger().Panic().Print("panic")
+}
+
+func TestSampleRate(t *testing.T) {
+	s, tr, _ := newTestClient(t, &Config{SampleRate: 0.000001})
+	for i := 0; i < 10; i++ {
+		s.Logger().Error().Print("rarely sent")
+	}
+	if n := len(tr.Events()); n > 1 {
+		t.Errorf("expected events to be sampled, got %d", n)
+	}
+
+	rec := slogtest.NewRecorder()
+	for _, rate := range []float64{-0.5, 1.5} {
+		if _, err := New(rec, &Config{SampleRate: rate}); err == nil {
+			t.Errorf("%v: expected error", rate)
+		}
+	}
+}
+
+func TestLevel(t *testing.T) {
+	for level, want := range map[slog.LogLevel]sentrygo.Level{
+		slog.Panic: sentrygo.LevelFatal,
+		slog.Fatal: sentrygo.LevelFatal,
+		slog.Error: sentrygo.LevelError,
+		slog.Warn:  sentrygo.LevelWarning,
+		slog.Info:  sentrygo.LevelInfo,
+		slog.Debug: sentrygo.LevelDebug,
+	} {
+		if got := Level(level); got != want {
+			t.Errorf("%s: expected %s, got %s", level, want, got)
+		}
+	}
+}
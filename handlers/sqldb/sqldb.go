@@ -0,0 +1,221 @@
+// Package sqldb provides a slog.Logger that stores entries
+// in a SQL table
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"darvaza.org/core"
+	"darvaza.org/slog"
+	"darvaza.org/slog/handlers/cblog"
+	"darvaza.org/slog/internal"
+)
+
+const (
+	// DefaultTable is the name of the table used if none is given
+	DefaultTable = "logs"
+	// DefaultBatchSize is the maximum number of entries
+	// inserted at once
+	DefaultBatchSize = 100
+	// DefaultBatchInterval is how long entries can wait before
+	// being inserted if the batch isn't full
+	DefaultBatchInterval = time.Second
+	// DefaultRetentionInterval is how often old entries are deleted
+	DefaultRetentionInterval = time.Minute
+	// DefaultWriteTimeout is the maximum time to wait for a
+	// batch to be inserted
+	DefaultWriteTimeout = 10 * time.Second
+)
+
+var reTable = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// Placeholder returns the placeholder of the nth, starting at 1,
+// argument of a statement
+type Placeholder func(n int) string
+
+// QuestionPlaceholder uses ? for all arguments, as SQLite and
+// MySQL do
+func QuestionPlaceholder(int) string { return "?" }
+
+// DollarPlaceholder uses $n for the nth argument, as PostgreSQL does
+func DollarPlaceholder(n int) string { return "$" + strconv.Itoa(n) }
+
+// Config describes the table and how entries are stored
+type Config struct {
+	// DB is the database used. It isn't closed by the Client
+	DB *sql.DB
+	// Placeholder renders the arguments of the statements.
+	// Defaults to QuestionPlaceholder
+	Placeholder Placeholder
+	// Clock timestamps the entries, paces the BatchInterval and
+	// RetentionInterval, and tells the age of the entries for
+	// MaxAge. Defaults to the system clock
+	Clock cblog.Clock
+	// OnError is called when storing or deleting entries fails,
+	// instead of reporting it using slog.ReportError()
+	OnError func(error)
+
+	// Table is the name of the table. Defaults to DefaultTable
+	Table string
+	// CreateTable creates the table if it doesn't exist
+	CreateTable bool
+
+	// MaxRows is the maximum number of entries kept.
+	// Zero means no limit
+	MaxRows int
+	// MaxAge is the maximum age of the entries kept.
+	// Zero means no limit
+	MaxAge time.Duration
+
+	// BatchSize is the maximum number of entries inserted at
+	// once. Defaults to DefaultBatchSize
+	BatchSize int
+	// BufferSize is the number of entries that can wait to be
+	// batched before logging blocks. Defaults to
+	// cblog.DefaultOutputBufferSize
+	BufferSize int
+	// BatchInterval is how long entries can wait before being
+	// inserted if the batch isn't full. Defaults to
+	// DefaultBatchInterval
+	BatchInterval time.Duration
+	// RetentionInterval is how often entries beyond MaxRows or
+	// MaxAge are deleted. Defaults to DefaultRetentionInterval
+	RetentionInterval time.Duration
+	// WriteTimeout is the maximum time to wait for each insert
+	// or delete. Defaults to DefaultWriteTimeout
+	WriteTimeout time.Duration
+}
+
+// SetDefaults sets the placeholder, table name, batching and
+// timeouts not specified, and the system clock
+func (cfg *Config) SetDefaults() {
+	if cfg.Clock == nil {
+		cfg.Clock = internal.SystemClock
+	}
+	if cfg.Placeholder == nil {
+		cfg.Placeholder = QuestionPlaceholder
+	}
+	if cfg.Table == "" {
+		cfg.Table = DefaultTable
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = DefaultBatchSize
+	}
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = cblog.DefaultOutputBufferSize
+	}
+	if cfg.BatchInterval <= 0 {
+		cfg.BatchInterval = DefaultBatchInterval
+	}
+	if cfg.RetentionInterval <= 0 {
+		cfg.RetentionInterval = DefaultRetentionInterval
+	}
+	if cfg.WriteTimeout <= 0 {
+		cfg.WriteTimeout = DefaultWriteTimeout
+	}
+}
+
+// Validate checks the database is set, the Table name is safe to
+// use in statements, and the retention limits aren't negative
+func (cfg *Config) Validate() error {
+	switch {
+	case cfg.DB == nil:
+		return core.Wrap(core.ErrInvalid, "sqldb: database not specified")
+	case !reTable.MatchString(cfg.Table):
+		return core.Wrapf(core.ErrInvalid, "sqldb: invalid table name %q", cfg.Table)
+	case cfg.MaxRows < 0:
+		return core.Wrap(core.ErrInvalid, "sqldb: negative MaxRows")
+	case cfg.MaxAge < 0:
+		return core.Wrap(core.ErrInvalid, "sqldb: negative MaxAge")
+	default:
+		return nil
+	}
+}
+
+// Client stores the entries of its slog.Logger in a SQL table
+type Client struct {
+	logger  *cblog.Logger
+	dropped atomic.Uint64
+	backend internal.Backend
+	cfg     Config
+}
+
+// New creates a Client and starts storing entries in the
+// table described by the Config, creating it first if
+// CreateTable is set.
+func New(cfg *Config) (*Client, error) {
+	if cfg == nil {
+		return nil, core.Wrap(core.ErrInvalid, "sqldb: config not specified")
+	}
+
+	c := &Client{
+		cfg: *cfg,
+	}
+	c.cfg.SetDefaults()
+	if err := c.cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	c.backend.Init("sqldb", c.cfg.OnError)
+
+	if c.cfg.CreateTable {
+		if err := c.createTable(); err != nil {
+			c.backend.Abort()
+			return nil, err
+		}
+	}
+
+	logger, ch := cblog.New(make(chan cblog.LogMsg, c.cfg.BufferSize),
+		cblog.WithClock(c.cfg.Clock),
+		cblog.WithHealthCheck(c.Health),
+		cblog.WithStats(c.Stats),
+		cblog.WithFlush(c.backend.Flush))
+	c.logger = logger
+
+	c.backend.Start(logger.Shutdown, func() { c.run(ch) })
+	return c, nil
+}
+
+// Logger returns the slog.Logger whose entries are stored
+func (c *Client) Logger() slog.Logger {
+	return c.logger
+}
+
+// DroppedCount returns the number of entries that couldn't
+// be stored
+func (c *Client) DroppedCount() uint64 {
+	return c.dropped.Load()
+}
+
+// Health returns the last error storing entries,
+// until an insert succeeds
+func (c *Client) Health() error {
+	return c.backend.Err()
+}
+
+// Stats returns the number of entries dropped by the Client, as
+// slog.DroppedStat, when a batch can't be inserted. Entries waiting
+// to be batched are reported by the cblog.Logger returned by Logger().
+func (c *Client) Stats() slog.Stats {
+	return slog.Stats{
+		slog.DroppedStat: c.dropped.Load(),
+	}
+}
+
+// Close stops accepting new entries and waits, up to
+// cblog.DefaultShutdownTimeout, for the pending ones to be stored.
+func (c *Client) Close() error {
+	return c.backend.Close(cblog.DefaultShutdownTimeout)
+}
+
+// Shutdown stops accepting new entries and waits for the pending
+// ones to be stored. If the context is cancelled first, the
+// batch in flight is aborted.
+func (c *Client) Shutdown(ctx context.Context) error {
+	return c.backend.Shutdown(ctx)
+}
@@ -0,0 +1,290 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"darvaza.org/slog"
+	slogtest "darvaza.org/slog/internal/testing"
+)
+
+// query is a statement executed on a fakeDB
+type query struct {
+	sql  string
+	args []driver.Value
+}
+
+// fakeDB is a database/sql driver recording the statements
+// executed, and answering queries with a single row
+type fakeDB struct {
+	mu      sync.Mutex
+	queries []query
+
+	// row is the value returned by queries, none if nil
+	row driver.Value
+	// err is returned by INSERT statements
+	err error
+}
+
+func (db *fakeDB) Connect(context.Context) (driver.Conn, error) { return db, nil }
+func (db *fakeDB) Driver() driver.Driver                        { return nil }
+
+func (db *fakeDB) Prepare(q string) (driver.Stmt, error) { return &fakeStmt{db: db, sql: q}, nil }
+func (*fakeDB) Close() error                             { return nil }
+func (db *fakeDB) Begin() (driver.Tx, error)             { return db, nil }
+func (*fakeDB) Commit() error                            { return nil }
+func (*fakeDB) Rollback() error                          { return nil }
+
+func (db *fakeDB) record(q string, args []driver.Value) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.queries = append(db.queries, query{sql: q, args: args})
+}
+
+// Queries returns the statements executed
+func (db *fakeDB) Queries() []query {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	return append([]query(nil), db.queries...)
+}
+
+type fakeStmt struct {
+	db  *fakeDB
+	sql string
+}
+
+func (*fakeStmt) Close() error  { return nil }
+func (*fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.db.record(s.sql, args)
+	if strings.HasPrefix(s.sql, "INSERT") && s.db.err != nil {
+		return nil, s.db.err
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.db.record(s.sql, args)
+	return &fakeRows{row: s.db.row}, nil
+}
+
+type fakeRows struct {
+	row  driver.Value
+	done bool
+}
+
+func (*fakeRows) Columns() []string { return []string{TimeColumn} }
+func (*fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.done || r.row == nil {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = r.row
+	return nil
+}
+
+func newTestClient(t *testing.T, db *fakeDB, cfg *Config) *Client {
+	t.Helper()
+
+	cfg.DB = sql.OpenDB(db)
+	c, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+	return c
+}
+
+func flush(t *testing.T, c *Client) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := slog.Flush(ctx, c.Logger()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestInsert(t *testing.T) {
+	db := new(fakeDB)
+	c := newTestClient(t, db, &Config{
+		CreateTable:   true,
+		BatchInterval: time.Hour,
+	})
+
+	l := c.Logger()
+	l.Info().WithField("key", "value").Print("first")
+	l.Warn().Print("second")
+	flush(t, c)
+
+	queries := db.Queries()
+	if len(queries) != 3 {
+		t.Fatalf("expected 3 statements, got %v", queries)
+	}
+	if q := queries[0].sql; q != Schema(DefaultTable) {
+		t.Errorf("expected the table created first, got %q", q)
+	}
+
+	for i, want := range [][]driver.Value{
+		{"info", "first", `{"key":"value"}`, nil},
+		{"warn", "second", nil, nil},
+	} {
+		q := queries[i+1]
+		if !strings.HasPrefix(q.sql, "INSERT INTO logs ") {
+			t.Errorf("#%d: unexpected statement %q", i, q.sql)
+			continue
+		}
+		for j, v := range want {
+			if got := q.args[j+1]; got != v {
+				t.Errorf("#%d: expected %v, got %v", i, v, got)
+			}
+		}
+	}
+}
+
+func TestInsertFailure(t *testing.T) {
+	db := &fakeDB{err: errors.New("disk full")}
+	var reported int
+	c := newTestClient(t, db, &Config{
+		BatchInterval: time.Hour,
+		OnError:       func(error) { reported++ },
+	})
+
+	l := c.Logger().Info()
+	l.Print("first")
+	l.Print("second")
+	flush(t, c)
+
+	if n := c.DroppedCount(); n != 2 {
+		t.Errorf("expected 2 entries dropped, got %d", n)
+	}
+	if n := c.Stats()[slog.DroppedStat]; n != 2 {
+		t.Errorf("expected %s 2, got %d", slog.DroppedStat, n)
+	}
+	if reported != 1 || c.Health() == nil {
+		t.Errorf("expected the error reported once, got %d", reported)
+	}
+}
+
+// TestPruneMySQL checks the statements pruning old entries don't
+// use subqueries on the table being deleted from, which MySQL
+// rejects
+func TestPruneMySQL(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cutoff := start.Add(-time.Minute)
+
+	clock := slogtest.NewManualClock(start)
+	db := &fakeDB{row: cutoff}
+	newTestClient(t, db, &Config{
+		Clock:   clock,
+		MaxRows: 10,
+		MaxAge:  time.Hour,
+	})
+
+	// batch and retention timers
+	waitFor(t, func() bool { return clock.Waiters() == 2 })
+	clock.Advance(DefaultRetentionInterval)
+	waitFor(t, func() bool { return len(db.Queries()) == 3 })
+
+	queries := db.Queries()
+	for _, q := range queries {
+		if strings.Contains(q.sql, "(SELECT") {
+			t.Errorf("subquery in %q", q.sql)
+		}
+	}
+
+	expectQuery(t, queries[0], "DELETE FROM logs WHERE time < ?",
+		start.Add(DefaultRetentionInterval).Add(-time.Hour))
+	expectQuery(t, queries[1], "SELECT time FROM logs ORDER BY time DESC LIMIT 1 OFFSET ?",
+		int64(9))
+	expectQuery(t, queries[2], "DELETE FROM logs WHERE time < ?", cutoff)
+}
+
+func TestPruneWithinMaxRows(t *testing.T) {
+	clock := slogtest.NewManualClock(time.Now())
+	db := new(fakeDB)
+	c := newTestClient(t, db, &Config{
+		Clock:   clock,
+		MaxRows: 10,
+	})
+
+	waitFor(t, func() bool { return clock.Waiters() == 2 })
+	clock.Advance(DefaultRetentionInterval)
+	waitFor(t, func() bool { return len(db.Queries()) > 0 })
+	// the prune is done once the flush is handled
+	flush(t, c)
+
+	queries := db.Queries()
+	if len(queries) != 1 || !strings.HasPrefix(queries[0].sql, "SELECT") {
+		t.Errorf("expected only the cutoff selected, got %v", queries)
+	}
+}
+
+func expectQuery(t *testing.T, q query, sql string, args ...driver.Value) {
+	t.Helper()
+
+	if q.sql != sql {
+		t.Errorf("expected %q, got %q", sql, q.sql)
+	}
+	if len(q.args) != len(args) {
+		t.Errorf("%q: expected %v, got %v", sql, args, q.args)
+		return
+	}
+	for i, v := range args {
+		if tv, ok := v.(time.Time); ok {
+			if got, _ := q.args[i].(time.Time); !got.Equal(tv) {
+				t.Errorf("%q: expected %v, got %v", sql, v, q.args[i])
+			}
+		} else if q.args[i] != v {
+			t.Errorf("%q: expected %v, got %v", sql, v, q.args[i])
+		}
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	db := sql.OpenDB(new(fakeDB))
+	for _, cfg := range []*Config{
+		nil,
+		{},
+		{DB: db, Table: "logs; DROP TABLE users"},
+		{DB: db, MaxRows: -1},
+		{DB: db, MaxAge: -time.Second},
+	} {
+		if _, err := New(cfg); err == nil {
+			t.Errorf("%+v: expected error", cfg)
+		}
+	}
+}
+
+func TestDollarPlaceholder(t *testing.T) {
+	c := &Client{cfg: Config{Table: "logs", Placeholder: DollarPlaceholder}}
+	want := "INSERT INTO logs (time, level, message, fields, stack) " +
+		"VALUES ($1, $2, $3, $4, $5)"
+	if q := c.insertQuery(); q != want {
+		t.Errorf("expected %q, got %q", want, q)
+	}
+}
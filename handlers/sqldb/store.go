@@ -0,0 +1,201 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"darvaza.org/core"
+	"darvaza.org/slog"
+	"darvaza.org/slog/handlers/cblog"
+	"darvaza.org/slog/internal"
+)
+
+// Columns of the table
+const (
+	TimeColumn    = "time"
+	LevelColumn   = "level"
+	MessageColumn = "message"
+	FieldsColumn  = "fields"
+	StackColumn   = "stack"
+)
+
+// Schema returns a CREATE TABLE statement for the given table,
+// using types understood by SQLite, PostgreSQL and MySQL
+func Schema(table string) string {
+	return fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s ("+
+		"%s TIMESTAMP NOT NULL, "+
+		"%s VARCHAR(8) NOT NULL, "+
+		"%s TEXT NOT NULL, "+
+		"%s TEXT, "+
+		"%s TEXT)",
+		table, TimeColumn, LevelColumn, MessageColumn, FieldsColumn, StackColumn)
+}
+
+func (c *Client) createTable() error {
+	ctx, cancel := context.WithTimeout(c.backend.Context(), c.cfg.WriteTimeout)
+	defer cancel()
+
+	if _, err := c.cfg.DB.ExecContext(ctx, Schema(c.cfg.Table)); err != nil {
+		return core.Wrap(err, "sqldb: create table")
+	}
+	return nil
+}
+
+func (c *Client) run(ch <-chan cblog.LogMsg) {
+	var batch []cblog.LogMsg
+
+	tick := c.cfg.Clock.After(c.cfg.BatchInterval)
+	prune := c.cfg.Clock.After(c.cfg.RetentionInterval)
+
+	for {
+		select {
+		case msg, ok := <-ch:
+			if !ok {
+				c.insert(batch)
+				return
+			}
+
+			batch = append(batch, msg)
+			if len(batch) >= c.cfg.BatchSize {
+				c.insert(batch)
+				batch = batch[:0]
+			}
+		case <-tick:
+			tick = c.cfg.Clock.After(c.cfg.BatchInterval)
+			c.insert(batch)
+			batch = batch[:0]
+		case req := <-c.backend.Flushes():
+			c.insert(batch)
+			batch = batch[:0]
+			close(req)
+		case <-prune:
+			prune = c.cfg.Clock.After(c.cfg.RetentionInterval)
+			c.prune()
+		}
+	}
+}
+
+func (c *Client) insertQuery() string {
+	p := c.cfg.Placeholder
+	return fmt.Sprintf("INSERT INTO %s (%s, %s, %s, %s, %s) VALUES (%s, %s, %s, %s, %s)",
+		c.cfg.Table, TimeColumn, LevelColumn, MessageColumn, FieldsColumn, StackColumn,
+		p(1), p(2), p(3), p(4), p(5))
+}
+
+// insert stores a batch of entries within a transaction
+func (c *Client) insert(batch []cblog.LogMsg) {
+	if len(batch) == 0 {
+		return
+	}
+
+	if err := c.doInsert(batch); err != nil {
+		c.backend.ReportError(core.Wrap(err, "sqldb: insert"))
+		c.dropped.Add(uint64(len(batch)))
+	} else {
+		c.backend.ClearError()
+	}
+}
+
+func (c *Client) doInsert(batch []cblog.LogMsg) error {
+	ctx, cancel := context.WithTimeout(c.backend.Context(), c.cfg.WriteTimeout)
+	defer cancel()
+
+	tx, err := c.cfg.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	stmt, err := tx.PrepareContext(ctx, c.insertQuery())
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, msg := range batch {
+		_, err := stmt.ExecContext(ctx, msg.Time, msg.Level.String(),
+			msg.Message, encodeFields(msg.Fields), encodeStack(msg))
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// prune deletes the entries exceeding MaxAge or MaxRows
+func (c *Client) prune() {
+	if c.cfg.MaxAge > 0 {
+		q := fmt.Sprintf("DELETE FROM %s WHERE %s < %s",
+			c.cfg.Table, TimeColumn, c.cfg.Placeholder(1))
+		c.exec(q, c.cfg.Clock.Now().Add(-c.cfg.MaxAge))
+	}
+
+	if c.cfg.MaxRows > 0 {
+		// MySQL doesn't allow subqueries on the table being
+		// deleted from, so the cutoff is selected first
+		if cutoff, ok := c.rowsCutoff(); ok {
+			q := fmt.Sprintf("DELETE FROM %s WHERE %s < %s",
+				c.cfg.Table, TimeColumn, c.cfg.Placeholder(1))
+			c.exec(q, cutoff)
+		}
+	}
+}
+
+// rowsCutoff returns the time of the oldest entry within MaxRows,
+// as returned by the driver, if there are more
+func (c *Client) rowsCutoff() (any, bool) {
+	ctx, cancel := context.WithTimeout(c.backend.Context(), c.cfg.WriteTimeout)
+	defer cancel()
+
+	q := fmt.Sprintf("SELECT %[2]s FROM %[1]s ORDER BY %[2]s DESC LIMIT 1 OFFSET %[3]s",
+		c.cfg.Table, TimeColumn, c.cfg.Placeholder(1))
+
+	var cutoff any
+	err := c.cfg.DB.QueryRowContext(ctx, q, c.cfg.MaxRows-1).Scan(&cutoff)
+	switch {
+	case err == nil:
+		return cutoff, true
+	case !errors.Is(err, sql.ErrNoRows):
+		c.backend.ReportError(core.Wrap(err, "sqldb: prune"))
+	}
+	return nil, false
+}
+
+func (c *Client) exec(query string, args ...any) {
+	ctx, cancel := context.WithTimeout(c.backend.Context(), c.cfg.WriteTimeout)
+	defer cancel()
+
+	if _, err := c.cfg.DB.ExecContext(ctx, query, args...); err != nil {
+		c.backend.ReportError(core.Wrap(err, "sqldb: prune"))
+	}
+}
+
+// encodeFields renders the fields of an entry as a JSON
+// object, or nil if there are none
+func encodeFields(fields map[string]any) any {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	out := internal.JSONFields(fields, 0)
+
+	b, err := json.Marshal(out)
+	if err != nil {
+		return nil
+	}
+	return string(b)
+}
+
+// encodeStack renders the call stack of an entry, or nil
+// if it has none
+func encodeStack(msg cblog.LogMsg) any {
+	if len(msg.Stack) == 0 {
+		return nil
+	}
+	return strings.TrimSpace(slog.FormatStack(msg.Stack))
+}
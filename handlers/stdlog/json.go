@@ -0,0 +1,66 @@
+package stdlog
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"darvaza.org/core"
+	"darvaza.org/slog"
+)
+
+// Keys used by NewJSON. Fields using these names are overridden.
+const (
+	TimeKey    = "time"
+	LevelKey   = "level"
+	MessageKey = "message"
+	StackKey   = "stack"
+)
+
+// renderJSON composes the JSON object given to the *log.Logger
+func (l *Logger) renderJSON(msg string) string {
+	fields := l.FieldsMap()
+
+	out := make(map[string]any, len(fields)+4)
+	for k, v := range fields {
+		out[k] = jsonValue(v)
+	}
+
+	t := l.Time()
+	if t.IsZero() {
+		t = time.Now()
+	}
+	out[TimeKey] = t.Format(time.RFC3339Nano)
+	if level := l.Level(); level > slog.UndefinedLevel {
+		out[LevelKey] = level.String()
+	}
+	out[MessageKey] = msg
+	if stack := l.CallStack(); len(stack) > 0 {
+		out[StackKey] = slog.StackFrames(stack)
+	}
+
+	b, err := json.Marshal(out)
+	if err != nil {
+		// unreachable, values are sanitised
+		return fmt.Sprintf("{%q:%q}", MessageKey, msg)
+	}
+	return string(b)
+}
+
+func jsonValue(v any) any {
+	switch x := v.(type) {
+	case nil, json.Marshaler:
+		return v
+	case core.Frame:
+		return slog.FormatCaller(x)
+	case error:
+		return x.Error()
+	case fmt.Stringer:
+		return x.String()
+	}
+
+	if _, err := json.Marshal(v); err != nil {
+		return fmt.Sprint(v)
+	}
+	return v
+}
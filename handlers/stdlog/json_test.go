@@ -0,0 +1,41 @@
+package stdlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log"
+	"testing"
+	"time"
+)
+
+func TestJSON(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewJSON(log.New(&buf, "", 0))
+
+	l.Warn().WithFields(map[string]any{
+		"err":      errors.New("failed"),
+		"ch":       make(chan int),
+		MessageKey: "overridden",
+	}).WithStack(0).Print("entry")
+
+	var out map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("%v: %q", err, buf)
+	}
+
+	if out[MessageKey] != "entry" || out[LevelKey] != "warn" {
+		t.Errorf("unexpected entry %v", out)
+	}
+	if out["err"] != "failed" || out["ch"] == nil {
+		t.Errorf("unexpected fields %v", out)
+	}
+	if s, _ := out[TimeKey].(string); s == "" {
+		t.Error("missing time")
+	} else if _, err := time.Parse(time.RFC3339Nano, s); err != nil {
+		t.Error(err)
+	}
+	if stack, _ := out[StackKey].([]any); len(stack) == 0 {
+		t.Errorf("missing stack: %v", out[StackKey])
+	}
+}
@@ -0,0 +1,274 @@
+// Package stdlog bridges slog.Logger and the standard *log.Logger
+package stdlog
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"darvaza.org/core"
+	"darvaza.org/slog"
+	"darvaza.org/slog/internal"
+)
+
+var (
+	_ slog.Logger       = (*Logger)(nil)
+	_ slog.CallerLogger = (*Logger)(nil)
+	_ slog.NamedLogger  = (*Logger)(nil)
+	_ slog.TimedLogger  = (*Logger)(nil)
+	_ slog.StackLogger  = (*Logger)(nil)
+)
+
+var levelNames = []string{
+	slog.UndefinedLevel: "",
+	slog.Panic:          "PANIC",
+	slog.Fatal:          "FATAL",
+	slog.Error:          "ERROR",
+	slog.Warn:           "WARN",
+	slog.Info:           "INFO",
+	slog.Debug:          "DEBUG",
+}
+
+// Logger is a slog.Logger using a standard *log.Logger as backend
+type Logger struct {
+	internal.Loglet
+
+	logger *log.Logger
+	json   bool
+}
+
+// Enabled tells this logger is enabled
+func (l *Logger) Enabled() bool {
+	return l != nil && l.logger != nil
+}
+
+// WithEnabled passes the logger and if it's enabled
+func (l *Logger) WithEnabled() (slog.Logger, bool) {
+	return l, l.Enabled()
+}
+
+// Print adds a log entry with arguments handled in the manner of fmt.Print
+func (l *Logger) Print(args ...any) {
+	l.msg(fmt.Sprint(args...))
+}
+
+// Println adds a log entry with arguments handled in the manner of fmt.Println
+func (l *Logger) Println(args ...any) {
+	l.msg(fmt.Sprintln(args...))
+}
+
+// Printf adds a log entry with arguments handled in the manner of fmt.Printf
+func (l *Logger) Printf(format string, args ...any) {
+	l.msg(internal.Sprintf(format, args...))
+}
+
+func (l *Logger) msg(msg string) {
+	msg = strings.TrimSpace(msg)
+
+	if l.Enabled() {
+		_ = l.logger.Output(3, l.render(msg))
+	}
+
+	switch l.Level() {
+	case slog.Fatal:
+		slog.Exit(1)
+	case slog.Panic:
+		panic(core.NewPanicError(2, msg))
+	}
+}
+
+// render composes the line given to the *log.Logger
+func (l *Logger) render(msg string) string {
+	if l.json {
+		return l.renderJSON(msg)
+	}
+
+	var buf strings.Builder
+
+	if level := l.Level(); level > slog.UndefinedLevel && int(level) < len(levelNames) {
+		buf.WriteString("[")
+		buf.WriteString(levelNames[level])
+		buf.WriteString("] ")
+	}
+	buf.WriteString(msg)
+
+	if fields := l.FieldsMap(); len(fields) > 0 {
+		for _, k := range core.SortedKeys(fields) {
+			_, _ = fmt.Fprintf(&buf, " %s=%q", k, renderValue(fields[k]))
+		}
+	}
+
+	if stack := l.CallStack(); len(stack) > 0 {
+		buf.WriteString("\n")
+		buf.WriteString(slog.FormatStack(stack))
+	}
+
+	return buf.String()
+}
+
+func renderValue(v any) string {
+	if f, ok := v.(core.Frame); ok {
+		return slog.FormatCaller(f)
+	}
+	return fmt.Sprint(v)
+}
+
+// Debug returns a new logger set to add entries as level Debug
+func (l *Logger) Debug() slog.Logger {
+	return l.WithLevel(slog.Debug)
+}
+
+// Info returns a new logger set to add entries as level Info
+func (l *Logger) Info() slog.Logger {
+	return l.WithLevel(slog.Info)
+}
+
+// Warn returns a new logger set to add entries as level Warn
+func (l *Logger) Warn() slog.Logger {
+	return l.WithLevel(slog.Warn)
+}
+
+// Error returns a new logger set to add entries as level Error
+func (l *Logger) Error() slog.Logger {
+	return l.WithLevel(slog.Error)
+}
+
+// Fatal returns a new logger set to add entries as level Fatal
+func (l *Logger) Fatal() slog.Logger {
+	return l.WithLevel(slog.Fatal)
+}
+
+// Panic returns a new logger set to add entries as level Panic
+func (l *Logger) Panic() slog.Logger {
+	return l.WithLevel(slog.Panic)
+}
+
+// WithLevel returns a new logger set to add entries to the specified level
+func (l *Logger) WithLevel(level slog.LogLevel) slog.Logger {
+	if level <= slog.UndefinedLevel {
+		// fix your code
+		l.Panic().WithStack(1).Printf("slog: invalid log level %v", level)
+	} else if level == l.Level() {
+		return l
+	}
+
+	return &Logger{
+		Loglet: l.Loglet.WithLevel(level),
+		logger: l.logger,
+		json:   l.json,
+	}
+}
+
+// WithStack attaches a call stack to a new logger
+func (l *Logger) WithStack(skip int) slog.Logger {
+	return &Logger{
+		Loglet: l.Loglet.WithStack(skip + 1),
+		logger: l.logger,
+		json:   l.json,
+	}
+}
+
+// WithCallStack attaches a given call stack to a new logger
+func (l *Logger) WithCallStack(st core.Stack) slog.Logger {
+	return &Logger{
+		Loglet: l.Loglet.WithCallStack(st),
+		logger: l.logger,
+		json:   l.json,
+	}
+}
+
+// WithTime records when the event happened on a new logger.
+// The JSON format uses it as time, otherwise it's rendered as
+// slog.TimeFieldName field.
+func (l *Logger) WithTime(t time.Time) slog.Logger {
+	ll := l.Loglet.WithTime(t)
+	if !l.json {
+		ll = l.Loglet.WithField(slog.TimeFieldName, t)
+	}
+
+	return &Logger{
+		Loglet: ll,
+		logger: l.logger,
+		json:   l.json,
+	}
+}
+
+// WithCaller attaches the immediate caller to a new logger
+func (l *Logger) WithCaller(skip int) slog.Logger {
+	return &Logger{
+		Loglet: l.Loglet.WithCaller(skip + 1),
+		logger: l.logger,
+		json:   l.json,
+	}
+}
+
+// WithName appends a component to the dot-separated name of a
+// new logger, rendered as slog.NameFieldName field
+func (l *Logger) WithName(name string) slog.Logger {
+	if name != "" {
+		return &Logger{
+			Loglet: l.Loglet.WithName(name),
+			logger: l.logger,
+			json:   l.json,
+		}
+	}
+	return l
+}
+
+// WithField returns a new logger with a field attached
+func (l *Logger) WithField(label string, value any) slog.Logger {
+	if label != "" {
+		return &Logger{
+			Loglet: l.Loglet.WithField(label, value),
+			logger: l.logger,
+			json:   l.json,
+		}
+	}
+	return l
+}
+
+// WithFields returns a new logger with a set of fields attached
+func (l *Logger) WithFields(fields map[string]any) slog.Logger {
+	delete(fields, "")
+
+	if len(fields) > 0 {
+		return &Logger{
+			Loglet: l.Loglet.WithFields(fields),
+			logger: l.logger,
+			json:   l.json,
+		}
+	}
+	return l
+}
+
+// New creates a slog.Logger adaptor using a standard *log.Logger
+// as backend. Entries are written as a single line prefixed by their
+// level, followed by the fields as key="value" pairs.
+// If none is given, log.Default() is used.
+func New(logger *log.Logger) slog.Logger {
+	if logger == nil {
+		logger = log.Default()
+	}
+
+	return &Logger{
+		logger: logger,
+	}
+}
+
+// NewJSON creates a slog.Logger adaptor using a standard *log.Logger
+// as backend, writing each entry as a single line JSON object with
+// its time, level, message, fields and call stack. The *log.Logger
+// should have neither prefix nor flags.
+// If none is given, a new one writing to os.Stderr is used.
+func NewJSON(logger *log.Logger) slog.Logger {
+	if logger == nil {
+		logger = log.New(os.Stderr, "", 0)
+	}
+
+	return &Logger{
+		logger: logger,
+		json:   true,
+	}
+}
@@ -0,0 +1,89 @@
+package stdlog
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"testing"
+
+	"darvaza.org/core"
+	"darvaza.org/slog"
+	slogtest "darvaza.org/slog/internal/testing"
+)
+
+func newTestLogger() (slog.Logger, *bytes.Buffer) {
+	var buf bytes.Buffer
+	return New(log.New(&buf, "", 0)), &buf
+}
+
+func TestText(t *testing.T) {
+	l, buf := newTestLogger()
+
+	l.Print("no level")
+	l.Info().WithFields(map[string]any{
+		"b": 2,
+		"a": "x y",
+		"":  "ignored",
+	}).Printf("hello %s", "world")
+	l.Warn().WithField("err", errors.New("failed")).Println("trimmed")
+
+	want := "no level\n" +
+		"[INFO] hello world a=\"x y\" b=\"2\"\n" +
+		"[WARN] trimmed err=\"failed\"\n"
+	if got := buf.String(); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestStack(t *testing.T) {
+	l, buf := newTestLogger()
+
+	l.Error().WithStack(0).Print("with stack")
+
+	first, stack, _ := strings.Cut(buf.String(), "\n")
+	if first != "[ERROR] with stack" {
+		t.Errorf("unexpected line %q", first)
+	}
+	if !strings.Contains(stack, "TestStack") {
+		t.Errorf("missing caller on stack:\n%s", stack)
+	}
+}
+
+func TestFatal(t *testing.T) {
+	l, buf := newTestLogger()
+
+	var code int
+	slog.SetExitFunc(func(c int) { code = c })
+	t.Cleanup(func() { slog.SetExitFunc(nil) })
+
+	l.Fatal().Print("fatal")
+
+	if code != 1 || buf.String() != "[FATAL] fatal\n" {
+		t.Errorf("unexpected exit code %d and output %q", code, buf)
+	}
+}
+
+func TestPanic(t *testing.T) {
+	l, buf := newTestLogger()
+
+	defer func() {
+		err, ok := recover().(*core.PanicError)
+		if !ok || fmt.Sprint(err.Recovered()) != "panic" {
+			t.Errorf("unexpected panic %v", err)
+		}
+		if buf.String() != "[PANIC] panic\n" {
+			t.Errorf("unexpected output %q", buf)
+		}
+	}()
+
+	l.Panic().Print("panic")
+}
+
+func TestProperties(t *testing.T) {
+	slogtest.CheckProperties(t, func(slog.Logger) slog.Logger {
+		l, _ := newTestLogger()
+		return l
+	})
+}
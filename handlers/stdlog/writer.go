@@ -0,0 +1,102 @@
+package stdlog
+
+import (
+	"log"
+	"strings"
+
+	"darvaza.org/slog"
+)
+
+// Option configures the *log.Logger created by NewStdLogger
+type Option func(*writer)
+
+// WithPrefixField extracts a leading "prefix: " from each line,
+// as commonly used by legacy code to identify components, and
+// attaches it as a field of the given name instead.
+func WithPrefixField(name string) Option {
+	return func(w *writer) {
+		w.prefixField = name
+	}
+}
+
+// WithPrefix attaches the given field to all entries
+// whose line starts with the given prefix, removing it
+// from the message.
+func WithPrefix(prefix, field string, value any) Option {
+	return func(w *writer) {
+		w.prefixes = append(w.prefixes, prefixRule{
+			prefix: prefix,
+			field:  field,
+			value:  value,
+		})
+	}
+}
+
+type prefixRule struct {
+	value  any
+	prefix string
+	field  string
+}
+
+type writer struct {
+	prefixField string
+	prefixes    []prefixRule
+}
+
+// NewStdLogger creates a standard *log.Logger that adds an entry
+// to the given slog.Logger, on the given level, for each line
+// written to it.
+func NewStdLogger(l slog.Logger, level slog.LogLevel, opts ...Option) *log.Logger {
+	if l == nil {
+		return nil
+	}
+	if level <= slog.UndefinedLevel {
+		level = slog.Info
+	}
+
+	w := &writer{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(w)
+		}
+	}
+
+	lw := slog.NewLogWriter(l.WithLevel(level), w.LogWrite)
+	return log.New(lw, "", 0)
+}
+
+// LogWrite splits the written text into lines and logs them
+func (w *writer) LogWrite(l slog.Logger, s string) error {
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if line != "" {
+			w.logLine(l, line)
+		}
+	}
+	return nil
+}
+
+func (w *writer) logLine(l slog.Logger, line string) {
+	for _, p := range w.prefixes {
+		if rest, ok := strings.CutPrefix(line, p.prefix); ok {
+			l = l.WithField(p.field, p.value)
+			line = strings.TrimLeft(rest, " ")
+			break
+		}
+	}
+
+	if w.prefixField != "" {
+		if prefix, rest, ok := strings.Cut(line, ": "); ok && isPrefix(prefix) {
+			l = l.WithField(w.prefixField, prefix)
+			line = rest
+		}
+	}
+
+	l.Print(line)
+}
+
+// isPrefix tells if a string looks like a component prefix
+func isPrefix(s string) bool {
+	s = strings.Trim(s, "[]")
+	return s != "" && !strings.ContainsAny(s, " \t")
+}
@@ -0,0 +1,40 @@
+package stdlog
+
+import (
+	"testing"
+
+	"darvaza.org/slog"
+	slogtest "darvaza.org/slog/internal/testing"
+)
+
+func TestStdLogger(t *testing.T) {
+	rec := slogtest.NewRecorder()
+	std := NewStdLogger(rec, slog.UndefinedLevel,
+		WithPrefix("[db]", "component", "database"),
+		WithPrefixField("module"))
+
+	std.Print("first\r\n\nsecond")
+	std.Print("[db] connected")
+	std.Print("http: listening")
+	std.Print("not a prefix: kept")
+
+	slogtest.AssertMessagesInOrder(t, rec.Messages(),
+		slogtest.Expect().Level(slog.Info).Message("first"),
+		slogtest.Expect().Level(slog.Info).Message("second"),
+		slogtest.Expect().Message("connected").
+			Field("component", "database").NoField("module"),
+		slogtest.Expect().Message("listening").Field("module", "http"),
+		slogtest.Expect().Message("not a prefix: kept").NoField("module"))
+}
+
+func TestStdLoggerLevel(t *testing.T) {
+	rec := slogtest.NewRecorder()
+	NewStdLogger(rec, slog.Warn).Printf("%d", 42)
+
+	slogtest.AssertMessagesInOrder(t, rec.Messages(),
+		slogtest.Expect().Level(slog.Warn).Message("42"))
+
+	if NewStdLogger(nil, slog.Info) != nil {
+		t.Error("expected nil without slog.Logger")
+	}
+}
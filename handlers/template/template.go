@@ -0,0 +1,64 @@
+// Package template provides a slog.Logger that records the
+// format and arguments of Printf calls as fields, so entries
+// can be grouped by template
+package template
+
+import (
+	"darvaza.org/slog"
+	"darvaza.org/slog/internal"
+)
+
+const (
+	// DefaultTemplateField is the default field label
+	// for the format given to Printf
+	DefaultTemplateField = "msg_template"
+	// DefaultParamsField is the default field label
+	// for the arguments given to Printf
+	DefaultParamsField = "params"
+)
+
+// Options customise the field labels. Empty labels
+// use the defaults.
+type Options struct {
+	TemplateField string
+	ParamsField   string
+}
+
+// New creates a slog.Logger that, for entries added using Printf,
+// attaches the format and a copy of the arguments as fields before
+// passing the rendered message to the parent.
+// Entries added using Print or Println are passed as they are.
+func New(parent slog.Logger, opts *Options) slog.Logger {
+	if parent == nil {
+		return nil
+	}
+
+	var o Options
+	if opts != nil {
+		o = *opts
+	}
+	if o.TemplateField == "" {
+		o.TemplateField = DefaultTemplateField
+	}
+	if o.ParamsField == "" {
+		o.ParamsField = DefaultParamsField
+	}
+
+	return internal.NewWrapper(parent, &internal.WrapperHooks{
+		Print: o.print,
+	})
+}
+
+func (o Options) print(e *internal.Entry) bool {
+	if e.Format != "" {
+		fields := map[string]any{
+			o.TemplateField: e.Format,
+		}
+		if len(e.Args) > 0 {
+			// the caller may reuse the slice
+			fields[o.ParamsField] = append([]any(nil), e.Args...)
+		}
+		e.Logger = e.Logger.WithFields(fields)
+	}
+	return true
+}
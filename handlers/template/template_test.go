@@ -0,0 +1,53 @@
+package template
+
+import (
+	"testing"
+
+	"darvaza.org/slog"
+	slogtest "darvaza.org/slog/internal/testing"
+)
+
+func TestPrintf(t *testing.T) {
+	rec := slogtest.NewRecorder()
+	l := New(rec, nil)
+
+	args := []any{"alice", 3}
+	l.Info().Printf("user %s failed %d times", args...)
+	// the caller reusing the slice doesn't alter the entry
+	args[0] = "bob"
+
+	l.Info().Printf("no arguments")
+	l.Info().Print("plain")
+	l.Info().Println("plain", "line")
+
+	slogtest.AssertMessagesInOrder(t, rec.Messages(),
+		slogtest.Expect().Message("user alice failed 3 times").
+			Field(DefaultTemplateField, "user %s failed %d times").
+			Field(DefaultParamsField, []any{"alice", 3}),
+		slogtest.Expect().Message("no arguments").
+			Field(DefaultTemplateField, "no arguments").
+			NoField(DefaultParamsField),
+		slogtest.Expect().Message("plain").
+			NoField(DefaultTemplateField),
+		slogtest.Expect().Message("plain line").
+			NoField(DefaultTemplateField))
+}
+
+func TestOptions(t *testing.T) {
+	rec := slogtest.NewRecorder()
+	l := New(rec, &Options{TemplateField: "format", ParamsField: "args"})
+
+	l.WithField("key", "value").Warn().Printf("%d%%", 50)
+
+	slogtest.AssertMessagesInOrder(t, rec.Messages(),
+		slogtest.Expect().Level(slog.Warn).Message("50%").
+			Field("key", "value").
+			Field("format", "%d%%").
+			Field("args", []any{50}))
+}
+
+func TestProperties(t *testing.T) {
+	slogtest.CheckProperties(t, func(parent slog.Logger) slog.Logger {
+		return New(parent, nil)
+	})
+}
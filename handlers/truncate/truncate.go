@@ -0,0 +1,151 @@
+// Package truncate provides a slog.Logger wrapper limiting the size
+// of messages and fields before passing them to the backend
+package truncate
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"darvaza.org/core"
+	"darvaza.org/slog"
+	"darvaza.org/slog/internal"
+)
+
+// DefaultMarker is appended to truncated messages and values
+// unless the Config says otherwise
+const DefaultMarker = "…"
+
+// Config describes the limits applied to entries. Zero or
+// negative values mean no limit.
+type Config struct {
+	// Marker is appended to truncated messages and values,
+	// and counts towards their maximum length.
+	// Defaults to DefaultMarker
+	Marker string
+
+	// MaxMessageLength is the maximum length in bytes
+	// of the message
+	MaxMessageLength int
+	// MaxFields is the maximum number of fields attached to
+	// an entry. Fields beyond it are discarded, except those
+	// replacing the value of a field already attached.
+	MaxFields int
+	// MaxValueSize is the maximum length in bytes of string
+	// field values. Byte slices, errors and fmt.Stringers are
+	// replaced by a truncated string when exceeding it.
+	MaxValueSize int
+}
+
+// SetDefaults uses DefaultMarker if none is given
+func (cfg *Config) SetDefaults() {
+	if cfg.Marker == "" {
+		cfg.Marker = DefaultMarker
+	}
+}
+
+// New creates a slog.Logger that enforces the limits described
+// by the Config before passing entries to the parent
+func New(parent slog.Logger, cfg *Config) slog.Logger {
+	if parent == nil {
+		return nil
+	}
+
+	var c Config
+	if cfg != nil {
+		c = *cfg
+	}
+	c.SetDefaults()
+
+	hooks := new(internal.WrapperHooks)
+	if c.MaxMessageLength > 0 {
+		hooks.Print = c.truncateMessage
+	}
+	if c.MaxValueSize > 0 {
+		hooks.Field = c.truncateField
+	}
+	if c.MaxFields > 0 {
+		hooks.Fields = c.limitFields
+	}
+
+	return internal.NewWrapper(parent, hooks)
+}
+
+func (cfg *Config) truncateMessage(e *internal.Entry) bool {
+	e.Message = cfg.truncate(e.Message, cfg.MaxMessageLength)
+	return true
+}
+
+func (cfg *Config) truncateField(key string, value any) (string, any, bool) {
+	if lazy, ok := value.(slog.Lazy); ok {
+		return key, slog.LazyValue(func() any {
+			return cfg.truncateValue(lazy.Value())
+		}), true
+	}
+	return key, cfg.truncateValue(value), true
+}
+
+func (cfg *Config) truncateValue(value any) any {
+	var s string
+
+	switch v := value.(type) {
+	case string:
+		if len(v) <= cfg.MaxValueSize {
+			return v
+		}
+		s = v
+	case []byte:
+		if len(v) <= cfg.MaxValueSize {
+			return v
+		}
+		s = string(v)
+	case error:
+		s = v.Error()
+	case fmt.Stringer:
+		s = v.String()
+	default:
+		return value
+	}
+
+	if len(s) <= cfg.MaxValueSize {
+		return value
+	}
+	return cfg.truncate(s, cfg.MaxValueSize)
+}
+
+// limitFields discards new fields once MaxFields is reached
+func (cfg *Config) limitFields(ll *internal.Loglet, fields map[string]any) map[string]any {
+	n := ll.FieldsCount()
+	if n+len(fields) <= cfg.MaxFields {
+		return fields
+	}
+
+	out := make(map[string]any, len(fields))
+	for _, k := range core.SortedKeys(fields) {
+		if _, ok := ll.Field(k); ok {
+			out[k] = fields[k]
+		} else if n < cfg.MaxFields {
+			out[k] = fields[k]
+			n++
+		}
+	}
+	return out
+}
+
+// truncate shortens s to at most size bytes, marker included,
+// without splitting UTF-8 sequences
+func (cfg *Config) truncate(s string, size int) string {
+	if len(s) <= size {
+		return s
+	}
+
+	marker := cfg.Marker
+	n := size - len(marker)
+	if n <= 0 {
+		// no room for the marker
+		marker, n = "", size
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n] + marker
+}
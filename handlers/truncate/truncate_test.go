@@ -0,0 +1,108 @@
+package truncate
+
+import (
+	"errors"
+	"testing"
+
+	"darvaza.org/slog"
+	slogtest "darvaza.org/slog/internal/testing"
+)
+
+type stringer string
+
+func (s stringer) String() string { return string(s) }
+
+func TestMessage(t *testing.T) {
+	rec := slogtest.NewRecorder()
+	l := New(rec, &Config{MaxMessageLength: 8, Marker: "..."})
+
+	l.Info().Print("short")
+	l.Info().Print("a longer message")
+	// don't split the é
+	l.Info().Print("abcdé and more")
+
+	slogtest.AssertMessagesInOrder(t, rec.Messages(),
+		slogtest.Expect().Message("short"),
+		slogtest.Expect().Message("a lon..."),
+		slogtest.Expect().Message("abcd..."))
+}
+
+func TestMarkerTooLong(t *testing.T) {
+	cfg := &Config{Marker: "[truncated]"}
+	if s := cfg.truncate("abcdefgh", 4); s != "abcd" {
+		t.Errorf("expected \"abcd\", got %q", s)
+	}
+
+	cfg = &Config{}
+	cfg.SetDefaults()
+	if s := cfg.truncate("abcdefgh", 6); s != "abc"+DefaultMarker {
+		t.Errorf("expected %q, got %q", "abc"+DefaultMarker, s)
+	}
+}
+
+func TestValues(t *testing.T) {
+	rec := slogtest.NewRecorder()
+	l := New(rec, &Config{MaxValueSize: 4, Marker: "~"})
+
+	l.Info().WithFields(map[string]any{
+		"short":    "abc",
+		"string":   "abcdef",
+		"bytes":    []byte("abcdef"),
+		"error":    errors.New("failure"),
+		"stringer": stringer("abcdef"),
+		"number":   123456789,
+	}).WithField("lazy", slog.LazyValue(func() any {
+		return "abcdef"
+	})).Print("values")
+
+	msgs := rec.Messages()
+	slogtest.AssertMessagesInOrder(t, msgs,
+		slogtest.Expect().Message("values").
+			Field("short", "abc").
+			Field("string", "abc~").
+			Field("bytes", "abc~").
+			Field("error", "fai~").
+			Field("stringer", "abc~").
+			Field("number", 123456789))
+
+	if len(msgs) == 1 {
+		if v := slog.ResolveValue(msgs[0].Fields["lazy"]); v != "abc~" {
+			t.Errorf("expected lazy value \"abc~\", got %v", v)
+		}
+	}
+}
+
+func TestMaxFields(t *testing.T) {
+	rec := slogtest.NewRecorder()
+	l := New(rec, &Config{MaxFields: 2})
+
+	l = l.WithField("a", 1).WithFields(map[string]any{"b": 2, "c": 3})
+	// replacing a field already attached is allowed
+	l.WithField("a", 10).WithField("d", 4).Info().Print("limited")
+
+	slogtest.AssertMessagesInOrder(t, rec.Messages(),
+		slogtest.Expect().Message("limited").
+			Field("a", 10).Field("b", 2).
+			NoField("c").NoField("d"))
+}
+
+func TestNoLimits(t *testing.T) {
+	rec := slogtest.NewRecorder()
+	l := New(rec, nil)
+
+	long := "a very long message, not truncated by default"
+	l.Info().WithField("key", long).Print(long)
+
+	slogtest.AssertMessagesInOrder(t, rec.Messages(),
+		slogtest.Expect().Message(long).Field("key", long))
+}
+
+func TestProperties(t *testing.T) {
+	slogtest.CheckProperties(t, func(parent slog.Logger) slog.Logger {
+		return New(parent, &Config{
+			MaxMessageLength: 1024,
+			MaxFields:        64,
+			MaxValueSize:     1024,
+		})
+	})
+}
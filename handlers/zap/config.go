@@ -0,0 +1,128 @@
+package zap
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"darvaza.org/core"
+	"darvaza.org/slog"
+)
+
+// Encodings provided by zap
+const (
+	ConsoleEncoding = "console"
+	JSONEncoding    = "json"
+)
+
+// ConfigOption modifies the [zap.Config] created by NewDefaultConfig
+type ConfigOption func(*zap.Config)
+
+// WithEncoding sets the encoding, ConsoleEncoding by default,
+// JSONEncoding or any registered using zap.RegisterEncoder()
+func WithEncoding(encoding string) ConfigOption {
+	return func(cfg *zap.Config) {
+		cfg.Encoding = encoding
+	}
+}
+
+// WithSampling limits the entries logged per second with the same
+// level and message to the first ones, and then every thereafter-th.
+// Passing zero initial disables sampling.
+func WithSampling(initial, thereafter int) ConfigOption {
+	return func(cfg *zap.Config) {
+		if initial > 0 {
+			cfg.Sampling = &zap.SamplingConfig{
+				Initial:    initial,
+				Thereafter: thereafter,
+			}
+		} else {
+			cfg.Sampling = nil
+		}
+	}
+}
+
+// WithOutputPaths sets the URLs or file paths entries are written
+// to, "stderr" by default. With none, entries are discarded.
+func WithOutputPaths(paths ...string) ConfigOption {
+	return func(cfg *zap.Config) {
+		cfg.OutputPaths = paths
+	}
+}
+
+// WithErrorOutputPaths sets the URLs or file paths zap's own
+// errors are written to, "stderr" by default
+func WithErrorOutputPaths(paths ...string) ConfigOption {
+	return func(cfg *zap.Config) {
+		cfg.ErrorOutputPaths = paths
+	}
+}
+
+// WithInitialFields sets fields attached to every entry
+func WithInitialFields(fields slog.Fields) ConfigOption {
+	return func(cfg *zap.Config) {
+		if len(fields) == 0 {
+			cfg.InitialFields = nil
+			return
+		}
+
+		cfg.InitialFields = make(map[string]any, len(fields))
+		for k, v := range fields {
+			if k != "" {
+				cfg.InitialFields[k] = slog.ResolveValue(v)
+			}
+		}
+	}
+}
+
+// WithLevel sets the initial level, Info by default
+func WithLevel(level slog.LogLevel) ConfigOption {
+	return func(cfg *zap.Config) {
+		if zl, ok := zapLevel(level); ok {
+			cfg.Level = zap.NewAtomicLevelAt(zl)
+		}
+	}
+}
+
+// WithStacktrace enables zap's own automatic call stacks on
+// Error entries and above, disabled by default
+func WithStacktrace(enabled bool) ConfigOption {
+	return func(cfg *zap.Config) {
+		cfg.DisableStacktrace = !enabled
+	}
+}
+
+// Validate checks a [zap.Config] can be used by New. Empty
+// OutputPaths are accepted, zap discarding the entries.
+func Validate(cfg *zap.Config) error {
+	switch {
+	case cfg == nil:
+		return core.Wrap(core.ErrInvalid, "zap: config not specified")
+	case cfg.Level == (zap.AtomicLevel{}):
+		return core.Wrap(core.ErrInvalid, "zap: level not specified")
+	case cfg.Encoding == "":
+		return core.Wrap(core.ErrInvalid, "zap: encoding not specified")
+	case cfg.Sampling != nil && cfg.Sampling.Initial <= 0:
+		return core.Wrapf(core.ErrInvalid, "zap: invalid sampling initial %v", cfg.Sampling.Initial)
+	case cfg.Sampling != nil && cfg.Sampling.Thereafter < 0:
+		return core.Wrapf(core.ErrInvalid, "zap: invalid sampling thereafter %v", cfg.Sampling.Thereafter)
+	default:
+		return nil
+	}
+}
+
+func zapLevel(level slog.LogLevel) (zapcore.Level, bool) {
+	var levels = []zapcore.Level{
+		slog.UndefinedLevel: zapcore.InvalidLevel,
+		slog.Panic:          zapcore.PanicLevel,
+		slog.Fatal:          zapcore.FatalLevel,
+		slog.Error:          zapcore.ErrorLevel,
+		slog.Warn:           zapcore.WarnLevel,
+		slog.Info:           zapcore.InfoLevel,
+		slog.Debug:          zapcore.DebugLevel,
+	}
+
+	if level <= slog.UndefinedLevel || int(level) >= len(levels) {
+		return zapcore.InvalidLevel, false
+	}
+	return levels[level], true
+}
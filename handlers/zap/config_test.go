@@ -0,0 +1,122 @@
+package zap
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"darvaza.org/core"
+	"darvaza.org/slog"
+)
+
+func TestValidate(t *testing.T) {
+	for name, tc := range map[string]struct {
+		cfg *zap.Config
+		ok  bool
+	}{
+		"nil":     {nil, false},
+		"default": {NewDefaultConfig(), true},
+		"json":    {NewDefaultConfig(WithEncoding(JSONEncoding)), true},
+		"no level": {func() *zap.Config {
+			cfg := NewDefaultConfig()
+			cfg.Level = zap.AtomicLevel{}
+			return cfg
+		}(), false},
+		"no encoding":     {NewDefaultConfig(WithEncoding("")), false},
+		"no output paths": {NewDefaultConfig(WithOutputPaths()), true},
+		"sampling":        {NewDefaultConfig(WithSampling(10, 100)), true},
+		"no sampling":     {NewDefaultConfig(WithSampling(0, 100)), true},
+		"bad thereafter":  {NewDefaultConfig(WithSampling(10, -1)), false},
+		"bad initial": {func() *zap.Config {
+			cfg := NewDefaultConfig()
+			cfg.Sampling = &zap.SamplingConfig{Thereafter: 1}
+			return cfg
+		}(), false},
+	} {
+		err := Validate(tc.cfg)
+		switch {
+		case tc.ok && err != nil:
+			t.Errorf("%s: unexpected error: %v", name, err)
+		case !tc.ok && !errors.Is(err, core.ErrInvalid):
+			t.Errorf("%s: expected ErrInvalid, got %v", name, err)
+		}
+	}
+}
+
+func TestNewDefaultConfigOptions(t *testing.T) {
+	cfg := NewDefaultConfig(
+		WithEncoding(JSONEncoding),
+		WithLevel(slog.Debug),
+		WithStacktrace(true),
+		WithOutputPaths("stdout"),
+		WithErrorOutputPaths("stdout"),
+		WithInitialFields(slog.Fields{"service": "test", "": "ignored"}),
+		nil,
+	)
+
+	switch {
+	case cfg.Encoding != JSONEncoding:
+		t.Errorf("unexpected encoding %q", cfg.Encoding)
+	case cfg.Level.Level() != zapcore.DebugLevel:
+		t.Errorf("unexpected level %v", cfg.Level.Level())
+	case cfg.DisableStacktrace:
+		t.Error("stacktrace not enabled")
+	case len(cfg.OutputPaths) != 1 || cfg.OutputPaths[0] != "stdout":
+		t.Errorf("unexpected output paths %q", cfg.OutputPaths)
+	case len(cfg.ErrorOutputPaths) != 1 || cfg.ErrorOutputPaths[0] != "stdout":
+		t.Errorf("unexpected error output paths %q", cfg.ErrorOutputPaths)
+	case len(cfg.InitialFields) != 1 || cfg.InitialFields["service"] != "test":
+		t.Errorf("unexpected initial fields %v", cfg.InitialFields)
+	}
+}
+
+func TestBuildInvalid(t *testing.T) {
+	if _, err := Build(NewDefaultConfig(WithEncoding(""))); !errors.Is(err, core.ErrInvalid) {
+		t.Errorf("expected ErrInvalid, got %v", err)
+	}
+	if l := New(NewDefaultConfig(WithEncoding(""))); l != nil {
+		t.Error("New accepted an invalid config")
+	}
+}
+
+func TestNewWithoutOutputPaths(t *testing.T) {
+	l := New(NewDefaultConfig(WithOutputPaths()))
+	if l == nil {
+		t.Fatal("New rejected a config without output paths")
+	}
+	l.Info().Print("discarded")
+}
+
+func TestBuildOutput(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "out.log")
+
+	l, err := Build(NewDefaultConfig(
+		WithEncoding(JSONEncoding),
+		WithOutputPaths(out),
+		WithInitialFields(slog.Fields{"service": "test"}),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l.Info().Print("hello")
+	_ = l.logger.Sync()
+
+	b, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var entry map[string]any
+	if err := json.Unmarshal(b, &entry); err != nil {
+		t.Fatalf("expected a single JSON entry: %v: %s", err, b)
+	}
+	if entry["msg"] != "hello" || entry["service"] != "test" {
+		t.Errorf("unexpected entry: %v", entry)
+	}
+}
@@ -0,0 +1,72 @@
+package zap
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"darvaza.org/core"
+	"darvaza.org/slog"
+)
+
+// FieldsToZap converts a set of slog fields into zap.Fields,
+// sorted by key, using FieldToZap
+func FieldsToZap(fields map[string]any) []zap.Field {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	out := make([]zap.Field, 0, len(fields))
+	for _, k := range core.SortedKeys(fields) {
+		if k != "" {
+			out = append(out, FieldToZap(k, fields[k]))
+		}
+	}
+	return out
+}
+
+// FieldToZap converts a slog field into a typed zap.Field, after
+// resolving lazy values, falling back to zap.Any for types
+// without a specific constructor
+func FieldToZap(key string, value any) zap.Field {
+	switch v := slog.ResolveValue(value).(type) {
+	case nil:
+		// keep the key, encoded as null
+		return zap.Any(key, nil)
+	case string:
+		return zap.String(key, v)
+	case bool:
+		return zap.Bool(key, v)
+	case int:
+		return zap.Int(key, v)
+	case int64:
+		return zap.Int64(key, v)
+	case int32:
+		return zap.Int32(key, v)
+	case uint:
+		return zap.Uint(key, v)
+	case uint64:
+		return zap.Uint64(key, v)
+	case uint32:
+		return zap.Uint32(key, v)
+	case float64:
+		return zap.Float64(key, v)
+	case float32:
+		return zap.Float32(key, v)
+	case time.Duration:
+		return zap.Duration(key, v)
+	case time.Time:
+		return zap.Time(key, v)
+	case []byte:
+		return zap.ByteString(key, v)
+	case error:
+		return zap.NamedError(key, v)
+	case core.Frame:
+		return zap.String(key, slog.FormatCaller(v))
+	case fmt.Stringer:
+		return zap.Stringer(key, v)
+	default:
+		return zap.Any(key, v)
+	}
+}
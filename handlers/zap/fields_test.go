@@ -0,0 +1,31 @@
+package zap
+
+import (
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestFieldToZapNil(t *testing.T) {
+	enc := zapcore.NewMapObjectEncoder()
+	FieldToZap("key", nil).AddTo(enc)
+
+	v, ok := enc.Fields["key"]
+	switch {
+	case !ok:
+		t.Error("key dropped")
+	case v != nil:
+		t.Errorf("expected nil, got %#v", v)
+	}
+}
+
+func TestFieldsToZapKeepsNil(t *testing.T) {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range FieldsToZap(map[string]any{"a": nil, "b": "x"}) {
+		f.AddTo(enc)
+	}
+
+	if len(enc.Fields) != 2 {
+		t.Errorf("expected 2 fields, got %v", enc.Fields)
+	}
+}
@@ -8,18 +8,19 @@ import (
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 
-	"darvaza.org/core"
 	"darvaza.org/slog"
 )
 
 var (
-	_ slog.Logger = (*Logger)(nil)
+	_ slog.Logger      = (*Logger)(nil)
+	_ slog.NamedLogger = (*Logger)(nil)
 )
 
 // Logger is an adaptor using go.uber.org/zap as slog.Logger
 type Logger struct {
 	logger *zap.Logger
 	config *zap.Config
+	keys   slog.KeyMapper
 }
 
 // Unwrap returns the underlying zap logger
@@ -104,21 +105,12 @@ func (zpl *Logger) Panic() slog.Logger {
 
 // WithLevel returns a new logger set to add entries to the specified level
 func (zpl *Logger) WithLevel(level slog.LogLevel) slog.Logger {
-	var levels = []zapcore.Level{
-		slog.UndefinedLevel: zapcore.InvalidLevel,
-		slog.Panic:          zapcore.PanicLevel,
-		slog.Fatal:          zapcore.FatalLevel,
-		slog.Error:          zapcore.ErrorLevel,
-		slog.Warn:           zapcore.WarnLevel,
-		slog.Info:           zapcore.InfoLevel,
-		slog.Debug:          zapcore.DebugLevel,
-	}
-
-	if level <= slog.UndefinedLevel || int(level) >= len(levels) {
+	zl, ok := zapLevel(level)
+	if !ok {
 		// fix your code
 		zpl.Panic().WithStack(1).Printf("slog: invalid log level %v", level)
-	} else if zpl.logger.Core().Enabled(levels[level]) {
-		zpl.config.Level.SetLevel(levels[level])
+	} else if zpl.logger.Core().Enabled(zl) {
+		zpl.config.Level.SetLevel(zl)
 	}
 
 	return zpl
@@ -132,30 +124,83 @@ func (zpl *Logger) WithStack(skip int) slog.Logger {
 	return zpl
 }
 
+// WithName appends a component to the name of the logger,
+// using zap's own dot-separated naming
+func (zpl *Logger) WithName(name string) slog.Logger {
+	if zpl.Enabled() && name != "" {
+		zpl.logger = zpl.logger.Named(name)
+	}
+	return zpl
+}
+
 // WithField returns a new logger with a field attached
 func (zpl *Logger) WithField(label string, value any) slog.Logger {
 	if zpl.Enabled() && label != "" {
-		zpl.logger = zpl.logger.With(zap.Any(label, value))
+		zpl.logger = zpl.logger.With(FieldToZap(zpl.keys.Map(label), value))
 	}
 	return zpl
 }
 
 // WithFields returns a new logger with a set of fields attached
 func (zpl *Logger) WithFields(fields map[string]any) slog.Logger {
-	if zpl.Enabled() {
-		zs := make([]zap.Field, len(fields))
-		for _, k := range core.SortedKeys(fields) {
-			zs = append(zs, zap.Any(k, fields[k]))
-		}
-		zpl.logger = zpl.logger.With(zs...)
+	if zpl.Enabled() && len(fields) > 0 {
+		zpl.logger = zpl.logger.With(FieldsToZap(zpl.mapKeys(fields))...)
+	}
+	return zpl
+}
+
+// WithKeyMapper renames the keys of the fields attached afterwards
+// using the given slog.KeyMapper
+func (zpl *Logger) WithKeyMapper(m slog.KeyMapper) *Logger {
+	if zpl != nil {
+		zpl.keys = m
 	}
 	return zpl
 }
 
+func (zpl *Logger) mapKeys(fields map[string]any) map[string]any {
+	if zpl.keys == nil {
+		return fields
+	}
+
+	out := make(map[string]any, len(fields))
+	for k, v := range fields {
+		if k != "" {
+			out[zpl.keys(k)] = v
+		}
+	}
+	return out
+}
+
 // New creates a slog.Logger adaptor using a zap as backend. If
 // none was passed it will create an opiniated new one.
+// It returns nil if the config is invalid, see Build().
 func New(cfg *zap.Config) slog.Logger {
-	return newLogger(cfg)
+	if l, err := Build(cfg); err == nil {
+		return l
+	}
+	return nil
+}
+
+// Build validates a [zap.Config], or NewDefaultConfig() if none
+// was passed, and creates a Logger using it
+func Build(cfg *zap.Config) (*Logger, error) {
+	if cfg == nil {
+		cfg = NewDefaultConfig()
+	}
+	if err := Validate(cfg); err != nil {
+		return nil, err
+	}
+
+	lg, err := cfg.Build(zap.WithFatalHook(fatalHook{}))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Logger{
+		logger: lg,
+		config: cfg,
+	}, nil
 }
 
 // NewWithCallback creates a new zap logger using a callback to modify it.
@@ -176,25 +221,17 @@ func NewNoop() *Logger {
 	}
 }
 
-func newLogger(cfg *zap.Config) *Logger {
-	if cfg == nil {
-		cfg = NewDefaultConfig()
-	}
-
-	lg, err := cfg.Build()
-	if err != nil {
-		return nil
-	}
+// fatalHook terminates the program using slog.Exit()
+// after a Fatal entry is written
+type fatalHook struct{}
 
-	return &Logger{
-		logger: lg,
-		config: cfg,
-	}
+func (fatalHook) OnWrite(*zapcore.CheckedEntry, []zapcore.Field) {
+	slog.Exit(1)
 }
 
 // NewDefaultConfig creates a new [zap.Config] logging to the
-// console.
-func NewDefaultConfig() *zap.Config {
+// console, modified by the given options.
+func NewDefaultConfig(opts ...ConfigOption) *zap.Config {
 	cfg := zap.NewProductionConfig()
 	encoderConfig := zap.NewProductionEncoderConfig()
 	encoderConfig.EncodeTime = zapcore.RFC3339TimeEncoder
@@ -204,5 +241,11 @@ func NewDefaultConfig() *zap.Config {
 	cfg.Level = zap.NewAtomicLevelAt(zapcore.InfoLevel)
 	cfg.DisableStacktrace = true
 	cfg.DisableCaller = true
+
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
 	return &cfg
 }
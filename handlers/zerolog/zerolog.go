@@ -3,7 +3,6 @@ package zerolog
 
 import (
 	"fmt"
-	"os"
 	"strings"
 
 	"github.com/rs/zerolog"
@@ -13,7 +12,8 @@ import (
 )
 
 var (
-	_ slog.Logger = (*Logger)(nil)
+	_ slog.Logger      = (*Logger)(nil)
+	_ slog.NamedLogger = (*Logger)(nil)
 )
 
 // Logger is an adaptor for using github.com/rs/zerolog as slog.Logger.
@@ -22,6 +22,7 @@ type Logger struct {
 	event  *zerolog.Event
 	action func(string, error)
 	err    error
+	name   string
 }
 
 // Enabled tells if the underlying logger is enabled or not.
@@ -134,9 +135,7 @@ func (zl *Logger) WithLevel(level slog.LogLevel) slog.Logger {
 }
 
 func (*Logger) triggerExit(string, error) {
-	// revive:disable:deep-exit
-	os.Exit(1)
-	// revive:enable:deep-exit
+	slog.Exit(1)
 }
 
 func (*Logger) triggerPanic(msg string, err error) {
@@ -161,6 +160,16 @@ func (zl *Logger) WithStack(skip int) slog.Logger {
 	return zl
 }
 
+// WithName appends a component to the dot-separated name of the
+// Event Context, stored as slog.NameFieldName field
+func (zl *Logger) WithName(name string) slog.Logger {
+	if zl.Enabled() && name != "" {
+		zl.name = slog.JoinName(zl.name, name)
+		zl.event.Str(slog.NameFieldName, zl.name)
+	}
+	return zl
+}
+
 // WithField adds a field to the Event Context
 func (zl *Logger) WithField(label string, value any) slog.Logger {
 	if zl.Enabled() && label != "" {
@@ -181,6 +190,8 @@ func (zl *Logger) WithFields(fields map[string]any) slog.Logger {
 }
 
 func (zl *Logger) addField(label string, value any) {
+	value = slog.ResolveValue(value)
+
 	if label == slog.ErrorFieldName {
 		if err, ok := value.(error); ok {
 			zl.event.Err(err)
@@ -0,0 +1,67 @@
+package slog
+
+import "errors"
+
+// Names of the counters commonly reported by handlers
+const (
+	// DroppedStat counts entries discarded
+	DroppedStat = "dropped"
+	// PendingStat counts entries waiting to be delivered
+	PendingStat = "pending"
+)
+
+// Stats are counters reported by handlers, like the number of
+// entries dropped or pending
+type Stats map[string]uint64
+
+// HealthChecker is implemented by handlers with background
+// workers that can tell if they are working as expected
+type HealthChecker interface {
+	// Health returns nil when healthy, or the reason otherwise
+	Health() error
+}
+
+// StatsReporter is implemented by handlers keeping counters
+type StatsReporter interface {
+	Stats() Stats
+}
+
+// Health walks a chain of loggers like Close() does, joining the
+// errors reported by the stages implementing HealthChecker.
+// It returns nil if all of them are healthy.
+func Health(l Logger) error {
+	var errs []error
+
+	_ = walkChain(l, func(l Logger) error {
+		if h, ok := l.(HealthChecker); ok {
+			if err := h.Health(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return nil
+	})
+
+	return errors.Join(errs...)
+}
+
+// CollectStats walks a chain of loggers like Close() does, adding
+// up the counters reported by the stages implementing StatsReporter
+func CollectStats(l Logger) Stats {
+	out := make(Stats)
+
+	_ = walkChain(l, func(l Logger) error {
+		if r, ok := l.(StatsReporter); ok {
+			out.Add(r.Stats())
+		}
+		return nil
+	})
+
+	return out
+}
+
+// Add adds the counters of another Stats
+func (s Stats) Add(other Stats) {
+	for k, v := range other {
+		s[k] += v
+	}
+}
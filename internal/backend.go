@@ -0,0 +1,135 @@
+package internal
+
+import (
+	"context"
+	"time"
+
+	"darvaza.org/slog"
+)
+
+// Backend is the scaffolding shared by handlers delivering the
+// entries of a channel based logger from their own goroutine,
+// e.g. to a remote service
+type Backend struct {
+	ctx      context.Context
+	cancel   context.CancelFunc
+	done     chan struct{}
+	flushes  chan chan struct{}
+	shutdown func(context.Context) error
+	onError  func(error)
+	lastErr  LastError
+	name     string
+}
+
+// Init prepares the Backend. Errors are passed to onError
+// or, if nil, to slog.ReportError() using the given name.
+func (b *Backend) Init(name string, onError func(error)) {
+	b.name = name
+	b.onError = onError
+	b.done = make(chan struct{})
+	b.flushes = make(chan chan struct{})
+	b.ctx, b.cancel = context.WithCancel(context.Background())
+}
+
+// Start calls run on a new goroutine. run is expected to deliver
+// entries until the channel is closed by shutdown, and to serve
+// the requests received from Flushes().
+func (b *Backend) Start(shutdown func(context.Context) error, run func()) {
+	b.shutdown = shutdown
+
+	go func() {
+		defer close(b.done)
+		defer b.cancel()
+
+		run()
+	}()
+}
+
+// Context returns a context cancelled when the Backend is aborted
+// or its goroutine finishes, to be used by deliveries
+func (b *Backend) Context() context.Context {
+	return b.ctx
+}
+
+// Abort cancels the deliveries in flight
+func (b *Backend) Abort() {
+	b.cancel()
+}
+
+// Flushes returns the channel used to request the goroutine to
+// deliver what it holds. The received channel must be closed
+// once done.
+func (b *Backend) Flushes() <-chan chan struct{} {
+	return b.flushes
+}
+
+// Flush asks the goroutine to deliver what it holds and waits
+// until it's done, or the context is cancelled
+func (b *Backend) Flush(ctx context.Context) error {
+	req := make(chan struct{})
+
+	select {
+	case b.flushes <- req:
+	case <-b.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case <-req:
+		return nil
+	case <-b.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close calls Shutdown, waiting up to the given time
+func (b *Backend) Close(timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	return b.Shutdown(ctx)
+}
+
+// Shutdown stops the logger feeding the goroutine and waits for
+// it to finish. If the context is cancelled first, the deliveries
+// in flight are aborted.
+func (b *Backend) Shutdown(ctx context.Context) error {
+	err := b.shutdown(ctx)
+	if err == nil {
+		select {
+		case <-b.done:
+			return nil
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+	}
+
+	b.cancel()
+	return err
+}
+
+// ReportError records an error, to be returned by Err(),
+// and passes it on
+func (b *Backend) ReportError(err error) {
+	b.lastErr.Set(err)
+
+	if fn := b.onError; fn != nil {
+		fn(err)
+	} else {
+		slog.ReportError(b.name, err)
+	}
+}
+
+// ClearError forgets the last error, after a successful delivery
+func (b *Backend) ClearError() {
+	b.lastErr.Set(nil)
+}
+
+// Err returns the last error, until ClearError() is called
+func (b *Backend) Err() error {
+	return b.lastErr.Err()
+}
@@ -0,0 +1,33 @@
+package internal
+
+import "time"
+
+var (
+	_ Clock = SystemClock
+)
+
+// Clock is the source of time used by asynchronous handlers,
+// allowing time dependent behaviour to be tested deterministically
+type Clock interface {
+	// Now returns the current time
+	Now() time.Time
+	// After returns a channel that receives the current time
+	// once the given duration has elapsed
+	After(d time.Duration) <-chan time.Time
+}
+
+// SystemClock is the Clock backed by the time package
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time                         { return time.Now() }
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// ClockOrSystem returns the given Clock, or SystemClock if nil
+func ClockOrSystem(c Clock) Clock {
+	if c == nil {
+		return SystemClock
+	}
+	return c
+}
@@ -0,0 +1,75 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"darvaza.org/core"
+	"darvaza.org/slog"
+)
+
+// EncodeValue prepares a field value for encoders. Basic types and
+// time.Time are returned as they are, core.Frame values are formatted
+// as callers, and errors and fmt.Stringers are replaced by their text.
+// For everything else it returns false, and it's up to the encoder to
+// find out if it can handle the value.
+func EncodeValue(v any) (any, bool) {
+	switch x := v.(type) {
+	case nil, string, bool, time.Time,
+		int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64,
+		float32, float64:
+		return v, true
+	case core.Frame:
+		return slog.FormatCaller(x), true
+	case error:
+		return x.Error(), true
+	case fmt.Stringer:
+		return x.String(), true
+	default:
+		return v, false
+	}
+}
+
+// JSONValue prepares a field value to be encoded as JSON. Values
+// not handled by EncodeValue are marshalled once, and returned as
+// json.RawMessage, or replaced by their fmt representation if they
+// can't be.
+func JSONValue(v any) any {
+	if m, ok := v.(json.Marshaler); ok {
+		return m
+	}
+	if out, ok := EncodeValue(v); ok {
+		return out
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return json.RawMessage(b)
+}
+
+// JSONFields applies JSONValue to all the given fields,
+// returning a new map with room for extra entries
+func JSONFields(fields map[string]any, extra int) map[string]any {
+	out := make(map[string]any, len(fields)+extra)
+	for k, v := range fields {
+		out[k] = JSONValue(v)
+	}
+	return out
+}
+
+// TextValue returns the text representation of a field value,
+// e.g. to be used as label or key
+func TextValue(v any) string {
+	if out, ok := EncodeValue(v); ok {
+		v = out
+	}
+
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return fmt.Sprint(v)
+}
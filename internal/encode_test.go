@@ -0,0 +1,69 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+type stringer struct{}
+
+func (stringer) String() string { return "stringer" }
+
+func TestJSONValue(t *testing.T) {
+	now := time.Now()
+
+	for _, tc := range []struct {
+		in       any
+		expected string
+	}{
+		{nil, `null`},
+		{"text", `"text"`},
+		{42, `42`},
+		{now, mustJSON(t, now)},
+		{errors.New("failed"), `"failed"`},
+		{stringer{}, `"stringer"`},
+		{map[string]int{"a": 1}, `{"a":1}`},
+	} {
+		if got := mustJSON(t, JSONValue(tc.in)); got != tc.expected {
+			t.Errorf("%T: expected %s, got %s", tc.in, tc.expected, got)
+		}
+	}
+}
+
+func TestJSONValueUnsupported(t *testing.T) {
+	if _, ok := JSONValue(func() {}).(string); !ok {
+		t.Error("expected functions to be replaced by their fmt representation")
+	}
+	if _, ok := JSONValue([]int{1}).(json.RawMessage); !ok {
+		t.Error("expected composite values to be marshalled once")
+	}
+}
+
+func TestTextValue(t *testing.T) {
+	for _, tc := range []struct {
+		in       any
+		expected string
+	}{
+		{"text", "text"},
+		{42, "42"},
+		{errors.New("failed"), "failed"},
+		{stringer{}, "stringer"},
+		{[]int{1, 2}, "[1 2]"},
+	} {
+		if got := TextValue(tc.in); got != tc.expected {
+			t.Errorf("%T: expected %q, got %q", tc.in, tc.expected, got)
+		}
+	}
+}
+
+func mustJSON(t *testing.T, v any) string {
+	t.Helper()
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
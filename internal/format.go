@@ -0,0 +1,183 @@
+package internal
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"unicode/utf8"
+)
+
+// MaxCachedFormats is the maximum number of Printf formats
+// kept parsed when the cache is enabled
+const MaxCachedFormats = 1024
+
+// formatCache holds parsed Printf formats
+type formatCache struct {
+	m  map[string]*template
+	mu sync.RWMutex
+}
+
+var formats = formatCache{
+	m: make(map[string]*template),
+}
+
+func (c *formatCache) get(format string) *template {
+	c.mu.RLock()
+	t, ok := c.m[format]
+	c.mu.RUnlock()
+
+	if ok {
+		return t
+	}
+
+	t = parseTemplate(format)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.m) < MaxCachedFormats {
+		c.m[format] = t
+	}
+	return t
+}
+
+// segment is a piece of a parsed format, either
+// literal text or a single verb
+type segment struct {
+	s    string
+	verb bool
+}
+
+// template is a parsed Printf format. A nil template
+// means the format uses features it doesn't support.
+type template struct {
+	segments []segment
+	args     int
+}
+
+// parseTemplate splits a format into text and verbs consuming
+// one argument each, or returns nil if it uses argument
+// indexes or star widths.
+func parseTemplate(format string) *template {
+	t := &template{}
+	text := make([]byte, 0, len(format))
+
+	for i := 0; i < len(format); {
+		c := format[i]
+		if c != '%' {
+			text = append(text, c)
+			i++
+			continue
+		}
+
+		if i+1 < len(format) && format[i+1] == '%' {
+			text = append(text, '%')
+			i += 2
+			continue
+		}
+
+		n := verbLen(format[i:])
+		if n == 0 {
+			return nil
+		}
+
+		if len(text) > 0 {
+			t.segments = append(t.segments, segment{s: string(text)})
+			text = text[:0]
+		}
+		t.segments = append(t.segments, segment{s: format[i : i+n], verb: true})
+		t.args++
+		i += n
+	}
+
+	if len(text) > 0 {
+		t.segments = append(t.segments, segment{s: string(text)})
+	}
+	return t
+}
+
+// verbLen returns the length of the verb at the beginning of s,
+// or zero if it's incomplete or uses unsupported features
+func verbLen(s string) int {
+	i := 1
+	for i < len(s) {
+		switch c := s[i]; {
+		case c == '*' || c == '[':
+			return 0
+		case c == '+' || c == '-' || c == '#' || c == ' ' || c == '.' ||
+			('0' <= c && c <= '9'):
+			i++
+		default:
+			_, size := utf8.DecodeRuneInString(s[i:])
+			return i + size
+		}
+	}
+	return 0
+}
+
+// Sprintf formats according to a format specifier like fmt.Sprintf,
+// reusing the parsed format when the cache is enabled using the
+// slog_fmtcache build tag.
+func Sprintf(format string, args ...any) string {
+	if !formatCacheEnabled {
+		return fmt.Sprintf(format, args...)
+	}
+
+	t := formats.get(format)
+	if t == nil || t.args != len(args) {
+		// unsupported or wrong arguments, let fmt handle it
+		return fmt.Sprintf(format, args...)
+	}
+
+	return t.format(args)
+}
+
+func (t *template) format(args []any) string {
+	buf := make([]byte, 0, 64)
+
+	i := 0
+	for _, seg := range t.segments {
+		if !seg.verb {
+			buf = append(buf, seg.s...)
+			continue
+		}
+
+		buf = appendVerb(buf, seg.s, args[i])
+		i++
+	}
+	return string(buf)
+}
+
+// appendVerb formats one argument, skipping fmt when the verb
+// and the type of the argument are one of the most common cases,
+// and falling back to fmt for everything else
+func appendVerb(buf []byte, verb string, arg any) []byte {
+	switch verb {
+	case "%v":
+		switch v := arg.(type) {
+		case string:
+			return append(buf, v...)
+		case int:
+			return strconv.AppendInt(buf, int64(v), 10)
+		}
+	case "%s":
+		if v, ok := arg.(string); ok {
+			return append(buf, v...)
+		}
+	case "%d":
+		switch v := arg.(type) {
+		case int:
+			return strconv.AppendInt(buf, int64(v), 10)
+		case int64:
+			return strconv.AppendInt(buf, v, 10)
+		case uint64:
+			return strconv.AppendUint(buf, v, 10)
+		}
+	case "%q":
+		if v, ok := arg.(string); ok {
+			return strconv.AppendQuote(buf, v)
+		}
+	}
+
+	return fmt.Appendf(buf, verb, arg)
+}
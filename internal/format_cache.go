@@ -0,0 +1,6 @@
+//go:build slog_fmtcache
+
+package internal
+
+// formatCacheEnabled tells if Sprintf caches parsed formats
+const formatCacheEnabled = true
@@ -0,0 +1,79 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// nilError panics when Error() is called on a nil pointer
+type nilError struct {
+	msg string
+}
+
+func (e *nilError) Error() string { return e.msg }
+
+var formatTests = []struct {
+	format string
+	args   []any
+}{
+	{"plain", nil},
+	{"%s", []any{"string"}},
+	{"%s", []any{5}},
+	{"%v", []any{5}},
+	{"%d", []any{-5}},
+	{"%d", []any{int64(-5)}},
+	{"%d", []any{uint64(5)}},
+	{"%d", []any{"5"}},
+	{"%q", []any{"quoted"}},
+	{"%q", []any{5}},
+	{"%v", []any{errors.New("failed")}},
+	{"%s", []any{errors.New("failed")}},
+	{"%v", []any{(*nilError)(nil)}},
+	{"%s", []any{error((*nilError)(nil))}},
+	{"%v %s: %d%%", []any{"a", "b", 1}},
+	{"%5s|%-5d|%x", []any{"a", 1, 255}},
+}
+
+func TestTemplateFormat(t *testing.T) {
+	for _, tc := range formatTests {
+		tpl := parseTemplate(tc.format)
+		if tpl == nil || tpl.args != len(tc.args) {
+			t.Fatalf("%q: not parsed", tc.format)
+		}
+
+		expected := fmt.Sprintf(tc.format, tc.args...)
+		if got := tpl.format(tc.args); got != expected {
+			t.Errorf("%q %v: expected %q, got %q", tc.format, tc.args, expected, got)
+		}
+	}
+}
+
+func TestSprintf(t *testing.T) {
+	for _, tc := range formatTests {
+		expected := fmt.Sprintf(tc.format, tc.args...)
+		if got := Sprintf(tc.format, tc.args...); got != expected {
+			t.Errorf("%q %v: expected %q, got %q", tc.format, tc.args, expected, got)
+		}
+	}
+}
+
+const benchFormat = "request %s from %s took %d ms: %v"
+
+var benchArgs = []any{"GET /", "127.0.0.1", 42, "ok"}
+
+func BenchmarkSprintf(b *testing.B) {
+	b.Run("fmt", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = fmt.Sprintf(benchFormat, benchArgs...)
+		}
+	})
+
+	b.Run("cache", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = formats.get(benchFormat).format(benchArgs)
+		}
+	})
+}
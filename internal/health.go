@@ -0,0 +1,28 @@
+package internal
+
+import "sync/atomic"
+
+// LastError records the most recent failure of a background
+// worker, until it succeeds again
+type LastError struct {
+	p atomic.Pointer[error]
+}
+
+// Set records an error, or clears it if nil
+func (e *LastError) Set(err error) {
+	if err == nil {
+		if e.p.Load() != nil {
+			e.p.Store(nil)
+		}
+		return
+	}
+	e.p.Store(&err)
+}
+
+// Err returns the recorded error, if any
+func (e *LastError) Err() error {
+	if p := e.p.Load(); p != nil {
+		return *p
+	}
+	return nil
+}
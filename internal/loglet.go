@@ -1,21 +1,32 @@
 package internal
 
 import (
+	"context"
+	"time"
+
 	"darvaza.org/core"
 	"darvaza.org/slog"
 )
 
+const (
+	// DefaultMaxDepth is the length of the parent chain above which
+	// Compact flattens a Loglet
+	DefaultMaxDepth = 32
+)
+
 var (
 	_ core.CallStacker = (*Loglet)(nil)
 )
 
 // Loglet represents a link on the Logger context chain
 type Loglet struct {
+	ctx    context.Context
+	time   time.Time
 	parent *Loglet
-	level  slog.LogLevel
 	keys   []string
 	values []any
 	stack  core.Stack
+	level  slog.LogLevel
 }
 
 // Level returns the LogLevel of a Loglet
@@ -33,6 +44,42 @@ func (ll *Loglet) WithLevel(level slog.LogLevel) Loglet {
 		parent: ll,
 		level:  level,
 		stack:  ll.stack,
+		time:   ll.time,
+		ctx:    ll.ctx,
+	}
+}
+
+// Time returns the time of the event, if recorded
+func (ll *Loglet) Time() time.Time {
+	return ll.time
+}
+
+// WithTime records the time of the event on a new Loglet
+func (ll *Loglet) WithTime(t time.Time) Loglet {
+	return Loglet{
+		parent: ll,
+		level:  ll.level,
+		stack:  ll.stack,
+		time:   t,
+		ctx:    ll.ctx,
+	}
+}
+
+// Context returns the context the entry is logged on behalf of,
+// if given
+func (ll *Loglet) Context() context.Context {
+	return ll.ctx
+}
+
+// WithContext records the context the entry is logged on behalf
+// of on a new Loglet
+func (ll *Loglet) WithContext(ctx context.Context) Loglet {
+	return Loglet{
+		parent: ll,
+		level:  ll.level,
+		stack:  ll.stack,
+		time:   ll.time,
+		ctx:    ctx,
 	}
 }
 
@@ -47,15 +94,58 @@ func (ll *Loglet) WithStack(skip int) Loglet {
 		parent: ll,
 		level:  ll.level,
 		stack:  core.StackTrace(skip + 1),
+		time:   ll.time,
+		ctx:    ll.ctx,
 	}
 }
 
+// WithCallStack attaches a given call stack to a new Loglet
+func (ll *Loglet) WithCallStack(st core.Stack) Loglet {
+	return Loglet{
+		parent: ll,
+		level:  ll.level,
+		stack:  st,
+		time:   ll.time,
+		ctx:    ll.ctx,
+	}
+}
+
+// WithCaller attaches the frame skip levels above the caller
+// to a new Loglet, as slog.CallerFieldName field of core.Frame type
+func (ll *Loglet) WithCaller(skip int) Loglet {
+	if f := core.StackFrame(skip + 1); f != nil {
+		return ll.WithField(slog.CallerFieldName, *f)
+	}
+	return *ll
+}
+
+// Name returns the dot-separated name of the Loglet,
+// stored as slog.NameFieldName field
+func (ll *Loglet) Name() string {
+	if v, ok := ll.Field(slog.NameFieldName); ok {
+		if s, ok := v.(string); ok {
+			return s
+		}
+	}
+	return ""
+}
+
+// WithName appends a component to the name of a new Loglet
+func (ll *Loglet) WithName(name string) Loglet {
+	if name == "" {
+		return *ll
+	}
+	return ll.WithField(slog.NameFieldName, slog.JoinName(ll.Name(), name))
+}
+
 // WithField attaches a field to a new Loglet
 func (ll *Loglet) WithField(label string, value any) Loglet {
 	out := Loglet{
 		parent: ll,
 		level:  ll.level,
 		stack:  ll.stack,
+		time:   ll.time,
+		ctx:    ll.ctx,
 	}
 
 	if label != "" {
@@ -85,6 +175,8 @@ func (ll *Loglet) WithFields(fields map[string]any) Loglet {
 			parent: ll,
 			level:  ll.level,
 			stack:  ll.stack,
+			time:   ll.time,
+			ctx:    ll.ctx,
 			keys:   keys[:i],
 			values: values[:i],
 		}
@@ -92,6 +184,43 @@ func (ll *Loglet) WithFields(fields map[string]any) Loglet {
 	return *ll
 }
 
+// WithKV attaches fields given as alternating key/value pairs,
+// as parsed by slog.NextKV(), to a new Loglet
+func (ll *Loglet) WithKV(pairs ...any) Loglet {
+	if len(pairs) == 0 {
+		return *ll
+	}
+
+	n := (len(pairs) + 1) / 2
+	keys := make([]string, 0, n)
+	values := make([]any, 0, n)
+
+	for len(pairs) > 0 {
+		var k string
+		var v any
+
+		k, v, pairs = slog.NextKV(pairs)
+		if k != "" {
+			keys = append(keys, k)
+			values = append(values, v)
+		}
+	}
+
+	if len(keys) == 0 {
+		return *ll
+	}
+
+	return Loglet{
+		parent: ll,
+		level:  ll.level,
+		stack:  ll.stack,
+		time:   ll.time,
+		ctx:    ll.ctx,
+		keys:   keys,
+		values: values,
+	}
+}
+
 // FieldsCount return the number of fields on a Log context
 func (ll *Loglet) FieldsCount() int {
 	count := 0
@@ -102,6 +231,65 @@ func (ll *Loglet) FieldsCount() int {
 	return count
 }
 
+// Field returns the value of the newest field with the given key,
+// without computing slog.Lazy values
+func (ll *Loglet) Field(key string) (any, bool) {
+	for ll != nil {
+		for i, k := range ll.keys {
+			if k == key {
+				return ll.values[i], true
+			}
+		}
+		ll = ll.parent
+	}
+	return nil, false
+}
+
+// Depth returns the number of links on the Log context chain
+func (ll *Loglet) Depth() int {
+	depth := 0
+	for ll != nil {
+		depth++
+		ll = ll.parent
+	}
+	return depth
+}
+
+// Compact flattens the Log context chain into a single Loglet
+// when it's longer than DefaultMaxDepth
+func (ll *Loglet) Compact() Loglet {
+	return ll.CompactDepth(DefaultMaxDepth)
+}
+
+// CompactDepth flattens the Log context chain into a single Loglet
+// when it's longer than the given depth. Fields set on newer links
+// override those on their parents.
+func (ll *Loglet) CompactDepth(maxDepth int) Loglet {
+	if ll.Depth() <= maxDepth {
+		return *ll
+	}
+
+	n := ll.FieldsCount()
+	keys := make([]string, 0, n)
+	values := make([]any, 0, n)
+
+	// raw values, Lazy ones are evaluated when emitted
+	iter := ll.UniqueFields()
+	for iter.Next() {
+		keys = append(keys, iter.k)
+		values = append(values, iter.v)
+	}
+
+	return Loglet{
+		level:  ll.level,
+		stack:  ll.stack,
+		time:   ll.time,
+		ctx:    ll.ctx,
+		keys:   keys,
+		values: values,
+	}
+}
+
 // Fields returns a FieldsIterator
 func (ll *Loglet) Fields() (iter *FieldsIterator) {
 	return &FieldsIterator{
@@ -110,12 +298,42 @@ func (ll *Loglet) Fields() (iter *FieldsIterator) {
 	}
 }
 
+// UniqueFields returns a FieldsIterator that skips keys
+// already yielded by newer links of the chain, so fields
+// set on a child override those of its parents
+func (ll *Loglet) UniqueFields() (iter *FieldsIterator) {
+	return &FieldsIterator{
+		ll:   ll,
+		i:    0,
+		seen: make(map[string]struct{}),
+	}
+}
+
+// FieldsMap returns the fields of the Log context as a map,
+// or nil if there are none. Fields set on a child override
+// those of its parents, and slog.Lazy values are computed
+func (ll *Loglet) FieldsMap() map[string]any {
+	n := ll.FieldsCount()
+	if n == 0 {
+		return nil
+	}
+
+	m := make(map[string]any, n)
+	iter := ll.UniqueFields()
+	for iter.Next() {
+		k, v := iter.Field()
+		m[k] = v
+	}
+	return m
+}
+
 // FieldsIterator iterates over fields on a Log context
 type FieldsIterator struct {
-	ll *Loglet
-	i  int
-	k  string
-	v  any
+	ll   *Loglet
+	seen map[string]struct{}
+	i    int
+	k    string
+	v    any
 }
 
 // Next advances iterator to next value. it returns false to indicate
@@ -127,9 +345,14 @@ func (iter *FieldsIterator) Next() bool {
 		ll := iter.ll
 
 		if i := iter.i; i < len(ll.keys) {
+			iter.i = i + 1
+
+			if iter.skip(ll.keys[i]) {
+				continue
+			}
+
 			iter.k = ll.keys[i]
 			iter.v = ll.values[i]
-			iter.i = i + 1
 			return true
 		}
 
@@ -140,17 +363,34 @@ func (iter *FieldsIterator) Next() bool {
 	return false
 }
 
+// skip tells if a key was already yielded when
+// iterating over unique fields
+func (iter *FieldsIterator) skip(key string) bool {
+	if iter.seen == nil {
+		return false
+	}
+
+	if _, ok := iter.seen[key]; ok {
+		return true
+	}
+
+	iter.seen[key] = struct{}{}
+	return false
+}
+
 // Key returns the label of the current field
 func (iter *FieldsIterator) Key() string {
 	return iter.k
 }
 
-// Value returns the value of the current field
+// Value returns the value of the current field,
+// computing it if it's slog.Lazy
 func (iter *FieldsIterator) Value() any {
-	return iter.v
+	return slog.ResolveValue(iter.v)
 }
 
-// Field returns key and value of the current field
+// Field returns key and value of the current field,
+// computing the value if it's slog.Lazy
 func (iter *FieldsIterator) Field() (key string, value any) {
-	return iter.k, iter.v
+	return iter.k, slog.ResolveValue(iter.v)
 }
@@ -0,0 +1,55 @@
+package internal
+
+import "sync"
+
+// Observers is a set of callbacks that can be added and
+// removed concurrently, called in the order they were added
+type Observers[T any] struct {
+	mu   sync.Mutex
+	fns  []observer[T]
+	next uint64
+}
+
+type observer[T any] struct {
+	fn T
+	id uint64
+}
+
+// Add registers a callback, returning a function
+// to remove it
+func (o *Observers[T]) Add(fn T) (remove func()) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	id := o.next
+	o.next++
+	o.fns = append(o.fns, observer[T]{fn: fn, id: id})
+
+	return func() {
+		o.mu.Lock()
+		defer o.mu.Unlock()
+
+		for i, ob := range o.fns {
+			if ob.id == id {
+				// copy on write, Each may be iterating
+				fns := make([]observer[T], 0, len(o.fns)-1)
+				fns = append(fns, o.fns[:i]...)
+				o.fns = append(fns, o.fns[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Each calls the given function for each registered callback,
+// in the order they were added, without holding the lock so
+// they can remove themselves
+func (o *Observers[T]) Each(call func(T)) {
+	o.mu.Lock()
+	fns := o.fns
+	o.mu.Unlock()
+
+	for _, ob := range fns {
+		call(ob.fn)
+	}
+}
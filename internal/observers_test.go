@@ -0,0 +1,34 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestObserversOrder(t *testing.T) {
+	var o Observers[int]
+	var got []int
+
+	removes := make([]func(), 5)
+	for i := range removes {
+		removes[i] = o.Add(i)
+	}
+	removes[1]()
+
+	o.Each(func(i int) {
+		got = append(got, i)
+		if i == 2 {
+			// removing while iterating
+			removes[3]()
+		}
+	})
+	if want := []int{0, 2, 3, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	got = got[:0]
+	o.Each(func(i int) { got = append(got, i) })
+	if want := []int{0, 2, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v after removing, got %v", want, got)
+	}
+}
@@ -0,0 +1,63 @@
+package internal
+
+import "sync"
+
+const (
+	// maxPooledFields is the size above which field maps
+	// aren't returned to the pool, so occasional large
+	// entries don't pin memory
+	maxPooledFields = 64
+)
+
+var (
+	entryPool = sync.Pool{
+		New: func() any { return new(Entry) },
+	}
+
+	fieldsPool = sync.Pool{
+		New: func() any { return make(map[string]any) },
+	}
+)
+
+// AcquireEntry returns a zeroed Entry from the pool. It must be
+// given back using Release() once nothing refers to it.
+func AcquireEntry() *Entry {
+	return entryPool.Get().(*Entry)
+}
+
+// Release returns the Entry to the pool. Neither the Entry
+// nor its Loglet can be used afterwards.
+func (e *Entry) Release() {
+	if e != nil {
+		*e = Entry{}
+		entryPool.Put(e)
+	}
+}
+
+// AcquireFieldsMap returns the fields of the Log context like
+// FieldsMap(), but using a map from the pool. It must be given
+// back using ReleaseFieldsMap() once nothing refers to it.
+func (ll *Loglet) AcquireFieldsMap() map[string]any {
+	if ll.FieldsCount() == 0 {
+		return nil
+	}
+
+	m := fieldsPool.Get().(map[string]any)
+	iter := ll.UniqueFields()
+	for iter.Next() {
+		k, v := iter.Field()
+		m[k] = v
+	}
+	return m
+}
+
+// ReleaseFieldsMap returns a map obtained from AcquireFieldsMap()
+// to the pool. The map can't be used afterwards.
+func ReleaseFieldsMap(m map[string]any) {
+	if m == nil || len(m) > maxPooledFields {
+		return
+	}
+
+	clear(m)
+	fieldsPool.Put(m)
+}
@@ -0,0 +1,74 @@
+package internal
+
+import (
+	"testing"
+)
+
+func newBenchLoglet() Loglet {
+	ll := new(Loglet).WithFields(map[string]any{
+		"method": "GET",
+		"path":   "/",
+		"status": 200,
+	})
+	return ll.WithField("bytes", 1024)
+}
+
+func TestAcquireFieldsMap(t *testing.T) {
+	ll := newBenchLoglet()
+
+	m := ll.AcquireFieldsMap()
+	if len(m) != 4 || m["status"] != 200 {
+		t.Errorf("unexpected fields: %v", m)
+	}
+	ReleaseFieldsMap(m)
+
+	var empty Loglet
+	if m := empty.AcquireFieldsMap(); m != nil {
+		t.Errorf("expected nil for no fields, got %v", m)
+	}
+}
+
+func BenchmarkFieldsMap(b *testing.B) {
+	ll := newBenchLoglet()
+
+	b.Run("alloc", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = ll.FieldsMap()
+		}
+	})
+
+	b.Run("pool", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			ReleaseFieldsMap(ll.AcquireFieldsMap())
+		}
+	})
+}
+
+func BenchmarkEntry(b *testing.B) {
+	ll := newBenchLoglet()
+
+	b.Run("alloc", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			e := &Entry{Loglet: &ll, Message: "message"}
+			benchEntrySink = e
+		}
+	})
+
+	b.Run("pool", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			e := AcquireEntry()
+			e.Loglet = &ll
+			e.Message = "message"
+			benchEntrySink = e
+			e.Release()
+		}
+	})
+}
+
+// benchEntrySink prevents the compiler from keeping
+// the benchmarked entries on the stack
+var benchEntrySink *Entry
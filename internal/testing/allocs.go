@@ -0,0 +1,41 @@
+package testing
+
+import (
+	"testing"
+)
+
+// DefaultAllocsRuns is the number of runs averaged by
+// CheckDisabledAllocs
+const DefaultAllocsRuns = 100
+
+// CheckDisabledAllocs fails the test if attaching fields, a call
+// stack, or printing on a disabled Debug logger allocates.
+// Values are boxed beforehand so only the handler is measured.
+// The test is skipped if the Debug level is enabled.
+func CheckDisabledAllocs(t testing.TB, factory LoggerFactory) {
+	t.Helper()
+
+	base := factory()
+	if base.Debug().Enabled() {
+		t.Skip("Debug level is enabled")
+	}
+
+	var value any = "value"
+	fields := map[string]any{"key2": value}
+	args := []any{benchMessage, value}
+
+	allocs := testing.AllocsPerRun(DefaultAllocsRuns, func() {
+		l := base.Debug().
+			WithField("key1", value).
+			WithFields(fields).
+			WithStack(0)
+
+		l.Print(args...)
+		l.Println(args...)
+		l.Printf(benchMessage+" %v", args[1:]...)
+	})
+
+	if allocs > 0 {
+		t.Errorf("disabled logger: %v allocations per entry", allocs)
+	}
+}
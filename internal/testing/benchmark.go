@@ -0,0 +1,126 @@
+package testing
+
+import (
+	"testing"
+
+	"darvaza.org/slog"
+)
+
+// DefaultBenchmarkChainDepth is the number of WithField calls
+// used by the deep chain benchmark
+const DefaultBenchmarkChainDepth = 100
+
+const benchMessage = "benchmark message"
+
+// BenchmarkSuite runs a set of comparable benchmarks against
+// loggers created by a LoggerFactory
+type BenchmarkSuite struct {
+	// Factory creates the loggers to benchmark
+	Factory LoggerFactory
+
+	// ChainDepth is the number of WithField calls used by
+	// the deep chain benchmark. Defaults to
+	// DefaultBenchmarkChainDepth
+	ChainDepth int
+}
+
+// RunBenchmarks runs the standard BenchmarkSuite using the given factory
+func RunBenchmarks(b *testing.B, factory LoggerFactory) {
+	s := BenchmarkSuite{
+		Factory: factory,
+	}
+	s.Run(b)
+}
+
+// Run runs all the benchmarks of the suite
+func (s BenchmarkSuite) Run(b *testing.B) {
+	if s.Factory == nil {
+		b.Fatal("BenchmarkSuite: no factory")
+	}
+	if s.ChainDepth <= 0 {
+		s.ChainDepth = DefaultBenchmarkChainDepth
+	}
+
+	b.Run("Print", s.benchPrint)
+	b.Run("Printf", s.benchPrintf)
+	b.Run("WithField", s.benchWithField)
+	b.Run("WithFields", s.benchWithFields)
+	b.Run("Disabled", s.benchDisabled)
+	b.Run("DeepChain", s.benchDeepChain)
+}
+
+func (s BenchmarkSuite) benchPrint(b *testing.B) {
+	l := s.Factory().Info()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Print(benchMessage)
+	}
+}
+
+func (s BenchmarkSuite) benchPrintf(b *testing.B) {
+	l := s.Factory().Info()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Printf(benchMessage+" %d: %s", i, "value")
+	}
+}
+
+func (s BenchmarkSuite) benchWithField(b *testing.B) {
+	l := s.Factory().Info()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.WithField("key1", "value").
+			WithField("key2", i).
+			Print(benchMessage)
+	}
+}
+
+func (s BenchmarkSuite) benchWithFields(b *testing.B) {
+	l := s.Factory().Info()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.WithFields(slog.Fields{
+			"key1": "value",
+			"key2": i,
+			"key3": true,
+		}).Print(benchMessage)
+	}
+}
+
+func (s BenchmarkSuite) benchDisabled(b *testing.B) {
+	l, ok := s.Factory().Debug().WithEnabled()
+	if ok {
+		b.Skip("Debug level is enabled")
+	}
+
+	// boxed beforehand so only the handler is measured
+	var value any = "value"
+	args := []any{value}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.WithField("key", value).Printf(benchMessage+" %v", args...)
+	}
+}
+
+func (s BenchmarkSuite) benchDeepChain(b *testing.B) {
+	l := s.Factory().Info()
+	for i := 0; i < s.ChainDepth; i++ {
+		l = l.WithField("key", i)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Print(benchMessage)
+	}
+}
@@ -0,0 +1,98 @@
+package testing
+
+import (
+	"sync"
+	"time"
+
+	"darvaza.org/slog/internal"
+)
+
+var (
+	_ internal.Clock = (*ManualClock)(nil)
+)
+
+// ManualClock is an internal.Clock that only moves when told to,
+// so time dependent behaviour can be tested deterministically
+type ManualClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []clockWaiter
+}
+
+type clockWaiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+// NewManualClock creates a ManualClock set to the given time
+func NewManualClock(start time.Time) *ManualClock {
+	return &ManualClock{now: start}
+}
+
+// Now returns the current time of the clock
+func (c *ManualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// After returns a channel that receives the time of the clock
+// once it has been advanced by the given duration
+func (c *ManualClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+	} else {
+		c.waiters = append(c.waiters, clockWaiter{
+			at: c.now.Add(d),
+			ch: ch,
+		})
+	}
+	return ch
+}
+
+// Advance moves the clock forward, firing the channels
+// returned by After that are due
+func (c *ManualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.set(c.now.Add(d))
+}
+
+// Set moves the clock to the given time, firing the channels
+// returned by After that are due
+func (c *ManualClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.set(t)
+}
+
+func (c *ManualClock) set(t time.Time) {
+	c.now = t
+
+	waiters := c.waiters[:0]
+	for _, w := range c.waiters {
+		if t.Before(w.at) {
+			waiters = append(waiters, w)
+		} else {
+			w.ch <- t
+		}
+	}
+	c.waiters = waiters
+}
+
+// Waiters returns the number of channels waiting for the
+// clock to advance, useful to know when a goroutine is
+// ready to be woken up
+func (c *ManualClock) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.waiters)
+}
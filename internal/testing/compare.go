@@ -0,0 +1,229 @@
+package testing
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// CompareOption modifies how CompareMessages matches messages
+type CompareOption func(*compareConfig)
+
+type compareConfig struct {
+	ignore  map[string]struct{}
+	ordered bool
+	subset  bool
+}
+
+// Ordered makes CompareMessages require the messages
+// to appear in the same order
+func Ordered() CompareOption {
+	return func(cfg *compareConfig) {
+		cfg.ordered = true
+	}
+}
+
+// IgnoreFields makes CompareMessages skip the given fields,
+// e.g. timestamps or sequence numbers
+func IgnoreFields(keys ...string) CompareOption {
+	return func(cfg *compareConfig) {
+		if cfg.ignore == nil {
+			cfg.ignore = make(map[string]struct{}, len(keys))
+		}
+		for _, k := range keys {
+			cfg.ignore[k] = struct{}{}
+		}
+	}
+}
+
+// FieldSubset makes CompareMessages accept messages carrying
+// more fields than expected, as long as those expected match
+func FieldSubset() CompareOption {
+	return func(cfg *compareConfig) {
+		cfg.subset = true
+	}
+}
+
+// MessageMismatch describes an expected message paired with
+// the actual one found in its place, when comparing in order
+type MessageMismatch struct {
+	Expected Message
+	Actual   Message
+	Reasons  []string
+	Index    int
+}
+
+// MessagesDiff is the result of CompareMessages
+type MessagesDiff struct {
+	// Matched are the actual messages matching the expectations
+	Matched []Message
+	// Mismatched are the expected messages found at their
+	// position, but different, when comparing in order
+	Mismatched []MessageMismatch
+	// Missing are the expected messages not found
+	Missing []Message
+	// Unexpected are the actual messages not expected
+	Unexpected []Message
+}
+
+// Equal tells if all messages matched
+func (d *MessagesDiff) Equal() bool {
+	return len(d.Mismatched) == 0 && len(d.Missing) == 0 && len(d.Unexpected) == 0
+}
+
+// String describes the differences, one per line
+func (d *MessagesDiff) String() string {
+	var b strings.Builder
+
+	for _, m := range d.Mismatched {
+		_, _ = fmt.Fprintf(&b, "#%d: %s\n\texpected: %s\n\tactual:   %s\n",
+			m.Index, strings.Join(m.Reasons, ", "), m.Expected, m.Actual)
+	}
+	for _, m := range d.Missing {
+		_, _ = fmt.Fprintf(&b, "missing: %s\n", m)
+	}
+	for _, m := range d.Unexpected {
+		_, _ = fmt.Fprintf(&b, "unexpected: %s\n", m)
+	}
+	return b.String()
+}
+
+// CompareMessages matches the actual messages against those expected
+// by level, message, fields and whether they carry a call stack.
+// By default order doesn't matter and fields must be the same.
+// Times aren't compared, see AssertOrdered and AssertWithin.
+func CompareMessages(expected, actual []Message, opts ...CompareOption) *MessagesDiff {
+	var cfg compareConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if cfg.ordered {
+		return cfg.compareOrdered(expected, actual)
+	}
+	return cfg.compareUnordered(expected, actual)
+}
+
+func (cfg *compareConfig) compareOrdered(expected, actual []Message) *MessagesDiff {
+	d := new(MessagesDiff)
+
+	n := min(len(expected), len(actual))
+	for i := 0; i < n; i++ {
+		if reasons := cfg.diff(expected[i], actual[i]); len(reasons) > 0 {
+			d.Mismatched = append(d.Mismatched, MessageMismatch{
+				Index:    i,
+				Expected: expected[i],
+				Actual:   actual[i],
+				Reasons:  reasons,
+			})
+		} else {
+			d.Matched = append(d.Matched, actual[i])
+		}
+	}
+
+	d.Missing = append(d.Missing, expected[n:]...)
+	d.Unexpected = append(d.Unexpected, actual[n:]...)
+	return d
+}
+
+func (cfg *compareConfig) compareUnordered(expected, actual []Message) *MessagesDiff {
+	d := new(MessagesDiff)
+	used := make([]bool, len(actual))
+
+	for _, want := range expected {
+		found := false
+		for i, got := range actual {
+			if !used[i] && len(cfg.diff(want, got)) == 0 {
+				used[i] = true
+				found = true
+				d.Matched = append(d.Matched, got)
+				break
+			}
+		}
+
+		if !found {
+			d.Missing = append(d.Missing, want)
+		}
+	}
+
+	for i, got := range actual {
+		if !used[i] {
+			d.Unexpected = append(d.Unexpected, got)
+		}
+	}
+	return d
+}
+
+// diff returns the reasons two messages don't match
+func (cfg *compareConfig) diff(want, got Message) []string {
+	var reasons []string
+
+	if want.Level != got.Level {
+		reasons = append(reasons, "level")
+	}
+	if want.Message != got.Message {
+		reasons = append(reasons, "message")
+	}
+	if (len(want.Stack) > 0) != (len(got.Stack) > 0) {
+		reasons = append(reasons, "stack")
+	}
+
+	for _, k := range cfg.fieldsDiff(want.Fields, got.Fields) {
+		reasons = append(reasons, "field "+k)
+	}
+	return reasons
+}
+
+// fieldsDiff returns the sorted keys whose values differ
+func (cfg *compareConfig) fieldsDiff(want, got map[string]any) []string {
+	var keys []string
+
+	for k, v := range want {
+		if cfg.ignored(k) {
+			continue
+		}
+		if x, ok := got[k]; !ok || !SameValue(v, x) {
+			keys = append(keys, k)
+		}
+	}
+
+	if !cfg.subset {
+		for k := range got {
+			if _, ok := want[k]; !ok && !cfg.ignored(k) {
+				keys = append(keys, k)
+			}
+		}
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+func (cfg *compareConfig) ignored(key string) bool {
+	_, ok := cfg.ignore[key]
+	return ok
+}
+
+// SameValue tells if two field values are deeply equal,
+// considering NaN equal to itself
+func SameValue(a, b any) bool {
+	if x, ok := toFloat(a); ok {
+		if y, ok := toFloat(b); ok && math.IsNaN(x) && math.IsNaN(y) {
+			return true
+		}
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func toFloat(v any) (float64, bool) {
+	switch x := v.(type) {
+	case float64:
+		return x, true
+	case float32:
+		return float64(x), true
+	default:
+		return 0, false
+	}
+}
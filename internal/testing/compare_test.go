@@ -0,0 +1,78 @@
+package testing
+
+import (
+	"math"
+	"testing"
+
+	"darvaza.org/core"
+	"darvaza.org/slog"
+)
+
+func msg(level slog.LogLevel, text string, fields map[string]any) Message {
+	return Message{Level: level, Message: text, Fields: fields}
+}
+
+func TestCompareMessagesUnordered(t *testing.T) {
+	a := msg(slog.Info, "a", map[string]any{"k": 1})
+	b := msg(slog.Warn, "b", nil)
+	c := msg(slog.Error, "c", nil)
+
+	d := CompareMessages([]Message{a, b}, []Message{b, a})
+	if !d.Equal() {
+		t.Errorf("unexpected diff:\n%s", d)
+	}
+
+	d = CompareMessages([]Message{a, b}, []Message{b, c})
+	if d.Equal() || len(d.Missing) != 1 || len(d.Unexpected) != 1 || len(d.Matched) != 1 {
+		t.Errorf("unexpected diff:\n%s", d)
+	}
+}
+
+func TestCompareMessagesOrdered(t *testing.T) {
+	a := msg(slog.Info, "a", nil)
+	b := msg(slog.Warn, "b", nil)
+
+	d := CompareMessages([]Message{a, b}, []Message{b, a}, Ordered())
+	if len(d.Mismatched) != 2 {
+		t.Fatalf("expected 2 mismatches:\n%s", d)
+	}
+	if got := d.Mismatched[0].Reasons; len(got) != 2 {
+		t.Errorf("expected level and message reasons, got %q", got)
+	}
+
+	d = CompareMessages([]Message{a}, []Message{a, b}, Ordered())
+	if len(d.Unexpected) != 1 || len(d.Matched) != 1 {
+		t.Errorf("unexpected diff:\n%s", d)
+	}
+}
+
+func TestCompareMessagesFields(t *testing.T) {
+	want := msg(slog.Info, "x", map[string]any{"k": "v", "nan": math.NaN()})
+	got := msg(slog.Info, "x", map[string]any{"k": "v", "nan": math.NaN(), "seq": 7})
+
+	if CompareMessages([]Message{want}, []Message{got}).Equal() {
+		t.Error("extra field accepted")
+	}
+	if d := CompareMessages([]Message{want}, []Message{got}, IgnoreFields("seq")); !d.Equal() {
+		t.Errorf("ignored field compared:\n%s", d)
+	}
+	if d := CompareMessages([]Message{want}, []Message{got}, FieldSubset()); !d.Equal() {
+		t.Errorf("subset rejected:\n%s", d)
+	}
+
+	got.Fields["k"] = "w"
+	if CompareMessages([]Message{want}, []Message{got}, FieldSubset()).Equal() {
+		t.Error("different value accepted")
+	}
+}
+
+func TestCompareMessagesStack(t *testing.T) {
+	want := msg(slog.Info, "x", nil)
+	got := want
+	got.Stack = core.StackTrace(0)
+
+	d := CompareMessages([]Message{want}, []Message{got}, Ordered())
+	if len(d.Mismatched) != 1 || d.Mismatched[0].Reasons[0] != "stack" {
+		t.Errorf("stack not compared:\n%s", d)
+	}
+}
@@ -0,0 +1,159 @@
+package testing
+
+import (
+	"sync"
+	"testing"
+
+	"darvaza.org/core"
+	"darvaza.org/slog"
+)
+
+const (
+	// DefaultConcurrentGoroutines is the default number of goroutines
+	// used by the ConcurrentMutationSuite
+	DefaultConcurrentGoroutines = 8
+	// DefaultConcurrentIterations is the default number of iterations
+	// each goroutine of the ConcurrentMutationSuite does
+	DefaultConcurrentIterations = 100
+)
+
+// ConcurrentMutationSuite exercises a shared logger from multiple
+// goroutines at the same time, deriving new contexts, logging and
+// optionally changing thresholds. It's designed to be run with -race.
+type ConcurrentMutationSuite struct {
+	// Factory creates the logger to share
+	Factory LoggerFactory
+
+	// SetThreshold, if provided, is called concurrently
+	// to change the minimum level of the logger
+	SetThreshold func(slog.LogLevel)
+
+	// Budget, if provided, fails the test when the latency of
+	// the logging operations exceeds it
+	Budget *LatencyBudget
+
+	// Leaks, if provided, fails the test when goroutines or heap
+	// don't return to the baseline after Teardown
+	Leaks *LeakOptions
+	// Teardown, if provided, is called with the shared logger
+	// once the workers have finished, e.g. to close it
+	Teardown func(slog.Logger)
+
+	// Goroutines is the number of concurrent workers.
+	// Defaults to DefaultConcurrentGoroutines
+	Goroutines int
+	// Iterations is the number of rounds each worker does.
+	// Defaults to DefaultConcurrentIterations
+	Iterations int
+}
+
+// RunConcurrentMutation runs the default ConcurrentMutationSuite
+// using the given factory
+func RunConcurrentMutation(t *testing.T, factory LoggerFactory) {
+	s := ConcurrentMutationSuite{
+		Factory: factory,
+	}
+	s.Run(t)
+}
+
+// Run runs the suite. The latency of every logging operation is
+// recorded and its distribution logged when testing verbosely.
+func (s ConcurrentMutationSuite) Run(t *testing.T) {
+	var wg sync.WaitGroup
+	var stats LatencyStats
+
+	if s.Factory == nil {
+		t.Fatal("ConcurrentMutationSuite: no factory")
+	}
+	if s.Goroutines <= 0 {
+		s.Goroutines = DefaultConcurrentGoroutines
+	}
+	if s.Iterations <= 0 {
+		s.Iterations = DefaultConcurrentIterations
+	}
+
+	var checkLeaks func()
+	if s.Leaks != nil {
+		checkLeaks = CheckLeaks(t, s.Leaks)
+	}
+
+	logger := s.Factory()
+	base := logger.WithField("shared", true)
+
+	for g := 0; g < s.Goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			s.catch(t, func() { s.mutate(&stats, base, g) })
+		}(g)
+	}
+
+	if fn := s.SetThreshold; fn != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.catch(t, func() { s.toggle(fn) })
+		}()
+	}
+
+	wg.Wait()
+
+	t.Logf("latency: %s", &stats)
+	if s.Budget != nil {
+		s.Budget.Check(t, &stats)
+	}
+
+	if fn := s.Teardown; fn != nil {
+		fn(logger)
+	}
+	if checkLeaks != nil {
+		checkLeaks()
+	}
+}
+
+func (ConcurrentMutationSuite) catch(t *testing.T, fn func()) {
+	err := core.Catch(func() error {
+		fn()
+		return nil
+	})
+	if err != nil {
+		t.Errorf("concurrent mutation panicked: %v", err)
+	}
+}
+
+func (s ConcurrentMutationSuite) mutate(stats *LatencyStats, base slog.Logger, g int) {
+	for i := 0; i < s.Iterations; i++ {
+		level := fuzzLevels[(g+i)%len(fuzzLevels)]
+
+		l := base.WithLevel(level).WithField("goroutine", g)
+		stats.Measure(func() {
+			l.WithField("iteration", i).Printf("goroutine %d iteration %d", g, i)
+		})
+
+		stats.Measure(func() {
+			l.WithFields(slog.Fields{
+				"goroutine": -g,
+				"i":         i,
+			}).Print("fields")
+		})
+
+		if ll, ok := l.WithEnabled(); ok {
+			stats.Measure(func() {
+				ll.WithStack(0).Println("enabled")
+			})
+		}
+
+		// reuse the shared parent on every level
+		stats.Measure(func() { base.Debug().Print("debug") })
+		stats.Measure(func() { base.Info().Print("info") })
+		stats.Measure(func() { base.Warn().Print("warn") })
+		stats.Measure(func() { base.Error().Print("error") })
+	}
+}
+
+func (s ConcurrentMutationSuite) toggle(setThreshold func(slog.LogLevel)) {
+	for i := 0; i < s.Iterations; i++ {
+		setThreshold(fuzzLevels[i%len(fuzzLevels)])
+	}
+	setThreshold(slog.Debug)
+}
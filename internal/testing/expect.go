@@ -0,0 +1,241 @@
+package testing
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"darvaza.org/core"
+	"darvaza.org/slog"
+)
+
+// Expectation describes a message to be found by AssertMessagesMatch
+// or AssertMessagesInOrder. Conditions are added using its chainable
+// methods, and a message matches when it meets all of them.
+type Expectation struct {
+	fields   map[string]any
+	present  []string
+	absent   []string
+	message  *string
+	contains []string
+	stack    *bool
+	top      *FrameMatcher
+	level    slog.LogLevel
+}
+
+// Expect starts a new Expectation matching any message
+func Expect() *Expectation {
+	return new(Expectation)
+}
+
+// Level requires the message to be of the given level
+func (e *Expectation) Level(level slog.LogLevel) *Expectation {
+	e.level = level
+	return e
+}
+
+// Message requires the message text to be exactly the given one
+func (e *Expectation) Message(s string) *Expectation {
+	e.message = &s
+	return e
+}
+
+// MessageContains requires the message text to contain the given one
+func (e *Expectation) MessageContains(s string) *Expectation {
+	e.contains = append(e.contains, s)
+	return e
+}
+
+// Field requires a field with the given value
+func (e *Expectation) Field(key string, value any) *Expectation {
+	if e.fields == nil {
+		e.fields = make(map[string]any)
+	}
+	e.fields[key] = value
+	return e
+}
+
+// HasField requires a field, whatever its value
+func (e *Expectation) HasField(key string) *Expectation {
+	e.present = append(e.present, key)
+	return e
+}
+
+// NoField requires the message not to have the given field
+func (e *Expectation) NoField(key string) *Expectation {
+	e.absent = append(e.absent, key)
+	return e
+}
+
+// Stack requires the message to carry a call stack, or not to
+func (e *Expectation) Stack(want bool) *Expectation {
+	e.stack = &want
+	return e
+}
+
+// StackTop requires the message to carry a call stack whose
+// innermost frame meets the FrameMatcher
+func (e *Expectation) StackTop(fm FrameMatcher) *Expectation {
+	e.top = &fm
+	return e
+}
+
+// Match returns the reasons the message doesn't meet the
+// Expectation, or none if it does
+func (e *Expectation) Match(m Message) []string {
+	var reasons []string
+
+	if e.level != slog.UndefinedLevel && m.Level != e.level {
+		reasons = append(reasons, fmt.Sprintf("level %s", m.Level))
+	}
+	if e.message != nil && m.Message != *e.message {
+		reasons = append(reasons, fmt.Sprintf("message %q", m.Message))
+	}
+	for _, s := range e.contains {
+		if !strings.Contains(m.Message, s) {
+			reasons = append(reasons, fmt.Sprintf("message without %q", s))
+		}
+	}
+	if e.stack != nil && *e.stack != (len(m.Stack) > 0) {
+		reasons = append(reasons, fmt.Sprintf("stack %v", len(m.Stack) > 0))
+	}
+	if e.top != nil && (len(m.Stack) == 0 || !e.top.Match(m.Stack[0])) {
+		reasons = append(reasons, "stack not starting at "+e.top.String())
+	}
+
+	return append(reasons, e.matchFields(m.Fields)...)
+}
+
+func (e *Expectation) matchFields(fields map[string]any) []string {
+	var reasons []string
+
+	for _, k := range core.SortedKeys(e.fields) {
+		if v, ok := fields[k]; !ok {
+			reasons = append(reasons, fmt.Sprintf("no field %q", k))
+		} else if !SameValue(e.fields[k], v) {
+			reasons = append(reasons, fmt.Sprintf("field %q is %#v", k, v))
+		}
+	}
+	for _, k := range e.present {
+		if _, ok := fields[k]; !ok {
+			reasons = append(reasons, fmt.Sprintf("no field %q", k))
+		}
+	}
+	for _, k := range e.absent {
+		if _, ok := fields[k]; ok {
+			reasons = append(reasons, fmt.Sprintf("unexpected field %q", k))
+		}
+	}
+	return reasons
+}
+
+// String describes the Expectation, for test failures
+func (e *Expectation) String() string {
+	var parts []string
+
+	if e.level != slog.UndefinedLevel {
+		parts = append(parts, "level "+e.level.String())
+	}
+	if e.message != nil {
+		parts = append(parts, fmt.Sprintf("message %q", *e.message))
+	}
+	for _, s := range e.contains {
+		parts = append(parts, fmt.Sprintf("message containing %q", s))
+	}
+	for _, k := range core.SortedKeys(e.fields) {
+		parts = append(parts, fmt.Sprintf("field %q=%#v", k, e.fields[k]))
+	}
+	for _, k := range e.present {
+		parts = append(parts, fmt.Sprintf("field %q", k))
+	}
+	for _, k := range e.absent {
+		parts = append(parts, fmt.Sprintf("no field %q", k))
+	}
+	if e.stack != nil {
+		parts = append(parts, fmt.Sprintf("stack %v", *e.stack))
+	}
+	if e.top != nil {
+		parts = append(parts, "stack starting at "+e.top.String())
+	}
+
+	if len(parts) == 0 {
+		return "any message"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// AssertMessagesMatch checks there is one message per Expectation,
+// each meeting a different one, in any order
+func AssertMessagesMatch(t testing.TB, msgs []Message, exps ...*Expectation) bool {
+	t.Helper()
+
+	if !assertCount(t, msgs, exps) {
+		return false
+	}
+
+	// each expectation is paired with a different message,
+	// reassigning earlier pairs when needed
+	owner := make([]int, len(msgs))
+	for i := range owner {
+		owner[i] = -1
+	}
+
+	ok := true
+	for i, e := range exps {
+		if !assignMatch(i, exps, msgs, owner, make([]bool, len(msgs))) {
+			t.Errorf("no message matching %s", e)
+			ok = false
+		}
+	}
+	return ok
+}
+
+// assignMatch finds a message for the i-th Expectation, taking it
+// from its current owner if that one can be given another
+func assignMatch(i int, exps []*Expectation, msgs []Message, owner []int, seen []bool) bool {
+	for j, m := range msgs {
+		if seen[j] || len(exps[i].Match(m)) > 0 {
+			continue
+		}
+
+		seen[j] = true
+		if owner[j] < 0 || assignMatch(owner[j], exps, msgs, owner, seen) {
+			owner[j] = i
+			return true
+		}
+	}
+	return false
+}
+
+// AssertMessagesInOrder checks there is one message per Expectation,
+// each meeting the one at its position
+func AssertMessagesInOrder(t testing.TB, msgs []Message, exps ...*Expectation) bool {
+	t.Helper()
+
+	if !assertCount(t, msgs, exps) {
+		return false
+	}
+
+	ok := true
+	for i, e := range exps {
+		if reasons := e.Match(msgs[i]); len(reasons) > 0 {
+			t.Errorf("#%d: expected %s, got %s: %s", i, e, msgs[i],
+				strings.Join(reasons, ", "))
+			ok = false
+		}
+	}
+	return ok
+}
+
+func assertCount(t testing.TB, msgs []Message, exps []*Expectation) bool {
+	t.Helper()
+
+	if len(msgs) != len(exps) {
+		t.Errorf("expected %d messages, got %d", len(exps), len(msgs))
+		for _, m := range msgs {
+			t.Logf("got: %s", m)
+		}
+		return false
+	}
+	return true
+}
@@ -0,0 +1,101 @@
+package testing
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+
+	"darvaza.org/slog"
+)
+
+// failT records failures instead of failing the test
+type failT struct {
+	testing.TB
+	errors []string
+}
+
+func (*failT) Helper() {}
+
+func (t *failT) Errorf(format string, args ...any) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func (*failT) Logf(string, ...any) {}
+
+// Fatalf records the failure and stops the goroutine,
+// which must not be the one running the test
+func (t *failT) Fatalf(format string, args ...any) {
+	t.Errorf(format, args...)
+	runtime.Goexit()
+}
+
+func TestExpectationMatch(t *testing.T) {
+	m := Message{
+		Level:   slog.Warn,
+		Message: "disk almost full",
+		Fields:  map[string]any{"used": 97, "disk": "/"},
+	}
+
+	for name, tc := range map[string]struct {
+		e  *Expectation
+		ok bool
+	}{
+		"any":            {Expect(), true},
+		"all":            {Expect().Level(slog.Warn).MessageContains("full").Field("used", 97).HasField("disk").NoField("x").Stack(false), true},
+		"level":          {Expect().Level(slog.Error), false},
+		"message":        {Expect().Message("disk"), false},
+		"contains":       {Expect().MessageContains("empty"), false},
+		"field value":    {Expect().Field("used", 98), false},
+		"field missing":  {Expect().HasField("mount"), false},
+		"field present":  {Expect().NoField("disk"), false},
+		"stack expected": {Expect().Stack(true), false},
+	} {
+		reasons := tc.e.Match(m)
+		if ok := len(reasons) == 0; ok != tc.ok {
+			t.Errorf("%s: %s: expected match %v, reasons %q", name, tc.e, tc.ok, reasons)
+		}
+	}
+}
+
+func TestAssertMessagesMatch(t *testing.T) {
+	msgs := []Message{
+		{Level: slog.Info, Message: "first"},
+		{Level: slog.Info, Message: "second"},
+	}
+
+	// the greedy pairing gives "first" to the broad expectation,
+	// which has to be moved for the narrow one
+	broad := Expect().Level(slog.Info)
+	narrow := Expect().Message("first")
+	if !AssertMessagesMatch(t, msgs, broad, narrow) {
+		t.Error("pairing not found")
+	}
+
+	ft := new(failT)
+	if AssertMessagesMatch(ft, msgs, narrow, Expect().Message("first")) || len(ft.errors) != 1 {
+		t.Errorf("unexpected failures: %q", ft.errors)
+	}
+
+	ft = new(failT)
+	if AssertMessagesMatch(ft, msgs, broad) || len(ft.errors) != 1 {
+		t.Errorf("count not checked: %q", ft.errors)
+	}
+}
+
+func TestAssertMessagesInOrder(t *testing.T) {
+	msgs := []Message{
+		{Level: slog.Info, Message: "first"},
+		{Level: slog.Warn, Message: "second"},
+	}
+
+	first := Expect().Message("first")
+	second := Expect().Level(slog.Warn)
+	if !AssertMessagesInOrder(t, msgs, first, second) {
+		t.Error("order not accepted")
+	}
+
+	ft := new(failT)
+	if AssertMessagesInOrder(ft, msgs, second, first) || len(ft.errors) != 2 {
+		t.Errorf("unexpected failures: %q", ft.errors)
+	}
+}
@@ -0,0 +1,164 @@
+package testing
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"darvaza.org/core"
+	"darvaza.org/slog"
+)
+
+// FuzzInput is one set of arbitrary values to feed a logger
+type FuzzInput struct {
+	Message string
+	Key     string
+	Value   string
+	Int     int64
+	Float   float64
+}
+
+// fuzzLevels are the levels used when fuzzing, excluding
+// those that exit or panic by design
+var fuzzLevels = []slog.LogLevel{
+	slog.Error,
+	slog.Warn,
+	slog.Info,
+	slog.Debug,
+}
+
+// FuzzSeeds returns troublesome inputs to seed a fuzzer with
+func FuzzSeeds() []FuzzInput {
+	huge := strings.Repeat("x", 1<<16)
+
+	return []FuzzInput{
+		{},
+		{Message: "hello", Key: "key", Value: "value", Int: 1, Float: 1.5},
+		{Message: "%s %d %v %!", Key: "%s", Value: "%v", Float: math.NaN()},
+		{Message: "\x00\x1b[31m\r\n\t", Key: "\n", Value: "\x7f", Float: math.Inf(1)},
+		{Message: huge, Key: huge, Value: huge, Int: math.MaxInt64, Float: math.Inf(-1)},
+		{Message: "\xff\xfe", Key: "‮", Value: "�", Int: math.MinInt64},
+		{Message: slog.ErrorFieldName, Key: slog.ErrorFieldName, Value: "", Float: -0.0},
+	}
+}
+
+// FuzzLoggerInputs seeds the fuzzer with FuzzSeeds and checks the
+// handlers created by the factory on top of a Recorder handle
+// arbitrary inputs, as by CheckFuzzInput. It's meant to be called
+// from a handler's FuzzXxx function.
+func FuzzLoggerInputs(f *testing.F, factory HandlerFactory) {
+	if factory == nil {
+		f.Fatal("FuzzLoggerInputs: no factory")
+	}
+
+	for _, in := range FuzzSeeds() {
+		f.Add(in.Message, in.Key, in.Value, in.Int, in.Float)
+	}
+
+	f.Fuzz(func(t *testing.T, msg, key, value string, n int64, x float64) {
+		CheckFuzzInput(t, factory, FuzzInput{
+			Message: msg,
+			Key:     key,
+			Value:   value,
+			Int:     n,
+			Float:   x,
+		})
+	})
+}
+
+// CheckFuzzInput feeds the given input, on every non-terminal level,
+// to the handler the factory creates on top of a Recorder. It fails
+// the test if the handler panics, or if the Recorder doesn't get the
+// same messages and fields on the enabled levels, and nothing on
+// the others.
+func CheckFuzzInput(t testing.TB, factory HandlerFactory, in FuzzInput) {
+	t.Helper()
+
+	rec := NewRecorder()
+	l := factory(rec)
+
+	for _, level := range fuzzLevels {
+		rec.Reset()
+
+		ll := l.WithLevel(level)
+		enabled := ll.Enabled()
+
+		err := core.Catch(func() error {
+			feedFuzzInput(ll, in)
+			return nil
+		})
+
+		switch got := rec.Messages(); {
+		case err != nil:
+			t.Fatalf("level %v panicked: %v", level, err)
+		case !enabled && len(got) > 0:
+			t.Fatalf("level %v disabled but recorded:\n%s", level,
+				CompareMessages(nil, got))
+		case enabled:
+			want := fuzzMessages(level, in)
+			if d := CompareMessages(want, got, Ordered()); !d.Equal() {
+				t.Fatalf("level %v didn't round-trip:\n%s", level, d)
+			}
+		}
+	}
+}
+
+func feedFuzzInput(l slog.Logger, in FuzzInput) {
+	var nilMap map[string]any
+
+	l.Print(in.Message)
+	l.Println(in.Message, in.Value)
+	l.Printf(in.Message, in.Value, in.Int, in.Float)
+
+	l.WithField(in.Key, in.Value).
+		WithField(in.Value, in.Float).
+		WithField(slog.ErrorFieldName, in.Int).
+		Print(in.Message)
+
+	l.WithFields(nilMap).
+		WithFields(fuzzFields(in)).
+		Print(in.Message)
+}
+
+// fuzzFields returns the map given to WithFields by feedFuzzInput.
+// Keys may repeat, the last one winning.
+func fuzzFields(in FuzzInput) map[string]any {
+	return map[string]any{
+		in.Key:   in.Value,
+		in.Value: in.Int,
+		"":       in.Float,
+		"float":  in.Float,
+		"nil":    nil,
+	}
+}
+
+// fuzzMessages returns what feedFuzzInput is expected to record
+func fuzzMessages(level slog.LogLevel, in FuzzInput) []Message {
+	text := strings.TrimSpace(in.Message)
+	chained := make(map[string]any, 3)
+	for _, kv := range []struct {
+		k string
+		v any
+	}{
+		{in.Key, in.Value},
+		{in.Value, in.Float},
+		{slog.ErrorFieldName, in.Int},
+	} {
+		if kv.k != "" {
+			chained[kv.k] = kv.v
+		}
+	}
+
+	fields := fuzzFields(in)
+	delete(fields, "")
+
+	return []Message{
+		{Level: level, Message: text},
+		{Level: level, Message: strings.TrimSpace(fmt.Sprintln(in.Message, in.Value))},
+		{Level: level, Message: strings.TrimSpace(
+			fmt.Sprintf(in.Message, in.Value, in.Int, in.Float))},
+		{Level: level, Message: text, Fields: chained},
+		{Level: level, Message: text, Fields: fields},
+	}
+}
@@ -0,0 +1,32 @@
+package testing
+
+import (
+	"testing"
+
+	"darvaza.org/slog"
+)
+
+func FuzzRecorder(f *testing.F) {
+	FuzzLoggerInputs(f, identity)
+}
+
+func TestCheckFuzzInputRoundTrip(t *testing.T) {
+	in := FuzzSeeds()[1]
+
+	// a handler losing the fields
+	lossy := func(parent slog.Logger) slog.Logger {
+		return &forgetful{Logger: parent}
+	}
+
+	ft := new(failT)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		CheckFuzzInput(ft, lossy, in)
+	}()
+	<-done
+
+	if len(ft.errors) != 1 {
+		t.Errorf("lost fields not detected: %q", ft.errors)
+	}
+}
@@ -0,0 +1,120 @@
+package testing
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+// LatencyStats collects the duration of individual operations
+// and reports their distribution. It's safe for concurrent use.
+type LatencyStats struct {
+	mu      sync.Mutex
+	samples []time.Duration
+	sorted  bool
+}
+
+// Record adds a sample
+func (s *LatencyStats) Record(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.samples = append(s.samples, d)
+	s.sorted = false
+}
+
+// Measure calls the function and records how long it took
+func (s *LatencyStats) Measure(fn func()) {
+	start := time.Now()
+	fn()
+	s.Record(time.Since(start))
+}
+
+// Count returns the number of samples
+func (s *LatencyStats) Count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.samples)
+}
+
+// Percentile returns the smallest sample greater or equal than
+// the given percentage, between 0 and 100, of all samples.
+func (s *LatencyStats) Percentile(p float64) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.percentile(p)
+}
+
+func (s *LatencyStats) percentile(p float64) time.Duration {
+	n := len(s.samples)
+	if n == 0 {
+		return 0
+	}
+
+	if !s.sorted {
+		sort.Slice(s.samples, func(i, j int) bool {
+			return s.samples[i] < s.samples[j]
+		})
+		s.sorted = true
+	}
+
+	// nearest-rank
+	rank := int(math.Ceil(p / 100 * float64(n)))
+	rank = max(1, min(n, rank))
+	return s.samples[rank-1]
+}
+
+// Max returns the largest sample
+func (s *LatencyStats) Max() time.Duration {
+	return s.Percentile(100)
+}
+
+// String summarises the distribution of the samples
+func (s *LatencyStats) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return fmt.Sprintf("n=%d p50=%s p95=%s p99=%s max=%s",
+		len(s.samples),
+		s.percentile(50), s.percentile(95),
+		s.percentile(99), s.percentile(100))
+}
+
+// LatencyBudget sets the maximum acceptable latencies.
+// Zero values aren't checked.
+type LatencyBudget struct {
+	P50 time.Duration
+	P95 time.Duration
+	P99 time.Duration
+	Max time.Duration
+}
+
+// Check fails the test if any percentile of the stats
+// exceeds the budget
+func (b LatencyBudget) Check(t testing.TB, stats *LatencyStats) {
+	t.Helper()
+
+	for _, c := range []struct {
+		name   string
+		budget time.Duration
+		p      float64
+	}{
+		{"p50", b.P50, 50},
+		{"p95", b.P95, 95},
+		{"p99", b.P99, 99},
+		{"max", b.Max, 100},
+	} {
+		if c.budget <= 0 {
+			continue
+		}
+
+		if d := stats.Percentile(c.p); d > c.budget {
+			t.Errorf("latency %s %s exceeds budget of %s", c.name, d, c.budget)
+		}
+	}
+}
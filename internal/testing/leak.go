@@ -0,0 +1,101 @@
+package testing
+
+import (
+	"runtime"
+	"testing"
+	"time"
+)
+
+const (
+	// DefaultLeakSettleTime is how long CheckLeaks waits for
+	// goroutines and memory to return to the baseline
+	DefaultLeakSettleTime = time.Second
+	// DefaultHeapTolerance is the heap growth, in bytes, CheckLeaks
+	// accepts by default
+	DefaultHeapTolerance = 1 << 20
+
+	leakPollInterval = 10 * time.Millisecond
+)
+
+// LeakOptions configures CheckLeaks
+type LeakOptions struct {
+	// Settle is how long to wait for the counts to return to the
+	// baseline. Defaults to DefaultLeakSettleTime
+	Settle time.Duration
+	// HeapTolerance is the acceptable heap growth in bytes.
+	// Defaults to DefaultHeapTolerance
+	HeapTolerance uint64
+	// GoroutineTolerance is the acceptable number of extra goroutines
+	GoroutineTolerance int
+}
+
+func (opts *LeakOptions) setDefaults() {
+	if opts.Settle <= 0 {
+		opts.Settle = DefaultLeakSettleTime
+	}
+	if opts.HeapTolerance == 0 {
+		opts.HeapTolerance = DefaultHeapTolerance
+	}
+}
+
+type leakSnapshot struct {
+	heap       uint64
+	goroutines int
+}
+
+func takeLeakSnapshot() leakSnapshot {
+	var m runtime.MemStats
+
+	runtime.GC()
+	runtime.ReadMemStats(&m)
+
+	return leakSnapshot{
+		heap:       m.HeapAlloc,
+		goroutines: runtime.NumGoroutine(),
+	}
+}
+
+// CheckLeaks takes a baseline of the number of goroutines and the
+// heap size, and returns a function that fails the test if they
+// don't return to it, within tolerance, after garbage collecting
+// and allowing goroutines to finish.
+func CheckLeaks(t testing.TB, opts *LeakOptions) func() {
+	var o LeakOptions
+	if opts != nil {
+		o = *opts
+	}
+	o.setDefaults()
+
+	base := takeLeakSnapshot()
+
+	return func() {
+		t.Helper()
+
+		var now leakSnapshot
+		deadline := time.Now().Add(o.Settle)
+		for {
+			now = takeLeakSnapshot()
+			if o.within(base, now) || time.Now().After(deadline) {
+				break
+			}
+			time.Sleep(leakPollInterval)
+		}
+
+		if n := now.goroutines - base.goroutines; n > o.GoroutineTolerance {
+			buf := make([]byte, 1<<16)
+			buf = buf[:runtime.Stack(buf, true)]
+			t.Errorf("%d goroutines leaked (tolerance %d)\n%s",
+				n, o.GoroutineTolerance, buf)
+		}
+
+		if now.heap > base.heap+o.HeapTolerance {
+			t.Errorf("heap grew by %d bytes (tolerance %d)",
+				now.heap-base.heap, o.HeapTolerance)
+		}
+	}
+}
+
+func (o LeakOptions) within(base, now leakSnapshot) bool {
+	return now.goroutines-base.goroutines <= o.GoroutineTolerance &&
+		now.heap <= base.heap+o.HeapTolerance
+}
@@ -0,0 +1,247 @@
+package testing
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	"darvaza.org/slog"
+)
+
+const (
+	// DefaultPropertyRuns is the number of random chains
+	// CheckProperties tries
+	DefaultPropertyRuns = 200
+	// maxPropertyOps is the maximum length of a random chain
+	maxPropertyOps = 12
+)
+
+// propertyKeys is a small set of keys, so chains override fields
+var propertyKeys = []string{"a", "b", "c", "d", "e"}
+
+type propertyOpKind int
+
+const (
+	opWithField propertyOpKind = iota
+	opWithFields
+	opWithLevel
+	opWithStack
+
+	opKinds
+)
+
+// propertyOp is one derivation applied by CheckProperties
+type propertyOp struct {
+	fields map[string]any
+	key    string
+	kind   propertyOpKind
+	value  int
+	level  slog.LogLevel
+}
+
+func (op propertyOp) String() string {
+	switch op.kind {
+	case opWithField:
+		return fmt.Sprintf("WithField(%q, %d)", op.key, op.value)
+	case opWithFields:
+		return fmt.Sprintf("WithFields(%v)", op.fields)
+	case opWithLevel:
+		return fmt.Sprintf("WithLevel(%s)", op.level)
+	default:
+		return "WithStack(0)"
+	}
+}
+
+// propertyChain is a random sequence of derivations,
+// generated by testing/quick
+type propertyChain []propertyOp
+
+// Generate implements quick.Generator
+func (propertyChain) Generate(r *rand.Rand, _ int) reflect.Value {
+	chain := make(propertyChain, 1+r.Intn(maxPropertyOps))
+	for i := range chain {
+		chain[i] = randomOp(r)
+	}
+	return reflect.ValueOf(chain)
+}
+
+func randomOp(r *rand.Rand) propertyOp {
+	op := propertyOp{kind: propertyOpKind(r.Intn(int(opKinds)))}
+
+	switch op.kind {
+	case opWithField:
+		op.key = propertyKeys[r.Intn(len(propertyKeys))]
+		op.value = r.Int()
+	case opWithFields:
+		op.fields = make(map[string]any)
+		for n := 1 + r.Intn(3); n > 0; n-- {
+			op.fields[propertyKeys[r.Intn(len(propertyKeys))]] = r.Int()
+		}
+	case opWithLevel:
+		op.level = fuzzLevels[r.Intn(len(fuzzLevels))]
+	}
+	return op
+}
+
+func (chain propertyChain) String() string {
+	s := make([]string, len(chain))
+	for i, op := range chain {
+		s[i] = op.String()
+	}
+	return strings.Join(s, ".")
+}
+
+// propertyState is what a logger of the chain is expected to emit
+type propertyState struct {
+	fields map[string]any
+	level  slog.LogLevel
+	stack  bool
+}
+
+func (st propertyState) apply(op propertyOp) propertyState {
+	out := propertyState{
+		fields: slog.Fields(st.fields).Clone(),
+		level:  st.level,
+		stack:  st.stack,
+	}
+	if out.fields == nil {
+		out.fields = make(map[string]any)
+	}
+
+	switch op.kind {
+	case opWithField:
+		out.fields[op.key] = op.value
+	case opWithFields:
+		for k, v := range op.fields {
+			out.fields[k] = v
+		}
+	case opWithLevel:
+		out.level = op.level
+	case opWithStack:
+		out.stack = true
+	}
+	return out
+}
+
+func deriveOp(l slog.Logger, op propertyOp) slog.Logger {
+	switch op.kind {
+	case opWithField:
+		return l.WithField(op.key, op.value)
+	case opWithFields:
+		// handlers may keep or modify the map
+		return l.WithFields(slog.Fields(op.fields).Clone())
+	case opWithLevel:
+		return l.WithLevel(op.level)
+	default:
+		return l.WithStack(0)
+	}
+}
+
+// CheckProperties applies random chains of WithField, WithFields,
+// WithLevel and WithStack to the handlers created by the factory
+// on top of a Recorder, failing the test if what it passes on
+// breaks these invariants:
+//
+//   - fields set last override earlier ones with the same key
+//   - derived loggers never carry fewer fields than their parents
+//   - the level is the one set last, and stacks are kept once attached
+//   - deriving a logger doesn't change its parent
+//
+// Handlers may add fields of their own. It's meant for handlers
+// passing every entry and field on.
+func CheckProperties(t *testing.T, factory HandlerFactory) {
+	t.Helper()
+
+	var failure string
+	check := func(chain propertyChain) bool {
+		failure = checkChain(factory, chain)
+		return failure == ""
+	}
+
+	err := quick.Check(check, &quick.Config{MaxCount: DefaultPropertyRuns})
+	if ce, ok := err.(*quick.CheckError); ok {
+		t.Errorf("chain #%d: %s", ce.Count, failure)
+	} else if err != nil {
+		t.Error(err)
+	}
+}
+
+// checkChain applies the chain, printing after every step, then
+// prints again on every logger, returning what failed, if anything
+func checkChain(factory HandlerFactory, chain propertyChain) string {
+	rec := NewRecorder()
+	base := factory(rec).WithLevel(slog.Info)
+
+	loggers := make([]slog.Logger, 0, len(chain)+1)
+	states := make([]propertyState, 0, len(chain)+1)
+	loggers = append(loggers, base)
+	states = append(states, propertyState{level: slog.Info})
+
+	for _, op := range chain {
+		loggers = append(loggers, deriveOp(loggers[len(loggers)-1], op))
+		states = append(states, states[len(states)-1].apply(op))
+	}
+
+	first := printAll(rec, loggers)
+	second := printAll(rec, loggers)
+
+	prevCount := -1
+	for i := range loggers {
+		m, ok := first[i]
+		if !ok {
+			continue
+		}
+
+		if s := states[i].check(m); s != "" {
+			return fmt.Sprintf("%s: step %d: %s", chain, i, s)
+		}
+		if len(m.Fields) < prevCount {
+			return fmt.Sprintf("%s: step %d: %d fields after %d", chain, i,
+				len(m.Fields), prevCount)
+		}
+		prevCount = len(m.Fields)
+
+		if d := CompareMessages([]Message{m}, []Message{second[i]}); !d.Equal() {
+			return fmt.Sprintf("%s: step %d changed by its children:\n%s", chain, i, d)
+		}
+	}
+	return ""
+}
+
+// printAll prints on every enabled logger, returning what the
+// Recorder got for each of them by index
+func printAll(rec *Recorder, loggers []slog.Logger) map[int]Message {
+	out := make(map[int]Message, len(loggers))
+	for i, l := range loggers {
+		if !l.Enabled() {
+			continue
+		}
+
+		rec.Reset()
+		l.Print(i)
+		if msgs := rec.Messages(); len(msgs) == 1 {
+			out[i] = msgs[0]
+		}
+	}
+	return out
+}
+
+func (st propertyState) check(m Message) string {
+	if m.Level != st.level {
+		return fmt.Sprintf("level %s, expected %s", m.Level, st.level)
+	}
+	if st.stack && len(m.Stack) == 0 {
+		return "stack lost"
+	}
+	for k, v := range st.fields {
+		if x, ok := m.Fields[k]; !ok {
+			return fmt.Sprintf("field %q lost", k)
+		} else if !SameValue(x, v) {
+			return fmt.Sprintf("field %q is %v, expected %v", k, x, v)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,66 @@
+package testing
+
+import (
+	"testing"
+
+	"darvaza.org/slog"
+)
+
+func TestCheckProperties(t *testing.T) {
+	CheckProperties(t, identity)
+}
+
+func TestCheckChainFailures(t *testing.T) {
+	chain := propertyChain{
+		{kind: opWithField, key: "a", value: 1},
+		{kind: opWithField, key: "a", value: 2},
+	}
+
+	for name, factory := range map[string]HandlerFactory{
+		"forgetful": func(parent slog.Logger) slog.Logger {
+			return &forgetful{Logger: parent}
+		},
+		"first wins": func(parent slog.Logger) slog.Logger {
+			return &firstWins{Logger: parent}
+		},
+	} {
+		if s := checkChain(factory, chain); s == "" {
+			t.Errorf("%s: not detected", name)
+		}
+	}
+}
+
+// forgetful drops the fields
+type forgetful struct {
+	slog.Logger
+}
+
+func (l *forgetful) WithLevel(level slog.LogLevel) slog.Logger {
+	return &forgetful{Logger: l.Logger.WithLevel(level)}
+}
+
+func (l *forgetful) WithField(string, any) slog.Logger {
+	return l
+}
+
+// firstWins ignores fields already set
+type firstWins struct {
+	slog.Logger
+	keys map[string]bool
+}
+
+func (l *firstWins) WithLevel(level slog.LogLevel) slog.Logger {
+	return &firstWins{Logger: l.Logger.WithLevel(level), keys: l.keys}
+}
+
+func (l *firstWins) WithField(label string, value any) slog.Logger {
+	if l.keys[label] {
+		return l
+	}
+
+	keys := map[string]bool{label: true}
+	for k := range l.keys {
+		keys[k] = true
+	}
+	return &firstWins{Logger: l.Logger.WithField(label, value), keys: keys}
+}
@@ -0,0 +1,230 @@
+package testing
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"darvaza.org/core"
+	"darvaza.org/slog"
+	"darvaza.org/slog/internal"
+)
+
+var (
+	_ slog.Logger      = (*Recorder)(nil)
+	_ slog.NamedLogger = (*Recorder)(nil)
+	_ slog.StackLogger = (*Recorder)(nil)
+	_ slog.TimedLogger = (*Recorder)(nil)
+)
+
+// Message is an entry captured by a Recorder
+type Message struct {
+	// Time is when the event happened, as given by WithTime(),
+	// or when it was recorded otherwise
+	Time    time.Time
+	Fields  map[string]any
+	Stack   core.Stack
+	Message string
+	Level   slog.LogLevel
+}
+
+// String describes the message, for test failures
+func (m Message) String() string {
+	var b strings.Builder
+
+	_, _ = fmt.Fprintf(&b, "%s %q", m.Level, m.Message)
+	if len(m.Fields) > 0 {
+		// maps are printed sorted by key
+		_, _ = fmt.Fprintf(&b, " %v", m.Fields)
+	}
+	if len(m.Stack) > 0 {
+		b.WriteString(" with stack")
+	}
+	return b.String()
+}
+
+// Recorder is a slog.Logger keeping in memory the entries it receives,
+// to be inspected by tests. All levels are enabled, and Fatal and
+// Panic entries are recorded like any other, without terminating.
+// Recorders created by NewDiscardRecorder() only count them, to be
+// used as parent by benchmarks.
+type Recorder struct {
+	internal.Loglet
+
+	r *recorder
+}
+
+type recorder struct {
+	mu       sync.Mutex
+	messages []Message
+	count    int
+	discard  bool
+}
+
+// NewRecorder creates a new Recorder
+func NewRecorder() *Recorder {
+	return &Recorder{
+		r: new(recorder),
+	}
+}
+
+// NewDiscardRecorder creates a new Recorder that
+// counts the entries but doesn't keep them
+func NewDiscardRecorder() *Recorder {
+	return &Recorder{
+		r: &recorder{discard: true},
+	}
+}
+
+// Count returns the number of entries recorded so far
+func (l *Recorder) Count() int {
+	l.r.mu.Lock()
+	defer l.r.mu.Unlock()
+
+	return l.r.count
+}
+
+// Messages returns a copy of the entries recorded so far
+func (l *Recorder) Messages() []Message {
+	l.r.mu.Lock()
+	defer l.r.mu.Unlock()
+
+	out := make([]Message, len(l.r.messages))
+	copy(out, l.r.messages)
+	return out
+}
+
+// Reset forgets the entries recorded so far
+func (l *Recorder) Reset() {
+	l.r.mu.Lock()
+	defer l.r.mu.Unlock()
+
+	l.r.messages = nil
+	l.r.count = 0
+}
+
+func (l *Recorder) record(msg string) {
+	if l.r.discard {
+		l.r.mu.Lock()
+		l.r.count++
+		l.r.mu.Unlock()
+		return
+	}
+
+	t := l.Time()
+	if t.IsZero() {
+		t = time.Now()
+	}
+
+	m := Message{
+		Time:    t,
+		Message: strings.TrimSpace(msg),
+		Level:   l.Level(),
+		Fields:  l.FieldsMap(),
+		Stack:   l.CallStack(),
+	}
+
+	l.r.mu.Lock()
+	defer l.r.mu.Unlock()
+
+	l.r.messages = append(l.r.messages, m)
+	l.r.count++
+}
+
+func (l *Recorder) dup(ll internal.Loglet) *Recorder {
+	return &Recorder{
+		Loglet: ll,
+		r:      l.r,
+	}
+}
+
+// Enabled tells this logger is enabled
+func (*Recorder) Enabled() bool {
+	return true
+}
+
+// WithEnabled passes the logger and if it's enabled
+func (l *Recorder) WithEnabled() (slog.Logger, bool) {
+	return l, true
+}
+
+// Print records an entry with arguments handled in the manner of fmt.Print
+func (l *Recorder) Print(args ...any) {
+	l.record(fmt.Sprint(args...))
+}
+
+// Println records an entry with arguments handled in the manner of fmt.Println
+func (l *Recorder) Println(args ...any) {
+	l.record(fmt.Sprintln(args...))
+}
+
+// Printf records an entry with arguments handled in the manner of fmt.Printf
+func (l *Recorder) Printf(format string, args ...any) {
+	l.record(internal.Sprintf(format, args...))
+}
+
+// Debug returns a new logger set to record entries as level Debug
+func (l *Recorder) Debug() slog.Logger {
+	return l.WithLevel(slog.Debug)
+}
+
+// Info returns a new logger set to record entries as level Info
+func (l *Recorder) Info() slog.Logger {
+	return l.WithLevel(slog.Info)
+}
+
+// Warn returns a new logger set to record entries as level Warn
+func (l *Recorder) Warn() slog.Logger {
+	return l.WithLevel(slog.Warn)
+}
+
+// Error returns a new logger set to record entries as level Error
+func (l *Recorder) Error() slog.Logger {
+	return l.WithLevel(slog.Error)
+}
+
+// Fatal returns a new logger set to record entries as level Fatal
+func (l *Recorder) Fatal() slog.Logger {
+	return l.WithLevel(slog.Fatal)
+}
+
+// Panic returns a new logger set to record entries as level Panic
+func (l *Recorder) Panic() slog.Logger {
+	return l.WithLevel(slog.Panic)
+}
+
+// WithLevel returns a new logger set to record entries to the specified level
+func (l *Recorder) WithLevel(level slog.LogLevel) slog.Logger {
+	return l.dup(l.Loglet.WithLevel(level))
+}
+
+// WithStack attaches a call stack to a new logger
+func (l *Recorder) WithStack(skip int) slog.Logger {
+	return l.dup(l.Loglet.WithStack(skip + 1))
+}
+
+// WithCallStack attaches a given call stack to a new logger
+func (l *Recorder) WithCallStack(st core.Stack) slog.Logger {
+	return l.dup(l.Loglet.WithCallStack(st))
+}
+
+// WithTime records when the event happened on a new logger
+func (l *Recorder) WithTime(t time.Time) slog.Logger {
+	return l.dup(l.Loglet.WithTime(t))
+}
+
+// WithName attaches a name to a new logger
+func (l *Recorder) WithName(name string) slog.Logger {
+	return l.dup(l.Loglet.WithName(name))
+}
+
+// WithField returns a new logger with a field attached
+func (l *Recorder) WithField(label string, value any) slog.Logger {
+	return l.dup(l.Loglet.WithField(label, value))
+}
+
+// WithFields returns a new logger with a set of fields attached
+func (l *Recorder) WithFields(fields map[string]any) slog.Logger {
+	return l.dup(l.Loglet.WithFields(fields))
+}
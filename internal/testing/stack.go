@@ -0,0 +1,89 @@
+package testing
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"darvaza.org/core"
+)
+
+// FrameMatcher describes a call stack frame to look for.
+// Empty fields match any frame.
+type FrameMatcher struct {
+	// Func is the name of the function, with as much of its
+	// package as desired, e.g. "TestX", "testing.TestX" or
+	// "darvaza.org/slog/internal/testing.TestX"
+	Func string
+	// File is the name of the source file, with as much of
+	// its path as desired
+	File string
+}
+
+// Match tells if the frame meets the FrameMatcher
+func (fm FrameMatcher) Match(f core.Frame) bool {
+	return matchSuffix(f.Name(), fm.Func, "./") &&
+		matchSuffix(f.File(), fm.File, "/")
+}
+
+// matchSuffix tells if s ends with the given suffix, starting
+// at the beginning of s or after one of the separators
+func matchSuffix(s, suffix, separators string) bool {
+	switch {
+	case suffix == "" || s == suffix:
+		return true
+	case !strings.HasSuffix(s, suffix):
+		return false
+	default:
+		c := s[len(s)-len(suffix)-1]
+		return strings.IndexByte(separators, c) >= 0
+	}
+}
+
+// String describes the FrameMatcher, for test failures
+func (fm FrameMatcher) String() string {
+	switch {
+	case fm.Func == "" && fm.File == "":
+		return "any frame"
+	case fm.File == "":
+		return fmt.Sprintf("func %q", fm.Func)
+	case fm.Func == "":
+		return fmt.Sprintf("file %q", fm.File)
+	default:
+		return fmt.Sprintf("func %q in file %q", fm.Func, fm.File)
+	}
+}
+
+// AssertStackTop checks the message carries a call stack whose
+// innermost frame meets the FrameMatcher, e.g. the function that
+// called WithStack(0)
+func AssertStackTop(t testing.TB, m Message, fm FrameMatcher) bool {
+	t.Helper()
+
+	switch {
+	case len(m.Stack) == 0:
+		t.Errorf("%s: no stack, expected %s", m, fm)
+		return false
+	case !fm.Match(m.Stack[0]):
+		t.Errorf("%s: stack starts at %s (%s), expected %s", m,
+			m.Stack[0].Name(), m.Stack[0].FileLine(), fm)
+		return false
+	default:
+		return true
+	}
+}
+
+// AssertStackContains checks the message carries a call stack
+// with a frame meeting the FrameMatcher
+func AssertStackContains(t testing.TB, m Message, fm FrameMatcher) bool {
+	t.Helper()
+
+	for _, f := range m.Stack {
+		if fm.Match(f) {
+			return true
+		}
+	}
+
+	t.Errorf("%s: no frame matching %s in stack:%+v", m, fm, m.Stack)
+	return false
+}
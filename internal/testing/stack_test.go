@@ -0,0 +1,70 @@
+package testing
+
+import (
+	"testing"
+)
+
+func TestFrameMatcher(t *testing.T) {
+	rec := NewRecorder()
+	rec.WithStack(0).Print("here")
+
+	m := rec.Messages()[0]
+	top := m.Stack[0]
+
+	for _, fm := range []FrameMatcher{
+		{},
+		{Func: "TestFrameMatcher"},
+		{Func: "testing.TestFrameMatcher"},
+		{Func: top.Name()},
+		{File: "stack_test.go"},
+		{File: "internal/testing/stack_test.go", Func: "TestFrameMatcher"},
+	} {
+		if !fm.Match(top) {
+			t.Errorf("%s doesn't match %s", fm, top.Name())
+		}
+	}
+
+	for _, fm := range []FrameMatcher{
+		{Func: "FrameMatcher"},
+		{Func: "TestFrameMatcher.func1"},
+		{File: "_test.go"},
+		{File: "stack.go"},
+	} {
+		if fm.Match(top) {
+			t.Errorf("%s matches %s", fm, top.Name())
+		}
+	}
+}
+
+func TestAssertStack(t *testing.T) {
+	rec := NewRecorder()
+	rec.WithStack(0).Print("stack")
+	rec.Print("no stack")
+
+	msgs := rec.Messages()
+	here := FrameMatcher{Func: "TestAssertStack", File: "stack_test.go"}
+
+	AssertStackTop(t, msgs[0], here)
+	AssertStackContains(t, msgs[0], FrameMatcher{Func: "testing.tRunner"})
+
+	ft := new(failT)
+	AssertStackTop(ft, msgs[1], here)
+	AssertStackTop(ft, msgs[0], FrameMatcher{Func: "tRunner"})
+	AssertStackContains(ft, msgs[0], FrameMatcher{File: "missing.go"})
+	if len(ft.errors) != 3 {
+		t.Errorf("expected 3 failures, got %q", ft.errors)
+	}
+}
+
+func TestExpectStackTop(t *testing.T) {
+	rec := NewRecorder()
+	rec.WithStack(0).Print("stack")
+
+	here := FrameMatcher{Func: "TestExpectStackTop"}
+	AssertMessagesMatch(t, rec.Messages(), Expect().StackTop(here))
+
+	if reasons := Expect().StackTop(FrameMatcher{Func: "elsewhere"}).
+		Match(rec.Messages()[0]); len(reasons) != 1 {
+		t.Errorf("unexpected reasons %q", reasons)
+	}
+}
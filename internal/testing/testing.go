@@ -0,0 +1,14 @@
+// Package testing provides helpers to test and benchmark
+// slog.Logger implementations
+package testing
+
+import (
+	"darvaza.org/slog"
+)
+
+// LoggerFactory creates a new slog.Logger to be tested
+type LoggerFactory func() slog.Logger
+
+// HandlerFactory creates the slog.Logger to be tested on top of
+// the given parent, e.g. a Recorder observing what it passes on
+type HandlerFactory func(parent slog.Logger) slog.Logger
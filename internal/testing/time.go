@@ -0,0 +1,41 @@
+package testing
+
+import (
+	"testing"
+	"time"
+)
+
+// AssertOrdered checks the times of the messages never go
+// backwards, e.g. when delivered by asynchronous handlers
+func AssertOrdered(t testing.TB, msgs []Message) bool {
+	t.Helper()
+
+	ok := true
+	for i := 1; i < len(msgs); i++ {
+		if prev, cur := msgs[i-1].Time, msgs[i].Time; cur.Before(prev) {
+			t.Errorf("#%d: %s at %s, before #%d at %s", i, msgs[i],
+				cur.Format(time.RFC3339Nano), i-1, prev.Format(time.RFC3339Nano))
+			ok = false
+		}
+	}
+	return ok
+}
+
+// AssertWithin checks the times of the messages fall within
+// the given duration after start, e.g. to bound the latency
+// of a handler
+func AssertWithin(t testing.TB, msgs []Message, start time.Time, d time.Duration) bool {
+	t.Helper()
+
+	end := start.Add(d)
+
+	ok := true
+	for i, m := range msgs {
+		if m.Time.Before(start) || m.Time.After(end) {
+			t.Errorf("#%d: %s at %s, %s after start, outside %s", i, m,
+				m.Time.Format(time.RFC3339Nano), m.Time.Sub(start), d)
+			ok = false
+		}
+	}
+	return ok
+}
@@ -0,0 +1,61 @@
+package testing
+
+import (
+	"testing"
+	"time"
+
+	"darvaza.org/slog"
+)
+
+func TestRecorderTime(t *testing.T) {
+	rec := NewRecorder()
+	start := time.Now()
+	event := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	rec.Print("now")
+	slog.WithTime(rec, event).Print("event")
+
+	msgs := rec.Messages()
+	AssertWithin(t, msgs[:1], start, time.Minute)
+	if !msgs[1].Time.Equal(event) {
+		t.Errorf("expected %s, got %s", event, msgs[1].Time)
+	}
+}
+
+func TestAssertOrdered(t *testing.T) {
+	t0 := time.Unix(1000, 0)
+	msgs := []Message{
+		{Time: t0, Message: "a"},
+		{Time: t0, Message: "b"},
+		{Time: t0.Add(time.Second), Message: "c"},
+	}
+
+	if !AssertOrdered(t, msgs) {
+		t.Error("ordered messages rejected")
+	}
+
+	msgs[0].Time = t0.Add(time.Minute)
+	ft := new(failT)
+	if AssertOrdered(ft, msgs) || len(ft.errors) != 1 {
+		t.Errorf("disorder not reported: %q", ft.errors)
+	}
+}
+
+func TestAssertWithin(t *testing.T) {
+	t0 := time.Unix(1000, 0)
+	msgs := []Message{
+		{Time: t0.Add(-time.Second), Message: "early"},
+		{Time: t0, Message: "start"},
+		{Time: t0.Add(time.Second), Message: "end"},
+		{Time: t0.Add(2 * time.Second), Message: "late"},
+	}
+
+	if !AssertWithin(t, msgs[1:3], t0, time.Second) {
+		t.Error("messages within bounds rejected")
+	}
+
+	ft := new(failT)
+	if AssertWithin(ft, msgs, t0, time.Second) || len(ft.errors) != 2 {
+		t.Errorf("expected 2 failures, got %q", ft.errors)
+	}
+}
@@ -0,0 +1,278 @@
+package testing
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"darvaza.org/core"
+	"darvaza.org/slog"
+)
+
+var (
+	_ slog.Logger        = (*transcriptLogger)(nil)
+	_ slog.NamedLogger   = (*transcriptLogger)(nil)
+	_ slog.StackLogger   = (*transcriptLogger)(nil)
+	_ slog.TimedLogger   = (*transcriptLogger)(nil)
+	_ slog.ContextLogger = (*transcriptLogger)(nil)
+)
+
+// TranscriptStep is a method called on one of the loggers
+// of a Transcript
+type TranscriptStep struct {
+	// Method is the name of the slog.Logger method
+	Method string
+	// Args are the arguments the method was called with
+	Args []any
+	// Logger is the index of the logger the method was called on,
+	// the one given to RecordTranscript being 0
+	Logger int
+	// Result is the index given to the logger returned by the
+	// method, or -1 if it doesn't return one
+	Result int
+}
+
+// Transcript is the sequence of calls made on a logger and on
+// those derived from it, to be replayed against other handlers
+type Transcript struct {
+	mu      sync.Mutex
+	steps   []TranscriptStep
+	loggers int
+}
+
+// RecordTranscript wraps a logger so the calls made on it, and on
+// the loggers derived from it, are appended to the returned Transcript
+func RecordTranscript(l slog.Logger) (slog.Logger, *Transcript) {
+	tr := &Transcript{loggers: 1}
+	return &transcriptLogger{tr: tr, l: l}, tr
+}
+
+// NewTranscript records the calls the script makes on the
+// logger it's given, which discards the entries
+func NewTranscript(script func(slog.Logger)) *Transcript {
+	l, tr := RecordTranscript(NewDiscardRecorder())
+	script(l)
+	return tr
+}
+
+// Steps returns a copy of the calls recorded so far
+func (tr *Transcript) Steps() []TranscriptStep {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	out := make([]TranscriptStep, len(tr.steps))
+	copy(out, tr.steps)
+	return out
+}
+
+// add appends a step, returning the index given to
+// the resulting logger if derives is true
+func (tr *Transcript) add(from int, derives bool, method string, args ...any) int {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	result := -1
+	if derives {
+		result = tr.loggers
+		tr.loggers++
+	}
+
+	tr.steps = append(tr.steps, TranscriptStep{
+		Method: method,
+		Args:   args,
+		Logger: from,
+		Result: result,
+	})
+	return result
+}
+
+// Replay repeats the recorded calls on the given logger
+// and the ones derived from it
+func (tr *Transcript) Replay(l slog.Logger) {
+	loggers := []slog.Logger{l}
+
+	for _, step := range tr.Steps() {
+		next := replayStep(loggers[step.Logger], step)
+		if step.Result >= 0 {
+			loggers = append(loggers, next)
+		}
+	}
+}
+
+// Messages replays the Transcript on the logger the factory
+// creates on top of a new Recorder, returning what it recorded
+func (tr *Transcript) Messages(factory HandlerFactory) []Message {
+	rec := NewRecorder()
+	tr.Replay(factory(rec))
+	return rec.Messages()
+}
+
+// CheckTranscript replays the Transcript on the handlers created
+// by both factories, failing the test if the messages they pass
+// on differ. Messages are compared in order, as by CompareMessages.
+func CheckTranscript(t testing.TB, tr *Transcript, want, got HandlerFactory,
+	opts ...CompareOption) bool {
+	t.Helper()
+
+	opts = append([]CompareOption{Ordered()}, opts...)
+	d := CompareMessages(tr.Messages(want), tr.Messages(got), opts...)
+	if !d.Equal() {
+		t.Errorf("replayed transcript differs:\n%s", d)
+		return false
+	}
+	return true
+}
+
+func replayStep(l slog.Logger, step TranscriptStep) slog.Logger {
+	args := step.Args
+
+	switch step.Method {
+	case "Print":
+		l.Print(args...)
+	case "Println":
+		l.Println(args...)
+	case "Printf":
+		l.Printf(args[0].(string), args[1:]...)
+	case "WithEnabled":
+		next, _ := l.WithEnabled()
+		return next
+	case "WithLevel":
+		return l.WithLevel(args[0].(slog.LogLevel))
+	case "WithStack":
+		return l.WithStack(args[0].(int))
+	case "WithField":
+		return l.WithField(args[0].(string), args[1])
+	case "WithFields":
+		return l.WithFields(args[0].(map[string]any))
+	case "WithCallStack":
+		return slog.WithCallStack(l, args[0].(core.Stack))
+	case "WithName":
+		return slog.WithName(l, args[0].(string))
+	case "WithTime":
+		return slog.WithTime(l, args[0].(time.Time))
+	case "WithContext":
+		return slog.WithContext(args[0].(context.Context), l)
+	default:
+		panic(fmt.Errorf("transcript: unknown method %q", step.Method))
+	}
+	return nil
+}
+
+// transcriptLogger appends the calls made on it to a Transcript
+// before passing them to the logger it wraps
+type transcriptLogger struct {
+	tr *Transcript
+	l  slog.Logger
+	id int
+}
+
+func (tl *transcriptLogger) derive(l slog.Logger, method string, args ...any) slog.Logger {
+	return &transcriptLogger{
+		tr: tl.tr,
+		l:  l,
+		id: tl.tr.add(tl.id, true, method, args...),
+	}
+}
+
+// Enabled tells if the wrapped logger is enabled
+func (tl *transcriptLogger) Enabled() bool {
+	return tl.l.Enabled()
+}
+
+// WithEnabled records the call and passes it on
+func (tl *transcriptLogger) WithEnabled() (slog.Logger, bool) {
+	l, ok := tl.l.WithEnabled()
+	return tl.derive(l, "WithEnabled"), ok
+}
+
+// Print records the call and passes it on
+func (tl *transcriptLogger) Print(args ...any) {
+	tl.tr.add(tl.id, false, "Print", args...)
+	tl.l.Print(args...)
+}
+
+// Println records the call and passes it on
+func (tl *transcriptLogger) Println(args ...any) {
+	tl.tr.add(tl.id, false, "Println", args...)
+	tl.l.Println(args...)
+}
+
+// Printf records the call and passes it on
+func (tl *transcriptLogger) Printf(format string, args ...any) {
+	tl.tr.add(tl.id, false, "Printf", append([]any{format}, args...)...)
+	tl.l.Printf(format, args...)
+}
+
+// Debug records the call as WithLevel and passes it on
+func (tl *transcriptLogger) Debug() slog.Logger {
+	return tl.WithLevel(slog.Debug)
+}
+
+// Info records the call as WithLevel and passes it on
+func (tl *transcriptLogger) Info() slog.Logger {
+	return tl.WithLevel(slog.Info)
+}
+
+// Warn records the call as WithLevel and passes it on
+func (tl *transcriptLogger) Warn() slog.Logger {
+	return tl.WithLevel(slog.Warn)
+}
+
+// Error records the call as WithLevel and passes it on
+func (tl *transcriptLogger) Error() slog.Logger {
+	return tl.WithLevel(slog.Error)
+}
+
+// Fatal records the call as WithLevel and passes it on
+func (tl *transcriptLogger) Fatal() slog.Logger {
+	return tl.WithLevel(slog.Fatal)
+}
+
+// Panic records the call as WithLevel and passes it on
+func (tl *transcriptLogger) Panic() slog.Logger {
+	return tl.WithLevel(slog.Panic)
+}
+
+// WithLevel records the call and passes it on
+func (tl *transcriptLogger) WithLevel(level slog.LogLevel) slog.Logger {
+	return tl.derive(tl.l.WithLevel(level), "WithLevel", level)
+}
+
+// WithStack records the call and passes it on
+func (tl *transcriptLogger) WithStack(skip int) slog.Logger {
+	return tl.derive(tl.l.WithStack(skip+1), "WithStack", skip)
+}
+
+// WithCallStack records the call and passes it on
+func (tl *transcriptLogger) WithCallStack(st core.Stack) slog.Logger {
+	return tl.derive(slog.WithCallStack(tl.l, st), "WithCallStack", st)
+}
+
+// WithName records the call and passes it on
+func (tl *transcriptLogger) WithName(name string) slog.Logger {
+	return tl.derive(slog.WithName(tl.l, name), "WithName", name)
+}
+
+// WithTime records the call and passes it on
+func (tl *transcriptLogger) WithTime(t time.Time) slog.Logger {
+	return tl.derive(slog.WithTime(tl.l, t), "WithTime", t)
+}
+
+// WithContext records the call and passes it on
+func (tl *transcriptLogger) WithContext(ctx context.Context) slog.Logger {
+	return tl.derive(slog.WithContext(ctx, tl.l), "WithContext", ctx)
+}
+
+// WithField records the call and passes it on
+func (tl *transcriptLogger) WithField(label string, value any) slog.Logger {
+	return tl.derive(tl.l.WithField(label, value), "WithField", label, value)
+}
+
+// WithFields records the call and passes it on. The map is
+// copied, so later changes by the caller aren't replayed.
+func (tl *transcriptLogger) WithFields(fields map[string]any) slog.Logger {
+	return tl.derive(tl.l.WithFields(fields), "WithFields",
+		map[string]any(slog.Fields(fields).Clone()))
+}
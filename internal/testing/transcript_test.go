@@ -0,0 +1,70 @@
+package testing
+
+import (
+	"testing"
+	"time"
+
+	"darvaza.org/slog"
+)
+
+func transcriptScript(l slog.Logger) {
+	base := l.WithField("service", "test")
+	base.Info().Print("started")
+
+	req := slog.WithName(base, "http").WithFields(map[string]any{"id": 7})
+	req.Warn().Printf("slow %s", "request")
+	slog.WithTime(req.Error().WithStack(0), time.Unix(0, 0)).Println("failed")
+}
+
+func identity(parent slog.Logger) slog.Logger {
+	return parent
+}
+
+func TestTranscriptReplay(t *testing.T) {
+	tr := NewTranscript(transcriptScript)
+
+	steps := tr.Steps()
+	if len(steps) != 11 {
+		t.Fatalf("expected 11 steps, got %d: %+v", len(steps), steps)
+	}
+
+	AssertMessagesInOrder(t, tr.Messages(identity),
+		Expect().Level(slog.Info).Message("started").Field("service", "test"),
+		Expect().Level(slog.Warn).Message("slow request").
+			Field("id", 7).Field(slog.NameFieldName, "http"),
+		Expect().Level(slog.Error).Message("failed").Stack(true),
+	)
+}
+
+func TestCheckTranscript(t *testing.T) {
+	tr := NewTranscript(transcriptScript)
+
+	if !CheckTranscript(t, tr, identity, identity) {
+		t.Error("identical handlers differ")
+	}
+
+	// a handler losing the Info entries
+	lossy := func(parent slog.Logger) slog.Logger {
+		return &dropInfo{Logger: parent}
+	}
+	ft := new(failT)
+	if CheckTranscript(ft, tr, identity, lossy) || len(ft.errors) != 1 {
+		t.Errorf("difference not reported: %q", ft.errors)
+	}
+}
+
+// dropInfo discards the entries of level Info
+type dropInfo struct {
+	slog.Logger
+}
+
+func (l *dropInfo) WithLevel(level slog.LogLevel) slog.Logger {
+	if level == slog.Info {
+		return NewDiscardRecorder()
+	}
+	return &dropInfo{Logger: l.Logger.WithLevel(level)}
+}
+
+func (l *dropInfo) WithField(label string, value any) slog.Logger {
+	return &dropInfo{Logger: l.Logger.WithField(label, value)}
+}
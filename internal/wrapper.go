@@ -0,0 +1,433 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"darvaza.org/core"
+	"darvaza.org/slog"
+)
+
+var (
+	_ slog.Logger        = (*Wrapper)(nil)
+	_ slog.CallerLogger  = (*Wrapper)(nil)
+	_ slog.NamedLogger   = (*Wrapper)(nil)
+	_ slog.TimedLogger   = (*Wrapper)(nil)
+	_ slog.StackLogger   = (*Wrapper)(nil)
+	_ slog.ContextLogger = (*Wrapper)(nil)
+	_ slog.BatchLogger   = (*Wrapper)(nil)
+	_ slog.Unwrapper     = (*Wrapper)(nil)
+	_ slog.Flusher       = (*Wrapper)(nil)
+	_ slog.HealthChecker = (*Wrapper)(nil)
+	_ slog.StatsReporter = (*Wrapper)(nil)
+)
+
+// Entry is a log entry about to be passed to the parent of a Wrapper
+type Entry struct {
+	// Logger is the parent context the message will be printed on.
+	// Hooks can replace it, e.g. to attach more fields.
+	Logger slog.Logger
+	// Loglet is the context recorded by the Wrapper
+	Loglet *Loglet
+	// Message is the formatted message
+	Message string
+	// Format is the template given to Printf, if that was used
+	Format string
+	// Args are the arguments given to Print, Println or Printf
+	Args []any
+}
+
+// Level returns the level of the entry
+func (e *Entry) Level() slog.LogLevel {
+	return e.Loglet.Level()
+}
+
+// WrapperHooks allow a Wrapper to alter what reaches its parent
+type WrapperHooks struct {
+	// Field, if set, can modify or discard fields before
+	// they are passed to the parent
+	Field func(key string, value any) (string, any, bool)
+
+	// Fields, if set, is called after the Field hook with the
+	// context so far and the fields about to be attached, and
+	// returns those to be passed to the parent
+	Fields func(ll *Loglet, fields map[string]any) map[string]any
+
+	// Print, if set, is called when an enabled entry is printed,
+	// and for Fatal and Panic entries even when disabled. It can
+	// modify the Entry, or return false to discard it, except for
+	// Fatal and Panic entries, which are always passed on.
+	// The Entry is pooled and can't be retained after returning.
+	Print func(*Entry) bool
+
+	// Enabled, if set, can disable loggers the parent would
+	// consider enabled, based on their Loglet.
+	Enabled func(*Loglet) bool
+
+	// Batch, if set, is called by LogBatch() with the context and
+	// the parent, and returns false to have the entries emitted one
+	// by one through the Wrapper instead. The Field, Fields and Print
+	// hooks aren't called for batches it handles.
+	Batch func(ll *Loglet, parent slog.Logger, entries []slog.Entry) bool
+
+	// Flush and Close, if set, let slog.Flush() and slog.Close()
+	// reach the handler built around the Wrapper before its parent
+	Flush func(context.Context) error
+	Close func() error
+
+	// Health and Stats, if set, let slog.Health() and
+	// slog.CollectStats() reach the handler built around
+	// the Wrapper
+	Health func() error
+	Stats  func() slog.Stats
+}
+
+// recording tells if the context needs to be recorded on the
+// Loglet. Fields are only recorded when a Print, Enabled or
+// Fields hook is present.
+func (h *WrapperHooks) recording() bool {
+	return h.Print != nil || h.Enabled != nil || h.Fields != nil
+}
+
+// Wrapper is a slog.Logger that forwards everything to a parent
+// logger, recording the context on its Loglet and calling hooks
+// so the handler built around it can intervene.
+// Nothing is recorded, nor hooks called, when the parent is disabled.
+type Wrapper struct {
+	Loglet
+
+	parent slog.Logger
+	hooks  *WrapperHooks
+}
+
+// NewWrapper creates a Wrapper around the given parent
+func NewWrapper(parent slog.Logger, hooks *WrapperHooks) *Wrapper {
+	if parent == nil {
+		return nil
+	}
+	if hooks == nil {
+		hooks = &WrapperHooks{}
+	}
+
+	return &Wrapper{
+		parent: parent,
+		hooks:  hooks,
+	}
+}
+
+// Parent returns the current context of the parent logger
+func (w *Wrapper) Parent() slog.Logger {
+	return w.parent
+}
+
+// Unwrap returns the parent logger, so slog.Close() and
+// slog.Flush() can walk the chain
+func (w *Wrapper) Unwrap() slog.Logger {
+	return w.parent
+}
+
+// Flush calls the Flush hook, if any
+func (w *Wrapper) Flush(ctx context.Context) error {
+	if fn := w.hooks.Flush; fn != nil {
+		return fn(ctx)
+	}
+	return nil
+}
+
+// Close calls the Close hook, if any
+func (w *Wrapper) Close() error {
+	if fn := w.hooks.Close; fn != nil {
+		return fn()
+	}
+	return nil
+}
+
+// Health calls the Health hook, if any
+func (w *Wrapper) Health() error {
+	if fn := w.hooks.Health; fn != nil {
+		return fn()
+	}
+	return nil
+}
+
+// Stats calls the Stats hook, if any
+func (w *Wrapper) Stats() slog.Stats {
+	if fn := w.hooks.Stats; fn != nil {
+		return fn()
+	}
+	return nil
+}
+
+func (w *Wrapper) dup(ll Loglet, parent slog.Logger) *Wrapper {
+	return &Wrapper{
+		Loglet: ll,
+		parent: parent,
+		hooks:  w.hooks,
+	}
+}
+
+// Enabled tells if the parent logger is enabled, and
+// the Enabled hook agrees
+func (w *Wrapper) Enabled() bool {
+	if w == nil || w.parent == nil || !w.parent.Enabled() {
+		return false
+	}
+
+	if fn := w.hooks.Enabled; fn != nil {
+		return fn(&w.Loglet)
+	}
+	return true
+}
+
+// WithEnabled passes the logger and if it's enabled
+func (w *Wrapper) WithEnabled() (slog.Logger, bool) {
+	return w, w.Enabled()
+}
+
+// Print adds a log entry with arguments handled in the manner of fmt.Print
+func (w *Wrapper) Print(args ...any) {
+	if w.Enabled() || w.terminates() {
+		w.print(fmt.Sprint(args...), "", args)
+	}
+}
+
+// Println adds a log entry with arguments handled in the manner of fmt.Println
+func (w *Wrapper) Println(args ...any) {
+	if w.Enabled() || w.terminates() {
+		w.print(fmt.Sprintln(args...), "", args)
+	}
+}
+
+// Printf adds a log entry with arguments handled in the manner of fmt.Printf
+func (w *Wrapper) Printf(format string, args ...any) {
+	if w.Enabled() || w.terminates() {
+		w.print(Sprintf(format, args...), format, args)
+	}
+}
+
+// LogBatch passes the entries to the Batch hook, if any, or
+// emits them one by one otherwise
+func (w *Wrapper) LogBatch(entries []slog.Entry) {
+	if w == nil || w.parent == nil || len(entries) == 0 {
+		return
+	}
+
+	if fn := w.hooks.Batch; fn != nil && fn(&w.Loglet, w.parent, entries) {
+		return
+	}
+	slog.LogEach(w, entries)
+}
+
+// terminates tells if the entry is Fatal or Panic, which
+// the parent handles even when disabled
+func (w *Wrapper) terminates() bool {
+	if w == nil || w.parent == nil {
+		return false
+	}
+
+	switch w.Level() {
+	case slog.Fatal, slog.Panic:
+		return true
+	default:
+		return false
+	}
+}
+
+// print passes the entry through the Print hook to the parent.
+// Fatal and Panic entries reach the parent even if the hook
+// discards them, as modified by it.
+func (w *Wrapper) print(msg, format string, args []any) {
+	fn := w.hooks.Print
+	if fn == nil {
+		w.parent.Print(msg)
+		return
+	}
+
+	e := AcquireEntry()
+	e.Logger = w.parent
+	e.Loglet = &w.Loglet
+	e.Message = msg
+	e.Format = format
+	e.Args = args
+
+	if fn(e) || w.terminates() {
+		e.Logger.Print(e.Message)
+	}
+	e.Release()
+}
+
+// Debug returns a new logger set to add entries as level Debug
+func (w *Wrapper) Debug() slog.Logger {
+	return w.WithLevel(slog.Debug)
+}
+
+// Info returns a new logger set to add entries as level Info
+func (w *Wrapper) Info() slog.Logger {
+	return w.WithLevel(slog.Info)
+}
+
+// Warn returns a new logger set to add entries as level Warn
+func (w *Wrapper) Warn() slog.Logger {
+	return w.WithLevel(slog.Warn)
+}
+
+// Error returns a new logger set to add entries as level Error
+func (w *Wrapper) Error() slog.Logger {
+	return w.WithLevel(slog.Error)
+}
+
+// Fatal returns a new logger set to add entries as level Fatal
+func (w *Wrapper) Fatal() slog.Logger {
+	return w.WithLevel(slog.Fatal)
+}
+
+// Panic returns a new logger set to add entries as level Panic
+func (w *Wrapper) Panic() slog.Logger {
+	return w.WithLevel(slog.Panic)
+}
+
+// WithLevel returns a new logger set to add entries to the specified level
+func (w *Wrapper) WithLevel(level slog.LogLevel) slog.Logger {
+	if level <= slog.UndefinedLevel {
+		// fix your code
+		w.Panic().WithStack(1).Printf("slog: invalid log level %v", level)
+	} else if level == w.Level() {
+		return w
+	}
+
+	return w.dup(w.Loglet.WithLevel(level), w.parent.WithLevel(level))
+}
+
+// WithStack attaches a call stack to a new logger
+func (w *Wrapper) WithStack(skip int) slog.Logger {
+	if !w.Enabled() {
+		return w
+	}
+
+	return w.dup(w.Loglet.WithStack(skip+1), w.parent.WithStack(skip+1))
+}
+
+// WithCallStack attaches a given call stack to a new logger
+func (w *Wrapper) WithCallStack(st core.Stack) slog.Logger {
+	if !w.Enabled() {
+		return w
+	}
+
+	return w.dup(w.Loglet.WithCallStack(st), slog.WithCallStack(w.parent, st))
+}
+
+// WithContext records the context entries are logged on
+// behalf of on a new logger
+func (w *Wrapper) WithContext(ctx context.Context) slog.Logger {
+	if ctx == nil || !w.Enabled() {
+		return w
+	}
+
+	return w.dup(w.Loglet.WithContext(ctx), slog.WithContext(ctx, w.parent))
+}
+
+// WithCaller attaches the immediate caller to a new logger
+func (w *Wrapper) WithCaller(skip int) slog.Logger {
+	if !w.Enabled() {
+		return w
+	}
+
+	ll := w.Loglet
+	if w.hooks.recording() {
+		ll = w.Loglet.WithCaller(skip + 1)
+	}
+	return w.dup(ll, slog.WithCaller(w.parent, skip+1))
+}
+
+// WithTime records when the event happened on a new logger
+func (w *Wrapper) WithTime(t time.Time) slog.Logger {
+	if !w.Enabled() {
+		return w
+	}
+
+	ll := w.Loglet
+	if w.hooks.recording() {
+		ll = w.Loglet.WithTime(t)
+	}
+	return w.dup(ll, slog.WithTime(w.parent, t))
+}
+
+// WithName appends a component to the name of a new logger.
+// Names are recorded even when disabled, as the Enabled hook
+// may use them to decide on the loggers derived from it.
+func (w *Wrapper) WithName(name string) slog.Logger {
+	if name == "" || w == nil || w.parent == nil {
+		return w
+	}
+
+	ll := w.Loglet
+	if w.hooks.recording() {
+		ll = w.Loglet.WithName(name)
+	}
+	return w.dup(ll, slog.WithName(w.parent, name))
+}
+
+// WithField returns a new logger with a field attached
+func (w *Wrapper) WithField(label string, value any) slog.Logger {
+	if label == "" || !w.Enabled() {
+		return w
+	}
+
+	if fn := w.hooks.Field; fn != nil {
+		var ok bool
+		label, value, ok = fn(label, value)
+		if !ok || label == "" {
+			return w
+		}
+	}
+
+	if w.hooks.Fields != nil {
+		return w.attach(map[string]any{label: value})
+	}
+
+	ll := w.Loglet
+	if w.hooks.recording() {
+		ll = w.Loglet.WithField(label, value)
+	}
+	return w.dup(ll, w.parent.WithField(label, value))
+}
+
+// WithFields returns a new logger with a set of fields attached
+func (w *Wrapper) WithFields(fields map[string]any) slog.Logger {
+	if len(fields) == 0 || !w.Enabled() {
+		return w
+	}
+
+	if fn := w.hooks.Field; fn != nil {
+		m := make(map[string]any, len(fields))
+		for k, v := range fields {
+			if k, v, ok := fn(k, v); ok && k != "" {
+				m[k] = v
+			}
+		}
+		fields = m
+	} else {
+		delete(fields, "")
+	}
+
+	return w.attach(fields)
+}
+
+// attach passes the fields through the Fields hook, if any,
+// and returns a new logger with the remaining ones attached
+func (w *Wrapper) attach(fields map[string]any) slog.Logger {
+	if fn := w.hooks.Fields; fn != nil && len(fields) > 0 {
+		fields = fn(&w.Loglet, fields)
+	}
+
+	if len(fields) == 0 {
+		return w
+	}
+
+	ll := w.Loglet
+	if w.hooks.recording() {
+		ll = w.Loglet.WithFields(fields)
+	}
+	return w.dup(ll, w.parent.WithFields(fields))
+}
@@ -0,0 +1,76 @@
+package internal_test
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"darvaza.org/slog"
+	"darvaza.org/slog/internal"
+	slogtest "darvaza.org/slog/internal/testing"
+)
+
+// counter counts how many times it's formatted
+type counter struct {
+	n atomic.Int32
+}
+
+func (c *counter) String() string {
+	c.n.Add(1)
+	return "counter"
+}
+
+func newDisabledWrapper() (slog.Logger, *slogtest.Recorder) {
+	rec := slogtest.NewRecorder()
+	w := internal.NewWrapper(rec, &internal.WrapperHooks{
+		Enabled: func(*internal.Loglet) bool { return false },
+	})
+	return w, rec
+}
+
+func TestWrapperDisabledPrintf(t *testing.T) {
+	w, rec := newDisabledWrapper()
+
+	var c counter
+	w.Info().Printf("%v", &c)
+	w.Debug().Printf("%s", &c)
+
+	if n := c.n.Load(); n != 0 {
+		t.Errorf("disabled entries formatted %d times", n)
+	}
+	if msgs := rec.Messages(); len(msgs) != 0 {
+		t.Errorf("disabled entries recorded: %v", msgs)
+	}
+}
+
+func TestWrapperDisabledFatal(t *testing.T) {
+	w, rec := newDisabledWrapper()
+
+	var c counter
+	w.Fatal().Printf("%v", &c)
+
+	if n := c.n.Load(); n != 1 {
+		t.Errorf("expected Fatal to be formatted once, got %d", n)
+	}
+	msgs := rec.Messages()
+	if len(msgs) != 1 || msgs[0].Message != "counter" || msgs[0].Level != slog.Fatal {
+		t.Errorf("unexpected entries: %v", msgs)
+	}
+}
+
+func TestWrapperDisabledFatalHooks(t *testing.T) {
+	rec := slogtest.NewRecorder()
+	w := internal.NewWrapper(rec, &internal.WrapperHooks{
+		Enabled: func(*internal.Loglet) bool { return false },
+		Print: func(e *internal.Entry) bool {
+			e.Message = "masked: " + e.Message
+			return false
+		},
+	})
+
+	w.Fatal().Println("a", "b")
+	w.Panic().Print("a", "b")
+
+	slogtest.AssertMessagesInOrder(t, rec.Messages(),
+		slogtest.Expect().Level(slog.Fatal).Message("masked: a b"),
+		slogtest.Expect().Level(slog.Panic).Message("masked: ab"))
+}
@@ -0,0 +1,124 @@
+package slog
+
+import (
+	"strings"
+	"unicode"
+)
+
+// ReservedKeys are the keys handlers commonly use for the
+// level, message and time of entries, renamed by PrefixReserved
+// when no other keys are given
+var ReservedKeys = []string{"level", "msg", "time"}
+
+// KeyMapper renames field keys, so field naming can be standardised
+// across backends. Handlers honouring it call it on every key before
+// rendering it.
+type KeyMapper func(key string) string
+
+// Map renames a key, or returns it unchanged if the KeyMapper is nil
+func (m KeyMapper) Map(key string) string {
+	if m == nil {
+		return key
+	}
+	return m(key)
+}
+
+// Then returns a KeyMapper applying another after this one
+func (m KeyMapper) Then(next KeyMapper) KeyMapper {
+	switch {
+	case m == nil:
+		return next
+	case next == nil:
+		return m
+	default:
+		return func(key string) string {
+			return next(m(key))
+		}
+	}
+}
+
+// SnakeCase is a KeyMapper converting keys like "requestID",
+// "RequestId" or "request-id" into "request_id"
+func SnakeCase(key string) string {
+	return joinWords(splitWords(key), '_', false)
+}
+
+// CamelCase is a KeyMapper converting keys like "request_id"
+// or "request-id" into "requestId"
+func CamelCase(key string) string {
+	return joinWords(splitWords(key), 0, true)
+}
+
+// PrefixReserved returns a KeyMapper that adds a prefix to the
+// given keys, or to ReservedKeys if none, so fields don't collide
+// with those a handler uses for the level, message and time of
+// entries, e.g. "level" becomes "fields.level"
+func PrefixReserved(prefix string, reserved ...string) KeyMapper {
+	if len(reserved) == 0 {
+		reserved = ReservedKeys
+	}
+
+	m := make(map[string]bool, len(reserved))
+	for _, key := range reserved {
+		m[key] = true
+	}
+
+	return func(key string) string {
+		if m[key] {
+			return prefix + key
+		}
+		return key
+	}
+}
+
+// splitWords splits a key in lowercase words, on separators
+// and lowercase to uppercase transitions, keeping acronyms
+// like "ID" in "requestID" together
+func splitWords(key string) []string {
+	var words []string
+	var word []rune
+
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, strings.ToLower(string(word)))
+			word = word[:0]
+		}
+	}
+
+	runes := []rune(key)
+	for i, r := range runes {
+		switch {
+		case r == '_' || r == '-' || unicode.IsSpace(r):
+			flush()
+			continue
+		case unicode.IsUpper(r) && i > 0:
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) ||
+				(unicode.IsUpper(prev) && nextLower) {
+				flush()
+			}
+		}
+		word = append(word, r)
+	}
+	flush()
+
+	return words
+}
+
+func joinWords(words []string, sep rune, capitalise bool) string {
+	var buf strings.Builder
+
+	for i, w := range words {
+		if i > 0 && sep != 0 {
+			buf.WriteRune(sep)
+		}
+		if i > 0 && capitalise {
+			r := []rune(w)
+			r[0] = unicode.ToUpper(r[0])
+			w = string(r)
+		}
+		buf.WriteString(w)
+	}
+	return buf.String()
+}
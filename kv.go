@@ -0,0 +1,77 @@
+package slog
+
+import "fmt"
+
+// KVLogger is implemented by loggers that can attach fields
+// given as alternating key/value pairs without building a map
+type KVLogger interface {
+	// WithKV attaches fields from alternating key/value pairs
+	WithKV(pairs ...any) Logger
+}
+
+// WithKV attaches fields given as alternating key/value pairs,
+// as parsed by NextKV(), to a log context. It uses the logger's
+// own WithKV() if implemented, or one WithField() call per pair
+// otherwise, so no map is allocated.
+func WithKV(l Logger, pairs ...any) Logger {
+	if kl, ok := l.(KVLogger); ok {
+		return kl.WithKV(pairs...)
+	}
+
+	if len(pairs) == 0 || !l.Enabled() {
+		return l
+	}
+
+	for len(pairs) > 0 {
+		var key string
+		var value any
+
+		key, value, pairs = NextKV(pairs)
+		if key != "" {
+			l = l.WithField(key, value)
+		}
+	}
+	return l
+}
+
+// NextKV takes the first field out of a list of alternating
+// key/value pairs, returning the remaining ones. A Field element
+// is taken as a complete pair, keys that aren't strings are
+// converted using fmt.Sprint(), and a trailing key without
+// value gets nil.
+func NextKV(pairs []any) (key string, value any, rest []any) {
+	switch len(pairs) {
+	case 0:
+		return "", nil, nil
+	case 1:
+		rest = nil
+	default:
+		value, rest = pairs[1], pairs[2:]
+	}
+
+	switch k := pairs[0].(type) {
+	case Field:
+		return k.Key, k.Value, pairs[1:]
+	case string:
+		return k, value, rest
+	default:
+		return fmt.Sprint(k), value, rest
+	}
+}
+
+// KVFields converts alternating key/value pairs, as parsed by
+// NextKV(), into a list of Field values
+func KVFields(pairs ...any) []Field {
+	if len(pairs) == 0 {
+		return nil
+	}
+
+	out := make([]Field, 0, (len(pairs)+1)/2)
+	for len(pairs) > 0 {
+		var f Field
+
+		f.Key, f.Value, pairs = NextKV(pairs)
+		out = append(out, f)
+	}
+	return out
+}
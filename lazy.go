@@ -0,0 +1,34 @@
+package slog
+
+// Lazy is a field value computed only when an entry using it is
+// actually emitted, or by backends encoding fields eagerly, when
+// attached to an enabled logger. The function may be called once
+// per emitted entry.
+type Lazy func() any
+
+// LazyValue wraps a function so its result is only computed
+// when the field is emitted.
+//
+// e.g.
+// logger.Debug().WithField("state", slog.LazyValue(dumpState)).Print("tick")
+func LazyValue(fn func() any) Lazy {
+	return Lazy(fn)
+}
+
+// Value computes the value of the field
+func (fn Lazy) Value() any {
+	if fn == nil {
+		return nil
+	}
+	return fn()
+}
+
+// ResolveValue returns the given field value, computing it if
+// it's Lazy, converted by the encoder registered for its type
+// if there is one
+func ResolveValue(v any) any {
+	if fn, ok := v.(Lazy); ok {
+		v = fn.Value()
+	}
+	return EncodeValue(v)
+}
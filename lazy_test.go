@@ -0,0 +1,51 @@
+package slog_test
+
+import (
+	"testing"
+
+	"darvaza.org/slog"
+	"darvaza.org/slog/internal"
+	slogtest "darvaza.org/slog/internal/testing"
+)
+
+func TestLazyValueNotInvokedWhenDisabled(t *testing.T) {
+	var calls int
+	lazy := slog.LazyValue(func() any {
+		calls++
+		return "computed"
+	})
+
+	rec := slogtest.NewRecorder()
+	l := internal.NewWrapper(rec, &internal.WrapperHooks{
+		Enabled: func(ll *internal.Loglet) bool {
+			return ll.Level() <= slog.Info
+		},
+	})
+
+	l.Debug().WithField("lazy", lazy).Print("disabled")
+	if calls != 0 {
+		t.Fatalf("closure invoked %d times for a disabled entry", calls)
+	}
+
+	l.Info().WithField("lazy", lazy).Print("enabled")
+	if calls != 1 {
+		t.Fatalf("expected closure to be invoked once, got %d", calls)
+	}
+
+	msgs := rec.Messages()
+	if len(msgs) != 1 || msgs[0].Fields["lazy"] != "computed" {
+		t.Errorf("unexpected entries: %v", msgs)
+	}
+}
+
+func TestResolveValue(t *testing.T) {
+	if v := slog.ResolveValue(slog.LazyValue(func() any { return 1 })); v != 1 {
+		t.Errorf("expected 1, got %v", v)
+	}
+	if v := slog.ResolveValue(slog.Lazy(nil)); v != nil {
+		t.Errorf("expected nil, got %v", v)
+	}
+	if v := slog.ResolveValue("plain"); v != "plain" {
+		t.Errorf("expected %q, got %v", "plain", v)
+	}
+}
@@ -0,0 +1,42 @@
+package slog
+
+import (
+	"strconv"
+	"strings"
+
+	"darvaza.org/core"
+)
+
+var levelNames = []string{
+	UndefinedLevel: "undefined",
+	Panic:          "panic",
+	Fatal:          "fatal",
+	Error:          "error",
+	Warn:           "warn",
+	Info:           "info",
+	Debug:          "debug",
+}
+
+// String returns the lowercase name of the level
+func (level LogLevel) String() string {
+	if level >= UndefinedLevel && int(level) < len(levelNames) {
+		return levelNames[level]
+	}
+	return "LogLevel(" + strconv.Itoa(int(level)) + ")"
+}
+
+// ParseLevel returns the LogLevel corresponding to a name,
+// case insensitive. "warning" is accepted as alias of "warn".
+func ParseLevel(name string) (LogLevel, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "warning" {
+		return Warn, nil
+	}
+
+	for i, s := range levelNames {
+		if i > int(UndefinedLevel) && s == name {
+			return LogLevel(i), nil
+		}
+	}
+	return UndefinedLevel, core.Wrapf(core.ErrInvalid, "invalid log level %q", name)
+}
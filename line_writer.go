@@ -0,0 +1,239 @@
+package slog
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+var (
+	_ io.WriteCloser = (*Writer)(nil)
+)
+
+// DefaultMaxLineSize is the longest line a Writer
+// buffers when no MaxLineSize is given
+const DefaultMaxLineSize = 64 << 10
+
+// WriterOptions configures a Writer created by NewWriter
+type WriterOptions struct {
+	// Level is used for lines without a recognised level.
+	// Defaults to Info.
+	Level LogLevel
+
+	// ParseLevel enables detecting level prefixes like "ERROR:",
+	// "[warn]" or "INFO " at the beginning of each line. Prefixes
+	// followed by a space are only recognised in capitals, so
+	// "Error connecting" is left alone. The prefix is removed from
+	// the message. Fatal and Panic prefixes are logged as Error as
+	// a Writer never terminates the program.
+	ParseLevel bool
+
+	// ParseKlog enables detecting klog/glog style headers like
+	// "E0102 15:04:05.000000    1234 file.go:12] message",
+	// which are removed from the message.
+	ParseKlog bool
+
+	// ParseJSON enables decoding lines holding a JSON object.
+	// The "level", "msg" and "time" keys, or common aliases,
+	// set the level, message and time of the entry, and the
+	// rest become fields.
+	ParseJSON bool
+
+	// ParseLogfmt enables decoding lines of key=value pairs,
+	// treated like those holding JSON.
+	ParseLogfmt bool
+
+	// MaxLineSize is the number of bytes after which a line is
+	// logged even if incomplete, the rest following as another
+	// entry. Defaults to DefaultMaxLineSize.
+	MaxLineSize int
+}
+
+// Writer is an io.WriteCloser that adds a log entry for each
+// line written to it. Incomplete lines are buffered until
+// completed, the Writer is closed, or they reach MaxLineSize.
+type Writer struct {
+	mu   sync.Mutex
+	l    Logger
+	buf  []byte
+	opts WriterOptions
+}
+
+// NewWriter creates a Writer that logs each line on the given Logger.
+func NewWriter(l Logger, opts *WriterOptions) *Writer {
+	if l == nil {
+		return nil
+	}
+
+	w := &Writer{l: l}
+	if opts != nil {
+		w.opts = *opts
+	}
+	if w.opts.Level <= UndefinedLevel {
+		w.opts.Level = Info
+	}
+	if w.opts.MaxLineSize <= 0 {
+		w.opts.MaxLineSize = DefaultMaxLineSize
+	}
+	return w
+}
+
+// Write logs every complete line, splitting those over MaxLineSize,
+// and buffers the remainder
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	maxSize := w.opts.MaxLineSize
+	w.buf = append(w.buf, p...)
+	for len(w.buf) > 0 {
+		i := bytes.IndexByte(w.buf, '\n')
+		switch {
+		case i >= 0 && i <= maxSize:
+			w.writeLine(string(w.buf[:i]))
+			w.buf = w.buf[i+1:]
+		case i >= 0 || len(w.buf) >= maxSize:
+			// too long
+			n := cutLine(w.buf, maxSize)
+			w.writeLine(string(w.buf[:n]))
+			w.buf = w.buf[n:]
+		default:
+			// incomplete
+			return len(p), nil
+		}
+	}
+
+	// release
+	w.buf = nil
+	return len(p), nil
+}
+
+// Close logs any buffered incomplete line
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.buf) > 0 {
+		w.writeLine(string(w.buf))
+		w.buf = nil
+	}
+	return nil
+}
+
+// cutLine returns where to split a line longer than maxSize,
+// avoiding splitting UTF-8 sequences
+func cutLine(b []byte, maxSize int) int {
+	if maxSize >= len(b) {
+		return len(b)
+	}
+
+	for n := maxSize; n > maxSize-utf8.UTFMax && n > 0; n-- {
+		if utf8.RuneStart(b[n]) {
+			return n
+		}
+	}
+	return maxSize
+}
+
+func (w *Writer) writeLine(line string) {
+	line = strings.TrimRight(line, "\r")
+	if strings.TrimSpace(line) == "" {
+		return
+	}
+
+	if w.writeStructured(line) {
+		return
+	}
+
+	level, msg, ok := UndefinedLevel, line, false
+	if w.opts.ParseKlog {
+		level, msg, ok = parseKlogHeader(line)
+	}
+	if !ok && w.opts.ParseLevel {
+		level, msg, ok = parseLevelPrefix(line)
+	}
+	if !ok {
+		level, msg = w.opts.Level, line
+	}
+
+	w.l.WithLevel(level).Print(msg)
+}
+
+// levelPrefixes maps the names recognised as level prefixes
+var levelPrefixes = map[string]LogLevel{
+	"PANIC":   Error,
+	"FATAL":   Error,
+	"ERROR":   Error,
+	"ERR":     Error,
+	"WARNING": Warn,
+	"WARN":    Warn,
+	"INFO":    Info,
+	"DEBUG":   Debug,
+}
+
+// parseLevelPrefix detects "[level]", "level:" and "LEVEL " prefixes,
+// the last only in capitals so sentences starting with a level name
+// aren't mistaken for one
+func parseLevelPrefix(line string) (LogLevel, string, bool) {
+	var word, rest string
+
+	if s, ok := strings.CutPrefix(line, "["); ok {
+		word, rest, ok = strings.Cut(s, "]")
+		if !ok {
+			return UndefinedLevel, line, false
+		}
+	} else {
+		i := strings.IndexAny(line, ": ")
+		switch {
+		case i < 0:
+			return UndefinedLevel, line, false
+		case line[i] == ' ' && line[:i] != strings.ToUpper(line[:i]):
+			return UndefinedLevel, line, false
+		}
+		word, rest = line[:i], line[i+1:]
+	}
+
+	if level, ok := levelPrefixes[strings.ToUpper(word)]; ok {
+		return level, strings.TrimLeft(rest, ": "), true
+	}
+	return UndefinedLevel, line, false
+}
+
+// klogLevels maps the severity letter of klog/glog headers
+var klogLevels = map[byte]LogLevel{
+	'F': Error,
+	'E': Error,
+	'W': Warn,
+	'I': Info,
+}
+
+// parseKlogHeader detects "Lmmdd hh:mm:ss.uuuuuu threadid file:line] msg"
+func parseKlogHeader(line string) (LogLevel, string, bool) {
+	const minHeader = len("I0102 15:04:05.000000 1 x:1] ")
+
+	if len(line) < minHeader || !isDigits(line[1:5]) || line[5] != ' ' {
+		return UndefinedLevel, line, false
+	}
+
+	level, ok := klogLevels[line[0]]
+	if !ok {
+		return UndefinedLevel, line, false
+	}
+
+	_, msg, ok := strings.Cut(line, "] ")
+	if !ok {
+		return UndefinedLevel, line, false
+	}
+	return level, msg, true
+}
+
+func isDigits(s string) bool {
+	for _, c := range []byte(s) {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
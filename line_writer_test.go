@@ -0,0 +1,57 @@
+package slog_test
+
+import (
+	"strings"
+	"testing"
+
+	"darvaza.org/slog"
+	slogtest "darvaza.org/slog/internal/testing"
+)
+
+func TestWriterLevelPrefix(t *testing.T) {
+	rec := slogtest.NewRecorder()
+	w := slog.NewWriter(rec, &slog.WriterOptions{ParseLevel: true})
+
+	_, _ = w.Write([]byte(strings.Join([]string{
+		"[warn] bracketed",
+		"error: colon",
+		"Error: capitalised colon",
+		"DEBUG capitals",
+		"Error connecting to the database",
+		"info about something",
+		"WARNING",
+	}, "\n")))
+	_ = w.Close()
+
+	slogtest.AssertMessagesInOrder(t, rec.Messages(),
+		slogtest.Expect().Level(slog.Warn).Message("bracketed"),
+		slogtest.Expect().Level(slog.Error).Message("colon"),
+		slogtest.Expect().Level(slog.Error).Message("capitalised colon"),
+		slogtest.Expect().Level(slog.Debug).Message("capitals"),
+		slogtest.Expect().Level(slog.Info).Message("Error connecting to the database"),
+		slogtest.Expect().Level(slog.Info).Message("info about something"),
+		slogtest.Expect().Level(slog.Info).Message("WARNING"))
+}
+
+func TestWriterMaxLineSize(t *testing.T) {
+	rec := slogtest.NewRecorder()
+	w := slog.NewWriter(rec, &slog.WriterOptions{MaxLineSize: 8})
+
+	// buffered until the cap is reached
+	_, _ = w.Write([]byte("abcd"))
+	if n := len(rec.Messages()); n != 0 {
+		t.Fatalf("incomplete line logged early: %d entries", n)
+	}
+	_, _ = w.Write([]byte("efghij"))
+
+	// a complete line over the cap, not splitting "ñ"
+	_, _ = w.Write([]byte("\n1234567ñ89\nshort"))
+	_ = w.Close()
+
+	slogtest.AssertMessagesInOrder(t, rec.Messages(),
+		slogtest.Expect().Message("abcdefgh"),
+		slogtest.Expect().Message("ij"),
+		slogtest.Expect().Message("1234567"),
+		slogtest.Expect().Message("ñ89"),
+		slogtest.Expect().Message("short"))
+}
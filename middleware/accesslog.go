@@ -0,0 +1,144 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AccessLogFormat is the layout of access log lines
+type AccessLogFormat int
+
+const (
+	// CommonLogFormat is the NCSA Common Log Format
+	CommonLogFormat AccessLogFormat = iota
+	// CombinedLogFormat is the Common Log Format followed by
+	// the Referer and User-Agent of the request
+	CombinedLogFormat
+)
+
+// AccessLogTimeFormat is the layout of the time in access log lines
+const AccessLogTimeFormat = "02/Jan/2006:15:04:05 -0700"
+
+// Render composes the access log line, without new line, of an
+// entry logged at the given time from its fields. It returns false
+// if the method or the status are missing.
+func (f AccessLogFormat) Render(t time.Time, fields map[string]any) (string, bool) {
+	method, ok1 := fields[MethodFieldName]
+	status, ok2 := fields[StatusFieldName]
+	if !ok1 || !ok2 {
+		return "", false
+	}
+
+	var buf strings.Builder
+
+	buf.WriteString(remoteHost(fields[RemoteAddrFieldName]))
+	buf.WriteString(" - ")
+	buf.WriteString(accessField(fields, UserFieldName))
+	buf.WriteString(" [")
+	buf.WriteString(t.Format(AccessLogTimeFormat))
+	buf.WriteString("] \"")
+	buf.WriteString(escapeAccess(fmt.Sprint(method)))
+	if path, ok := fields[PathFieldName]; ok {
+		buf.WriteByte(' ')
+		buf.WriteString(escapeAccess(fmt.Sprint(path)))
+	}
+	if proto, ok := fields[ProtoFieldName]; ok {
+		buf.WriteByte(' ')
+		buf.WriteString(escapeAccess(fmt.Sprint(proto)))
+	}
+	buf.WriteString("\" ")
+	_, _ = fmt.Fprint(&buf, status)
+	buf.WriteByte(' ')
+	buf.WriteString(accessSize(fields[SizeFieldName]))
+
+	if f == CombinedLogFormat {
+		buf.WriteString(" \"")
+		buf.WriteString(accessField(fields, RefererFieldName))
+		buf.WriteString("\" \"")
+		buf.WriteString(accessField(fields, UserAgentFieldName))
+		buf.WriteString("\"")
+	}
+
+	return buf.String(), true
+}
+
+// remoteHost returns the host part of the remote address
+func remoteHost(v any) string {
+	s, _ := v.(string)
+	if s == "" {
+		return "-"
+	}
+
+	if host, _, err := net.SplitHostPort(s); err == nil {
+		s = host
+	}
+	return escapeAccess(s)
+}
+
+// accessField returns the escaped value of a field, or "-"
+// if missing or empty
+func accessField(fields map[string]any, key string) string {
+	v, ok := fields[key]
+	if !ok || v == nil {
+		return "-"
+	}
+
+	s := fmt.Sprint(v)
+	if s == "" {
+		return "-"
+	}
+	return escapeAccess(s)
+}
+
+// accessSize returns the size of the response body, or "-"
+// if empty
+func accessSize(v any) string {
+	switch n := v.(type) {
+	case int:
+		if n > 0 {
+			return strconv.Itoa(n)
+		}
+	case int64:
+		if n > 0 {
+			return strconv.FormatInt(n, 10)
+		}
+	case nil:
+	default:
+		return fmt.Sprint(v)
+	}
+	return "-"
+}
+
+// escapeAccess escapes quotes, backslashes and control
+// characters the way Apache does
+func escapeAccess(s string) string {
+	if !needsEscaping(s) {
+		return s
+	}
+
+	var buf strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '"', c == '\\':
+			buf.WriteByte('\\')
+			buf.WriteByte(c)
+		case c < 0x20 || c == 0x7f:
+			_, _ = fmt.Fprintf(&buf, `\x%02x`, c)
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	return buf.String()
+}
+
+func needsEscaping(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c == '"' || c == '\\' || c < 0x20 || c == 0x7f {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	slogtest "darvaza.org/slog/internal/testing"
+)
+
+func TestAccessLogFormat(t *testing.T) {
+	ts := time.Date(2024, 10, 10, 13, 55, 36, 0, time.FixedZone("", -7*3600))
+	fields := map[string]any{
+		MethodFieldName:     "GET",
+		PathFieldName:       "/apache_pb.gif",
+		ProtoFieldName:      "HTTP/1.0",
+		StatusFieldName:     200,
+		SizeFieldName:       2326,
+		RemoteAddrFieldName: "127.0.0.1:4321",
+		UserFieldName:       "frank",
+		RefererFieldName:    "http://www.example.com/start.html",
+		UserAgentFieldName:  `Mozilla/4.08 "quoted"`,
+	}
+
+	for _, tc := range []struct {
+		format AccessLogFormat
+		want   string
+	}{
+		{CommonLogFormat, `127.0.0.1 - frank [10/Oct/2024:13:55:36 -0700] ` +
+			`"GET /apache_pb.gif HTTP/1.0" 200 2326`},
+		{CombinedLogFormat, `127.0.0.1 - frank [10/Oct/2024:13:55:36 -0700] ` +
+			`"GET /apache_pb.gif HTTP/1.0" 200 2326 ` +
+			`"http://www.example.com/start.html" "Mozilla/4.08 \"quoted\""`},
+	} {
+		got, ok := tc.format.Render(ts, fields)
+		if !ok || got != tc.want {
+			t.Errorf("%d: expected %q, got %q", tc.format, tc.want, got)
+		}
+	}
+}
+
+func TestAccessLogFormatMissing(t *testing.T) {
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	got, ok := CombinedLogFormat.Render(ts, map[string]any{
+		MethodFieldName: "GET\n",
+		StatusFieldName: 304,
+		SizeFieldName:   0,
+	})
+	want := `- - - [02/Jan/2024:03:04:05 +0000] "GET\x0a" 304 - "-" "-"`
+	if !ok || got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	if _, ok := CommonLogFormat.Render(ts, map[string]any{MethodFieldName: "GET"}); ok {
+		t.Error("rendered without status")
+	}
+}
+
+func TestHTTPHandlerAccessFields(t *testing.T) {
+	rec := slogtest.NewRecorder()
+	h := NewHTTPHandler(rec, http.NotFoundHandler())
+	h.AccessFields = true
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Referer", "http://example.com/")
+	req.Header.Set("User-Agent", "test")
+	serve(h, req)
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	serve(h, req)
+
+	slogtest.AssertMessagesInOrder(t, rec.Messages(),
+		slogtest.Expect().Field(ProtoFieldName, "HTTP/1.1").
+			Field(RefererFieldName, "http://example.com/").
+			Field(UserAgentFieldName, "test"),
+		slogtest.Expect().Field(ProtoFieldName, "HTTP/1.1").
+			NoField(RefererFieldName).
+			NoField(UserAgentFieldName))
+}
@@ -0,0 +1,19 @@
+module darvaza.org/slog/middleware/grpc
+
+go 1.22
+
+replace darvaza.org/slog => ../../
+
+require (
+	darvaza.org/slog v0.6.0
+	google.golang.org/grpc v1.67.1
+)
+
+require (
+	darvaza.org/core v0.16.0 // indirect
+	golang.org/x/net v0.34.0 // indirect
+	golang.org/x/sys v0.29.0 // indirect
+	golang.org/x/text v0.21.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240814211410-ddb44dafa142 // indirect
+	google.golang.org/protobuf v1.34.2 // indirect
+)
@@ -0,0 +1,135 @@
+// Package grpc provides gRPC interceptors and a grpclog.LoggerV2
+// adapter built on slog.Logger
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"darvaza.org/slog"
+)
+
+const (
+	// ServiceFieldName is the field label for the gRPC service
+	ServiceFieldName = "grpc.service"
+	// MethodFieldName is the field label for the gRPC method
+	MethodFieldName = "grpc.method"
+	// CodeFieldName is the field label for the gRPC status code
+	CodeFieldName = "grpc.code"
+	// DurationFieldName is the field label for the time it took
+	// to complete the call
+	DurationFieldName = "duration"
+	// PeerFieldName is the field label for the address of the peer
+	PeerFieldName = "peer"
+)
+
+// Option configures the interceptors
+type Option func(*config)
+
+type config struct {
+	codeToLevel func(codes.Code) slog.LogLevel
+	logStart    bool
+}
+
+// WithCodeToLevel sets the function used to choose the level
+// of an entry from the status code of the call.
+// Defaults to DefaultCodeToLevel.
+func WithCodeToLevel(fn func(codes.Code) slog.LogLevel) Option {
+	return func(cfg *config) {
+		if fn != nil {
+			cfg.codeToLevel = fn
+		}
+	}
+}
+
+// WithLogStart enables logging an entry, at Debug level,
+// when calls begin
+func WithLogStart() Option {
+	return func(cfg *config) {
+		cfg.logStart = true
+	}
+}
+
+func newConfig(opts []Option) *config {
+	cfg := &config{
+		codeToLevel: DefaultCodeToLevel,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(cfg)
+		}
+	}
+	return cfg
+}
+
+// DefaultCodeToLevel logs successful calls as Info, those failing
+// because of the client as Warn, and server side failures as Error.
+func DefaultCodeToLevel(code codes.Code) slog.LogLevel {
+	switch code {
+	case codes.OK:
+		return slog.Info
+	case codes.Canceled, codes.InvalidArgument, codes.NotFound,
+		codes.AlreadyExists, codes.PermissionDenied, codes.Unauthenticated,
+		codes.ResourceExhausted, codes.FailedPrecondition, codes.Aborted,
+		codes.OutOfRange:
+		return slog.Warn
+	default:
+		return slog.Error
+	}
+}
+
+// callFields returns the fields describing a call
+func callFields(ctx context.Context, fullMethod string) slog.Fields {
+	service, method := splitMethod(fullMethod)
+
+	fields := slog.Fields{
+		ServiceFieldName: service,
+		MethodFieldName:  method,
+	}
+
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		fields[PeerFieldName] = p.Addr.String()
+	}
+
+	return fields
+}
+
+// splitMethod splits "/package.Service/Method"
+func splitMethod(fullMethod string) (service, method string) {
+	s := fullMethod
+	if len(s) > 0 && s[0] == '/' {
+		s = s[1:]
+	}
+
+	for i := len(s) - 1; i >= 0; i-- {
+		if s[i] == '/' {
+			return s[:i], s[i+1:]
+		}
+	}
+	return "", s
+}
+
+func (cfg *config) logStarted(l slog.Logger) {
+	if cfg.logStart {
+		l.Debug().Print("call started")
+	}
+}
+
+func (cfg *config) logDone(l slog.Logger, start time.Time, err error) {
+	code := status.Code(err)
+
+	l = l.WithLevel(cfg.codeToLevel(code)).WithFields(slog.Fields{
+		CodeFieldName:     code.String(),
+		DurationFieldName: time.Since(start),
+	})
+
+	if err != nil {
+		l = l.WithField(slog.ErrorFieldName, err)
+	}
+
+	l.Print("call completed")
+}
@@ -0,0 +1,146 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"darvaza.org/slog"
+	slogtest "darvaza.org/slog/internal/testing"
+)
+
+// testServerStream is a grpc.ServerStream with a given context
+type testServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (ss *testServerStream) Context() context.Context { return ss.ctx }
+
+func peerContext() context.Context {
+	return peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 1234},
+	})
+}
+
+func TestUnaryServer(t *testing.T) {
+	rec := slogtest.NewRecorder()
+	intercept := UnaryServerInterceptor(rec, WithLogStart())
+	info := &grpc.UnaryServerInfo{FullMethod: "/pkg.Greeter/Hello"}
+
+	resp, err := intercept(peerContext(), "req", info,
+		func(_ context.Context, req any) (any, error) {
+			return req.(string) + "!", nil
+		})
+	if err != nil || resp != "req!" {
+		t.Fatalf("unexpected response %v, %v", resp, err)
+	}
+
+	failure := status.Error(codes.NotFound, "missing")
+	_, err = intercept(peerContext(), "req", info,
+		func(context.Context, any) (any, error) {
+			return nil, failure
+		})
+	if err != failure {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	slogtest.AssertMessagesInOrder(t, rec.Messages(),
+		slogtest.Expect().Level(slog.Debug).Message("call started").
+			Field(ServiceFieldName, "pkg.Greeter").
+			Field(MethodFieldName, "Hello").
+			Field(PeerFieldName, "127.0.0.1:1234"),
+		slogtest.Expect().Level(slog.Info).Message("call completed").
+			Field(CodeFieldName, "OK").
+			HasField(DurationFieldName).
+			NoField(slog.ErrorFieldName),
+		slogtest.Expect().Level(slog.Debug).Message("call started"),
+		slogtest.Expect().Level(slog.Warn).Message("call completed").
+			Field(CodeFieldName, "NotFound").
+			Field(slog.ErrorFieldName, failure))
+}
+
+func TestStreamServer(t *testing.T) {
+	rec := slogtest.NewRecorder()
+	intercept := StreamServerInterceptor(rec, WithCodeToLevel(func(codes.Code) slog.LogLevel {
+		return slog.Debug
+	}))
+
+	failure := errors.New("not a status")
+	ss := &testServerStream{ctx: peerContext()}
+	err := intercept(nil, ss, &grpc.StreamServerInfo{FullMethod: "/pkg.Greeter/Chat"},
+		func(any, grpc.ServerStream) error { return failure })
+	if err != failure {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	slogtest.AssertMessagesInOrder(t, rec.Messages(),
+		slogtest.Expect().Level(slog.Debug).Message("call completed").
+			Field(MethodFieldName, "Chat").
+			Field(CodeFieldName, "Unknown").
+			Field(slog.ErrorFieldName, failure))
+}
+
+func TestUnaryClient(t *testing.T) {
+	cc, err := grpc.NewClient("passthrough:///backend:50051",
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cc.Close()
+
+	rec := slogtest.NewRecorder()
+	intercept := UnaryClientInterceptor(rec)
+
+	err = intercept(context.Background(), "/pkg.Greeter/Hello", nil, nil, cc,
+		func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+			return status.Error(codes.Internal, "boom")
+		})
+	if status.Code(err) != codes.Internal {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	slogtest.AssertMessagesInOrder(t, rec.Messages(),
+		slogtest.Expect().Level(slog.Error).Message("call completed").
+			Field(ServiceFieldName, "pkg.Greeter").
+			Field(PeerFieldName, "passthrough:///backend:50051").
+			Field(CodeFieldName, "Internal"))
+}
+
+func TestSplitMethod(t *testing.T) {
+	for _, tc := range []struct {
+		full, service, method string
+	}{
+		{"/pkg.Service/Method", "pkg.Service", "Method"},
+		{"pkg.Service/Method", "pkg.Service", "Method"},
+		{"/Method", "", "Method"},
+		{"", "", ""},
+	} {
+		service, method := splitMethod(tc.full)
+		if service != tc.service || method != tc.method {
+			t.Errorf("%q: expected %q %q, got %q %q",
+				tc.full, tc.service, tc.method, service, method)
+		}
+	}
+}
+
+func TestDefaultCodeToLevel(t *testing.T) {
+	for code, want := range map[codes.Code]slog.LogLevel{
+		codes.OK:               slog.Info,
+		codes.Canceled:         slog.Warn,
+		codes.PermissionDenied: slog.Warn,
+		codes.Unknown:          slog.Error,
+		codes.Unavailable:      slog.Error,
+	} {
+		if got := DefaultCodeToLevel(code); got != want {
+			t.Errorf("%s: expected %s, got %s", code, want, got)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package grpc
+
+import (
+	"fmt"
+
+	"google.golang.org/grpc/grpclog"
+
+	"darvaza.org/slog"
+)
+
+var (
+	_ grpclog.LoggerV2 = (*LoggerV2)(nil)
+)
+
+// LoggerV2 is a grpclog.LoggerV2 using a slog.Logger as backend.
+// Install it using grpclog.SetLoggerV2().
+type LoggerV2 struct {
+	logger    slog.Logger
+	verbosity int
+}
+
+// NewLoggerV2 creates a grpclog.LoggerV2 adapter for the given
+// slog.Logger. V(n) is enabled for n up to the given verbosity,
+// and entries use the Debug level when verbosity is above zero.
+func NewLoggerV2(l slog.Logger, verbosity int) *LoggerV2 {
+	if l == nil {
+		return nil
+	}
+
+	return &LoggerV2{
+		logger:    l,
+		verbosity: verbosity,
+	}
+}
+
+func (g *LoggerV2) print(level slog.LogLevel, msg string) {
+	g.logger.WithLevel(level).Print(msg)
+}
+
+// Info logs to Info level, in the manner of fmt.Print
+func (g *LoggerV2) Info(args ...any) { g.print(slog.Info, fmt.Sprint(args...)) }
+
+// Infoln logs to Info level, in the manner of fmt.Println
+func (g *LoggerV2) Infoln(args ...any) { g.print(slog.Info, fmt.Sprintln(args...)) }
+
+// Infof logs to Info level, in the manner of fmt.Printf
+func (g *LoggerV2) Infof(format string, args ...any) {
+	g.print(slog.Info, fmt.Sprintf(format, args...))
+}
+
+// Warning logs to Warn level, in the manner of fmt.Print
+func (g *LoggerV2) Warning(args ...any) { g.print(slog.Warn, fmt.Sprint(args...)) }
+
+// Warningln logs to Warn level, in the manner of fmt.Println
+func (g *LoggerV2) Warningln(args ...any) { g.print(slog.Warn, fmt.Sprintln(args...)) }
+
+// Warningf logs to Warn level, in the manner of fmt.Printf
+func (g *LoggerV2) Warningf(format string, args ...any) {
+	g.print(slog.Warn, fmt.Sprintf(format, args...))
+}
+
+// Error logs to Error level, in the manner of fmt.Print
+func (g *LoggerV2) Error(args ...any) { g.print(slog.Error, fmt.Sprint(args...)) }
+
+// Errorln logs to Error level, in the manner of fmt.Println
+func (g *LoggerV2) Errorln(args ...any) { g.print(slog.Error, fmt.Sprintln(args...)) }
+
+// Errorf logs to Error level, in the manner of fmt.Printf
+func (g *LoggerV2) Errorf(format string, args ...any) {
+	g.print(slog.Error, fmt.Sprintf(format, args...))
+}
+
+// Fatal logs to Fatal level, in the manner of fmt.Print
+func (g *LoggerV2) Fatal(args ...any) { g.print(slog.Fatal, fmt.Sprint(args...)) }
+
+// Fatalln logs to Fatal level, in the manner of fmt.Println
+func (g *LoggerV2) Fatalln(args ...any) { g.print(slog.Fatal, fmt.Sprintln(args...)) }
+
+// Fatalf logs to Fatal level, in the manner of fmt.Printf
+func (g *LoggerV2) Fatalf(format string, args ...any) {
+	g.print(slog.Fatal, fmt.Sprintf(format, args...))
+}
+
+// V tells if the given verbosity level is enabled
+func (g *LoggerV2) V(l int) bool {
+	if l > g.verbosity {
+		return false
+	}
+
+	level := slog.Info
+	if l > 0 {
+		level = slog.Debug
+	}
+	return g.logger.WithLevel(level).Enabled()
+}
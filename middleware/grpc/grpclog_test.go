@@ -0,0 +1,32 @@
+package grpc
+
+import (
+	"testing"
+
+	"darvaza.org/slog"
+	slogtest "darvaza.org/slog/internal/testing"
+)
+
+func TestLoggerV2(t *testing.T) {
+	rec := slogtest.NewRecorder()
+	g := NewLoggerV2(rec, 1)
+
+	g.Info("info ", 1)
+	g.Warningf("warn %d", 2)
+	g.Errorln("error", 3)
+
+	slogtest.AssertMessagesInOrder(t, rec.Messages(),
+		slogtest.Expect().Level(slog.Info).Message("info 1"),
+		slogtest.Expect().Level(slog.Warn).Message("warn 2"),
+		slogtest.Expect().Level(slog.Error).MessageContains("error 3"))
+
+	for v, want := range map[int]bool{0: true, 1: true, 2: false} {
+		if got := g.V(v); got != want {
+			t.Errorf("V(%d): expected %v, got %v", v, want, got)
+		}
+	}
+
+	if NewLoggerV2(nil, 0) != nil {
+		t.Error("expected nil without slog.Logger")
+	}
+}
@@ -0,0 +1,82 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"darvaza.org/slog"
+)
+
+// UnaryServerInterceptor logs the completion of unary calls
+// received by a gRPC server
+func UnaryServerInterceptor(l slog.Logger, opts ...Option) grpc.UnaryServerInterceptor {
+	cfg := newConfig(opts)
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (any, error) {
+		//
+		start := time.Now()
+		ll := l.WithFields(callFields(ctx, info.FullMethod))
+		cfg.logStarted(ll)
+
+		resp, err := handler(ctx, req)
+		cfg.logDone(ll, start, err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor logs the completion of streaming calls
+// received by a gRPC server
+func StreamServerInterceptor(l slog.Logger, opts ...Option) grpc.StreamServerInterceptor {
+	cfg := newConfig(opts)
+
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler) error {
+		//
+		start := time.Now()
+		ll := l.WithFields(callFields(ss.Context(), info.FullMethod))
+		cfg.logStarted(ll)
+
+		err := handler(srv, ss)
+		cfg.logDone(ll, start, err)
+		return err
+	}
+}
+
+// UnaryClientInterceptor logs the completion of unary calls
+// made by a gRPC client
+func UnaryClientInterceptor(l slog.Logger, opts ...Option) grpc.UnaryClientInterceptor {
+	cfg := newConfig(opts)
+
+	return func(ctx context.Context, method string, req, reply any,
+		cc *grpc.ClientConn, invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		//
+		start := time.Now()
+		ll := l.WithFields(callFields(ctx, method)).WithField(PeerFieldName, cc.Target())
+		cfg.logStarted(ll)
+
+		err := invoker(ctx, method, req, reply, cc, callOpts...)
+		cfg.logDone(ll, start, err)
+		return err
+	}
+}
+
+// StreamClientInterceptor logs the establishment of streaming calls
+// made by a gRPC client
+func StreamClientInterceptor(l slog.Logger, opts ...Option) grpc.StreamClientInterceptor {
+	cfg := newConfig(opts)
+
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn,
+		method string, streamer grpc.Streamer, callOpts ...grpc.CallOption) (grpc.ClientStream, error) {
+		//
+		start := time.Now()
+		ll := l.WithFields(callFields(ctx, method)).WithField(PeerFieldName, cc.Target())
+		cfg.logStarted(ll)
+
+		cs, err := streamer(ctx, desc, cc, method, callOpts...)
+		cfg.logDone(ll, start, err)
+		return cs, err
+	}
+}
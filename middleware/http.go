@@ -0,0 +1,198 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"darvaza.org/slog"
+)
+
+var (
+	_ http.Handler = (*HTTPHandler)(nil)
+)
+
+// DefaultRequestIDHeader is the header used to get the request ID
+// when none is specified
+const DefaultRequestIDHeader = "X-Request-Id"
+
+// HTTPHandler is an http.Handler middleware that logs the
+// completion of each request, and optionally their start.
+type HTTPHandler struct {
+	// Logger is where entries are added
+	Logger slog.Logger
+	// Next is the handler doing the actual work
+	Next http.Handler
+
+	// RouteLevels overrides Level for paths starting with the
+	// given prefixes. The longest match wins.
+	RouteLevels map[string]slog.LogLevel
+
+	// RequestIDHeader is the header containing the request ID.
+	// Defaults to DefaultRequestIDHeader
+	RequestIDHeader string
+
+	// Level is the level used to log requests. Defaults to Info.
+	// Responses with 5xx status are logged as Error regardless.
+	Level slog.LogLevel
+
+	// LogStart enables logging an entry when requests begin
+	LogStart bool
+
+	// AccessFields adds the protocol, Referer and User-Agent of
+	// requests, needed to render Combined Log Format lines
+	AccessFields bool
+}
+
+// NewHTTPHandler creates an HTTPHandler logging requests
+// to the given slog.Logger
+func NewHTTPHandler(l slog.Logger, next http.Handler) *HTTPHandler {
+	return &HTTPHandler{
+		Logger: l,
+		Next:   next,
+	}
+}
+
+func (h *HTTPHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if h.Logger == nil || h.Next == nil {
+		h.serveNext(rw, req)
+		return
+	}
+
+	start := time.Now()
+	level := h.routeLevel(req)
+	l := h.Logger.WithFields(h.requestFields(req))
+
+	if h.LogStart {
+		l.WithLevel(level).Print("request started")
+	}
+
+	w := &responseWriter{ResponseWriter: rw}
+	h.Next.ServeHTTP(w, req)
+
+	status := w.Status()
+	if status >= http.StatusInternalServerError {
+		level = slog.Error
+	}
+
+	l.WithLevel(level).WithFields(slog.Fields{
+		StatusFieldName:   status,
+		SizeFieldName:     w.size,
+		DurationFieldName: time.Since(start),
+	}).Print("request completed")
+}
+
+func (h *HTTPHandler) serveNext(rw http.ResponseWriter, req *http.Request) {
+	if h.Next != nil {
+		h.Next.ServeHTTP(rw, req)
+	} else {
+		http.NotFound(rw, req)
+	}
+}
+
+func (h *HTTPHandler) requestFields(req *http.Request) slog.Fields {
+	fields := slog.Fields{
+		MethodFieldName:     req.Method,
+		PathFieldName:       req.URL.Path,
+		RemoteAddrFieldName: req.RemoteAddr,
+	}
+
+	header := h.RequestIDHeader
+	if header == "" {
+		header = DefaultRequestIDHeader
+	}
+	if id := req.Header.Get(header); id != "" {
+		fields[RequestIDFieldName] = id
+	}
+
+	if h.AccessFields {
+		addAccessFields(fields, req)
+	}
+
+	if tc, ok := requestTrace(req); ok {
+		fields[slog.TraceIDFieldName] = tc.TraceID.String()
+		fields[slog.SpanIDFieldName] = tc.SpanID.String()
+	}
+
+	return fields
+}
+
+func addAccessFields(fields slog.Fields, req *http.Request) {
+	fields[ProtoFieldName] = req.Proto
+
+	if s := req.Referer(); s != "" {
+		fields[RefererFieldName] = s
+	}
+	if s := req.UserAgent(); s != "" {
+		fields[UserAgentFieldName] = s
+	}
+}
+
+// requestTrace returns the TraceContext of the request's context,
+// or its traceparent header
+func requestTrace(req *http.Request) (slog.TraceContext, bool) {
+	if tc, ok := slog.GetTraceContext(req.Context()); ok {
+		return tc, true
+	}
+
+	if s := req.Header.Get(slog.TraceParentHeader); s != "" {
+		tc, err := slog.ParseTraceParent(s)
+		return tc, err == nil
+	}
+	return slog.TraceContext{}, false
+}
+
+func (h *HTTPHandler) routeLevel(req *http.Request) slog.LogLevel {
+	level, best := h.Level, -1
+
+	for prefix, lvl := range h.RouteLevels {
+		if len(prefix) > best && strings.HasPrefix(req.URL.Path, prefix) {
+			level, best = lvl, len(prefix)
+		}
+	}
+
+	if level <= slog.UndefinedLevel {
+		level = slog.Info
+	}
+	return level
+}
+
+// responseWriter records the status and size of a response
+type responseWriter struct {
+	http.ResponseWriter
+
+	status int
+	size   int
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	n, err := w.ResponseWriter.Write(b)
+	w.size += n
+	return n, err
+}
+
+// Status returns the status code sent, or 200 if
+// nothing was written
+func (w *responseWriter) Status() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+// Unwrap gives http.ResponseController access to the
+// original http.ResponseWriter
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
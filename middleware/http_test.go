@@ -0,0 +1,134 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"darvaza.org/slog"
+	slogtest "darvaza.org/slog/internal/testing"
+)
+
+func serve(h http.Handler, req *http.Request) *httptest.ResponseRecorder {
+	rw := httptest.NewRecorder()
+	h.ServeHTTP(rw, req)
+	return rw
+}
+
+func TestHTTPHandler(t *testing.T) {
+	rec := slogtest.NewRecorder()
+	h := NewHTTPHandler(rec, http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(rw, "hello")
+	}))
+	h.LogStart = true
+
+	req := httptest.NewRequest(http.MethodGet, "/hello?x=1", nil)
+	req.Header.Set(DefaultRequestIDHeader, "abc")
+	if rw := serve(h, req); rw.Body.String() != "hello" {
+		t.Errorf("unexpected body %q", rw.Body)
+	}
+
+	msgs := rec.Messages()
+	slogtest.AssertMessagesInOrder(t, msgs,
+		slogtest.Expect().Level(slog.Info).Message("request started").
+			Field(MethodFieldName, http.MethodGet).
+			Field(PathFieldName, "/hello").
+			Field(RequestIDFieldName, "abc").
+			NoField(StatusFieldName),
+		slogtest.Expect().Level(slog.Info).Message("request completed").
+			Field(RemoteAddrFieldName, req.RemoteAddr).
+			Field(StatusFieldName, http.StatusOK).
+			Field(SizeFieldName, 5).
+			HasField(DurationFieldName).
+			NoField(ProtoFieldName))
+
+	if d, ok := msgs[1].Fields[DurationFieldName].(time.Duration); !ok || d < 0 {
+		t.Errorf("unexpected duration %v", msgs[1].Fields[DurationFieldName])
+	}
+}
+
+func TestHTTPHandlerLevels(t *testing.T) {
+	rec := slogtest.NewRecorder()
+	h := &HTTPHandler{
+		Logger: rec,
+		Next: http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			if req.URL.Path == "/fail" {
+				rw.WriteHeader(http.StatusBadGateway)
+				// ignored
+				rw.WriteHeader(http.StatusOK)
+			}
+		}),
+		Level: slog.Warn,
+		RouteLevels: map[string]slog.LogLevel{
+			"/health":       slog.Debug,
+			"/health/ready": slog.Info,
+		},
+		RequestIDHeader: "X-Trace",
+	}
+
+	for _, path := range []string{"/", "/health", "/health/ready", "/fail"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set(DefaultRequestIDHeader, "ignored")
+		serve(h, req)
+	}
+
+	slogtest.AssertMessagesInOrder(t, rec.Messages(),
+		slogtest.Expect().Level(slog.Warn).Field(PathFieldName, "/").
+			Field(StatusFieldName, http.StatusOK).
+			Field(SizeFieldName, 0).
+			NoField(RequestIDFieldName),
+		slogtest.Expect().Level(slog.Debug).Field(PathFieldName, "/health"),
+		slogtest.Expect().Level(slog.Info).Field(PathFieldName, "/health/ready"),
+		// 5xx always as Error
+		slogtest.Expect().Level(slog.Error).Field(PathFieldName, "/fail").
+			Field(StatusFieldName, http.StatusBadGateway))
+}
+
+func TestHTTPHandlerWithoutLogger(t *testing.T) {
+	h := NewHTTPHandler(nil, nil)
+
+	rw := serve(h, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rw.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", rw.Code)
+	}
+}
+
+func TestHTTPHandlerTrace(t *testing.T) {
+	const (
+		traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+		spanID  = "00f067aa0ba902b7"
+	)
+	header := "00-" + traceID + "-" + spanID + "-01"
+
+	tc, err := slog.ParseTraceParent("00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := slogtest.NewRecorder()
+	h := NewHTTPHandler(rec, http.NotFoundHandler())
+
+	// from the header
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(slog.TraceParentHeader, header)
+	serve(h, req)
+
+	// the context wins
+	req = req.WithContext(slog.WithTraceContext(req.Context(), tc))
+	serve(h, req)
+
+	// invalid header
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(slog.TraceParentHeader, "invalid")
+	serve(h, req)
+
+	slogtest.AssertMessagesInOrder(t, rec.Messages(),
+		slogtest.Expect().Field(slog.TraceIDFieldName, traceID).
+			Field(slog.SpanIDFieldName, spanID),
+		slogtest.Expect().Field(slog.TraceIDFieldName, tc.TraceID.String()).
+			Field(slog.SpanIDFieldName, tc.SpanID.String()),
+		slogtest.Expect().NoField(slog.TraceIDFieldName).
+			NoField(slog.SpanIDFieldName))
+}
@@ -0,0 +1,29 @@
+// Package middleware provides helpers to log requests of
+// network services using a slog.Logger
+package middleware
+
+const (
+	// MethodFieldName is the field label for the request method
+	MethodFieldName = "method"
+	// PathFieldName is the field label for the request path
+	PathFieldName = "path"
+	// StatusFieldName is the field label for the response status
+	StatusFieldName = "status"
+	// SizeFieldName is the field label for the size of the response body
+	SizeFieldName = "size"
+	// DurationFieldName is the field label for the time it took to
+	// handle the request
+	DurationFieldName = "duration"
+	// RemoteAddrFieldName is the field label for the address of the client
+	RemoteAddrFieldName = "remote_addr"
+	// RequestIDFieldName is the field label for the request ID
+	RequestIDFieldName = "request_id"
+	// ProtoFieldName is the field label for the protocol of the request
+	ProtoFieldName = "proto"
+	// RefererFieldName is the field label for the Referer header
+	RefererFieldName = "referer"
+	// UserAgentFieldName is the field label for the User-Agent header
+	UserAgentFieldName = "user_agent"
+	// UserFieldName is the field label for the authenticated user
+	UserFieldName = "user"
+)
@@ -0,0 +1,40 @@
+package slog
+
+import "strings"
+
+// NamedLogger is implemented by loggers that keep track of
+// a hierarchical, dot-separated, name
+type NamedLogger interface {
+	// WithName appends a component to the name of the logger
+	WithName(name string) Logger
+}
+
+// WithName appends a component to the dot-separated name of
+// a logger. It uses the logger's own WithName() if implemented,
+// or attaches the given name as NameFieldName field otherwise.
+func WithName(l Logger, name string) Logger {
+	if name == "" {
+		return l
+	}
+
+	if nl, ok := l.(NamedLogger); ok {
+		return nl.WithName(name)
+	}
+	return l.WithField(NameFieldName, name)
+}
+
+// JoinName joins the components of a logger name using dots,
+// skipping empty ones
+func JoinName(names ...string) string {
+	var sb strings.Builder
+	for _, s := range names {
+		if s == "" {
+			continue
+		}
+		if sb.Len() > 0 {
+			_ = sb.WriteByte('.')
+		}
+		_, _ = sb.WriteString(s)
+	}
+	return sb.String()
+}
@@ -0,0 +1,28 @@
+package slog
+
+import "testing"
+
+func TestJoinName(t *testing.T) {
+	for _, tc := range []struct {
+		expected string
+		names    []string
+	}{
+		{"", nil},
+		{"", []string{"", ""}},
+		{"a", []string{"", "a", ""}},
+		{"a.b.c", []string{"a", "", "b", "c"}},
+	} {
+		if got := JoinName(tc.names...); got != tc.expected {
+			t.Errorf("JoinName(%q): expected %q, got %q", tc.names, tc.expected, got)
+		}
+	}
+}
+
+func TestJoinNameKeepsArguments(t *testing.T) {
+	names := []string{"", "a", "", "b"}
+	_ = JoinName(names...)
+
+	if names[0] != "" || names[1] != "a" || names[2] != "" || names[3] != "b" {
+		t.Errorf("JoinName modified its arguments: %q", names)
+	}
+}
@@ -0,0 +1,23 @@
+package slog
+
+// Peeker is implemented by loggers that can tell if entries of
+// a level would be logged without creating a logger for it
+type Peeker interface {
+	// Peek tells if entries of the given level would be logged
+	Peek(level LogLevel) bool
+}
+
+// Peek tells if a logger would log entries of the given level, for
+// hot call sites guarding expensive work. It uses the logger's own
+// Peek() if implemented, or WithLevel(level).Enabled() otherwise.
+func Peek(l Logger, level LogLevel) bool {
+	switch {
+	case l == nil, level <= UndefinedLevel:
+		return false
+	default:
+		if p, ok := l.(Peeker); ok {
+			return p.Peek(level)
+		}
+		return l.WithLevel(level).Enabled()
+	}
+}
@@ -0,0 +1,58 @@
+package slog
+
+import (
+	"darvaza.org/core"
+)
+
+// ComponentFieldName is the field label used by Recover
+// to identify where the panic was recovered
+const ComponentFieldName = "component"
+
+// Recover is meant to be deferred. It recovers from a panic and logs it,
+// with a call stack, at Panic level before resuming normal execution.
+// The panic raised by the handler when logging at Panic level
+// is also recovered.
+//
+// e.g.
+// defer slog.Recover(logger, "worker")
+func Recover(l Logger, component string) {
+	if rvr := recover(); rvr != nil {
+		logRecovered(l, component, rvr)
+	}
+}
+
+// RecoverAndPanic is meant to be deferred. It logs a panic like Recover,
+// but then continues panicking with a [core.PanicError] carrying the
+// original payload and call stack.
+func RecoverAndPanic(l Logger, component string) {
+	if rvr := recover(); rvr != nil {
+		err := logRecovered(l, component, rvr)
+		panic(err)
+	}
+}
+
+func logRecovered(l Logger, component string, rvr any) *core.PanicError {
+	err, ok := rvr.(*core.PanicError)
+	if !ok {
+		// skip logRecovered, Recover and the runtime's panic
+		err = core.NewPanicError(3, rvr)
+	}
+
+	if l != nil {
+		logPanicError(l, component, err)
+	}
+	return err
+}
+
+func logPanicError(l Logger, component string, err *core.PanicError) {
+	defer func() {
+		// handlers panic when logging at Panic level
+		_ = recover()
+	}()
+
+	l = l.Panic().WithStack(3).WithField(ErrorFieldName, err)
+	if component != "" {
+		l = l.WithField(ComponentFieldName, component)
+	}
+	l.Printf("panic recovered: %v", err.Recovered())
+}
@@ -0,0 +1,142 @@
+package slog
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"darvaza.org/core"
+)
+
+var ctxScopeFieldsKey = core.NewContextKey[Fields]("scope-fields")
+
+var (
+	// scopes holds the stacks of fields pushed by each goroutine,
+	// keyed by goroutine id
+	scopes sync.Map
+	// scoped is the number of goroutines with fields pushed, so
+	// the goroutine id is only looked up when there are some
+	scoped atomic.Int64
+)
+
+// PushScopeFields attaches fields to the current goroutine, to be
+// added to the entries of loggers wrapped by the scope handler until
+// a matching PopScopeFields, which should be deferred. Goroutines
+// started afterwards don't inherit them, and a goroutine exiting
+// without popping leaves them behind, see RunWithScopeFields.
+//
+// e.g.
+//
+//	slog.PushScopeFields(slog.Fields{"request_id": id})
+//	defer slog.PopScopeFields()
+func PushScopeFields(fields Fields) {
+	id := goroutineID()
+
+	var stack []Fields
+	if v, ok := scopes.Load(id); ok {
+		stack = v.([]Fields)
+	} else {
+		scoped.Add(1)
+	}
+
+	// copy on write, readers may hold the previous one
+	next := make([]Fields, len(stack), len(stack)+1)
+	copy(next, stack)
+	scopes.Store(id, append(next, fields.Clone()))
+}
+
+// PopScopeFields removes the fields last pushed by the current
+// goroutine using PushScopeFields
+func PopScopeFields() {
+	id := goroutineID()
+
+	v, ok := scopes.Load(id)
+	if !ok {
+		return
+	}
+
+	if stack := v.([]Fields); len(stack) > 1 {
+		scopes.Store(id, stack[:len(stack)-1:len(stack)-1])
+	} else {
+		scopes.Delete(id)
+		scoped.Add(-1)
+	}
+}
+
+// RunWithScopeFields calls fn with the given fields pushed to
+// the current goroutine, popping them when it returns or panics
+func RunWithScopeFields(fields Fields, fn func()) {
+	PushScopeFields(fields)
+	defer PopScopeFields()
+
+	fn()
+}
+
+// WithScopeFields returns a copy of the context carrying the given
+// fields in addition to those it already carries, the new ones taking
+// precedence. Loggers wrapped by the scope handler add them to the
+// entries logged on behalf of the context, see WithContext. Unlike
+// PushScopeFields, they go away with the context, and reach the
+// goroutines it's passed to.
+//
+// e.g.
+//
+//	ctx = slog.WithScopeFields(ctx, slog.Fields{"request_id": id})
+//	logger = slog.WithContext(ctx, logger)
+func WithScopeFields(ctx context.Context, fields Fields) context.Context {
+	if len(fields) == 0 {
+		return ctx
+	}
+
+	// Merge copies unless one side is empty, and the caller
+	// may modify theirs afterwards
+	out, _ := ctxScopeFieldsKey.Get(ctx)
+	if len(out) == 0 {
+		out = fields.Clone()
+	} else {
+		out = out.Merge(fields)
+	}
+	return ctxScopeFieldsKey.WithValue(ctx, out)
+}
+
+// ScopeFields returns the fields pushed by the current goroutine,
+// those pushed last taking precedence, merged with those carried
+// by the context, if given, which take precedence over them.
+// It returns nil if there are none. The result shouldn't be
+// modified.
+func ScopeFields(ctx context.Context) Fields {
+	var out Fields
+
+	if scoped.Load() > 0 {
+		if v, ok := scopes.Load(goroutineID()); ok {
+			for _, fields := range v.([]Fields) {
+				out = out.Merge(fields)
+			}
+		}
+	}
+
+	if ctx != nil {
+		if fields, ok := ctxScopeFieldsKey.Get(ctx); ok {
+			out = out.Merge(fields)
+		}
+	}
+	return out
+}
+
+// goroutineID returns the id of the current goroutine, taken
+// from the header of its stack trace, "goroutine N [...".
+func goroutineID() uint64 {
+	var buf [64]byte
+
+	b := buf[:runtime.Stack(buf[:], false)]
+	b = bytes.TrimPrefix(b, []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i > 0 {
+		b = b[:i]
+	}
+
+	id, _ := strconv.ParseUint(string(b), 10, 64)
+	return id
+}
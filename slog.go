@@ -23,6 +23,22 @@ const (
 
 	// ErrorFieldName is the preferred field label for errors
 	ErrorFieldName = "error"
+	// CallerFieldName is the preferred field label for the
+	// function that added the log entry
+	CallerFieldName = "caller"
+	// NameFieldName is the preferred field label for the
+	// dot-separated name of the logger
+	NameFieldName = "logger"
+	// TimeFieldName is the preferred field label for the
+	// time of the event, when the handler can't record it
+	TimeFieldName = "time"
+	// StackFieldName is the preferred field label for a
+	// call stack, when the handler can't attach it
+	StackFieldName = "stack"
+	// StreamFieldName is the preferred field label for the
+	// output of another process an entry was read from,
+	// e.g. "stdout" or "stderr"
+	StreamFieldName = "stream"
 )
 
 // Logger is a backend agnostic interface for structured logs
@@ -0,0 +1,145 @@
+package slogconfig
+
+import (
+	"errors"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"darvaza.org/slog"
+	"darvaza.org/slog/handlers/chain"
+	"darvaza.org/slog/handlers/filter"
+	"darvaza.org/slog/handlers/levels"
+	"darvaza.org/slog/handlers/sampling"
+	"darvaza.org/slog/handlers/stdlog"
+)
+
+// Build assembles the slog.Logger described by the Config.
+// Files are opened for appending and remain open for the life
+// of the process.
+func (cfg *Config) Build() (slog.Logger, error) {
+	l, _, err := cfg.build(nil)
+	return l, err
+}
+
+// build assembles the slog.Logger described by the Config, and
+// returns the opened files, if any, so Reloader can close them.
+// If a levels.Registry is given it's updated and used instead
+// of a new one.
+func (cfg *Config) build(reg *levels.Registry) (slog.Logger, io.Closer, error) {
+	c := cfg.clone()
+	c.SetDefaults()
+	if err := c.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	l, f, err := buildOutputs(c.Outputs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if s := c.Sampling; s != nil {
+		l = sampling.New(l, &sampling.Config{
+			First:      s.First,
+			Thereafter: s.Thereafter,
+			Tick:       time.Duration(s.Tick),
+		})
+	}
+
+	if f := c.Filter; f != nil {
+		l = &filter.Logger{
+			Parent:      l,
+			Threshold:   slog.Debug,
+			AllowFields: f.AllowFields,
+			DenyFields:  f.DenyFields,
+		}
+	}
+
+	if reg == nil {
+		reg = levels.NewRegistry(c.levels())
+	} else {
+		reg.Set(c.levels())
+	}
+	return levels.New(l, reg), f, nil
+}
+
+// levels returns the thresholds of the validated Config
+func (cfg *Config) levels() *levels.Config {
+	def, _ := slog.ParseLevel(cfg.Level)
+
+	m := make(map[string]slog.LogLevel, len(cfg.Levels))
+	for name, s := range cfg.Levels {
+		m[name], _ = slog.ParseLevel(s)
+	}
+	return levels.NewConfig(def, m)
+}
+
+// buildOutputs builds the validated Outputs, passing entries to all
+// of them, the first being the primary as described by chain.NewMulti
+func buildOutputs(outputs []Output) (slog.Logger, io.Closer, error) {
+	loggers := make([]slog.Logger, 0, len(outputs))
+	var files closers
+
+	for _, o := range outputs {
+		l, f, err := o.build()
+		if err != nil {
+			_ = files.Close()
+			return nil, nil, err
+		}
+
+		loggers = append(loggers, l)
+		if f != nil {
+			files = append(files, f)
+		}
+	}
+
+	l := chain.NewMulti(loggers[0], loggers[1:]...)
+	if len(files) == 0 {
+		return l, nil, nil
+	}
+	return l, files, nil
+}
+
+// closers closes many files at once
+type closers []io.Closer
+
+// Close closes all of them, joining the errors
+func (cc closers) Close() error {
+	var errs []error
+	for _, c := range cc {
+		if err := c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (o Output) build() (slog.Logger, io.Closer, error) {
+	w, f, err := o.open()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if o.Format == FormatJSON {
+		return stdlog.NewJSON(log.New(w, "", 0)), f, nil
+	}
+	return stdlog.New(log.New(w, "", log.LstdFlags)), f, nil
+}
+
+// open returns the io.Writer of the Output, and
+// the same as io.Closer if it's a file
+func (o Output) open() (io.Writer, io.Closer, error) {
+	switch o.Type {
+	case OutputStdout:
+		return os.Stdout, nil, nil
+	case OutputFile:
+		f, err := os.OpenFile(o.Path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+		if err != nil {
+			return nil, nil, err
+		}
+		return f, f, nil
+	default:
+		return os.Stderr, nil, nil
+	}
+}
@@ -0,0 +1,229 @@
+// Package slogconfig builds slog.Logger handler stacks from
+// declarative configuration
+package slogconfig
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"darvaza.org/core"
+	"darvaza.org/slog"
+)
+
+// Output types
+const (
+	OutputStderr = "stderr"
+	OutputStdout = "stdout"
+	OutputFile   = "file"
+)
+
+// Output formats
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Config describes a handler stack
+type Config struct {
+	// Levels sets thresholds per logger name, see slog.WithName()
+	Levels map[string]string `json:"levels,omitempty" yaml:"levels,omitempty"`
+	// Filter, if set, removes fields
+	Filter *Filter `json:"filter,omitempty" yaml:"filter,omitempty"`
+	// Sampling, if set, limits repeated entries
+	Sampling *Sampling `json:"sampling,omitempty" yaml:"sampling,omitempty"`
+
+	// Outputs describe where entries are written, each one
+	// getting all of them. Defaults to a single Output on stderr
+	Outputs []Output `json:"outputs,omitempty" yaml:"outputs,omitempty"`
+
+	// Level is the threshold of loggers not matching Levels.
+	// Defaults to "info"
+	Level string `json:"level,omitempty" yaml:"level,omitempty"`
+}
+
+// Output describes where and how entries are written
+type Output struct {
+	// Type is "stderr", "stdout" or "file". Defaults to "stderr"
+	Type string `json:"type,omitempty" yaml:"type,omitempty"`
+	// Path is the name of the file when Type is "file"
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+	// Format is "text" or "json". Defaults to "text"
+	Format string `json:"format,omitempty" yaml:"format,omitempty"`
+}
+
+// Filter describes which fields reach the output
+type Filter struct {
+	AllowFields []string `json:"allow_fields,omitempty" yaml:"allow_fields,omitempty"`
+	DenyFields  []string `json:"deny_fields,omitempty" yaml:"deny_fields,omitempty"`
+}
+
+// Sampling describes how repeated entries are limited
+type Sampling struct {
+	// Tick is the period, e.g. "1s"
+	Tick       Duration `json:"tick,omitempty" yaml:"tick,omitempty"`
+	First      int      `json:"first" yaml:"first"`
+	Thereafter int      `json:"thereafter,omitempty" yaml:"thereafter,omitempty"`
+}
+
+// Duration is a time.Duration written as a string like "1m30s"
+type Duration time.Duration
+
+// MarshalText renders the Duration as a string
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// UnmarshalText parses a string like "1m30s"
+func (d *Duration) UnmarshalText(b []byte) error {
+	v, err := time.ParseDuration(string(b))
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// configFields is Config without methods, to decode
+// without recursion
+type configFields Config
+
+// UnmarshalJSON decodes and validates a Config
+func (cfg *Config) UnmarshalJSON(b []byte) error {
+	var v configFields
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	return cfg.set(Config(v))
+}
+
+// UnmarshalYAML decodes and validates a Config
+func (cfg *Config) UnmarshalYAML(node *yaml.Node) error {
+	var v configFields
+	if err := node.Decode(&v); err != nil {
+		return err
+	}
+	return cfg.set(Config(v))
+}
+
+func (cfg *Config) set(v Config) error {
+	v.SetDefaults()
+	if err := v.Validate(); err != nil {
+		return err
+	}
+	*cfg = v
+	return nil
+}
+
+// SetDefaults sets the Info level and a single output if not
+// specified, and the defaults of each output
+func (cfg *Config) SetDefaults() {
+	if cfg.Level == "" {
+		cfg.Level = slog.Info.String()
+	}
+	if len(cfg.Outputs) == 0 {
+		cfg.Outputs = []Output{{}}
+	}
+	for i := range cfg.Outputs {
+		cfg.Outputs[i].SetDefaults()
+	}
+}
+
+// clone returns a copy of the Config whose Outputs can be
+// modified without affecting the original
+func (cfg *Config) clone() Config {
+	c := *cfg
+	c.Outputs = slices.Clone(cfg.Outputs)
+	return c
+}
+
+// SetDefaults fills the gaps in the Output
+func (o *Output) SetDefaults() {
+	if o.Type == "" {
+		o.Type = OutputStderr
+	}
+	if o.Format == "" {
+		o.Format = FormatText
+	}
+}
+
+// Validate checks the Config can be built
+func (cfg *Config) Validate() error {
+	if _, err := slog.ParseLevel(cfg.Level); err != nil {
+		return err
+	}
+
+	for name, level := range cfg.Levels {
+		if _, err := slog.ParseLevel(level); err != nil {
+			return core.Wrapf(err, "levels: %q", name)
+		}
+	}
+
+	for i := range cfg.Outputs {
+		if err := cfg.Outputs[i].Validate(); err != nil {
+			return core.Wrapf(err, "outputs[%d]", i)
+		}
+	}
+
+	if s := cfg.Sampling; s != nil && (s.First < 0 || s.Thereafter < 0 || s.Tick < 0) {
+		return core.Wrap(core.ErrInvalid, "sampling: negative values")
+	}
+	return nil
+}
+
+// Validate checks the Output can be opened
+func (o *Output) Validate() error {
+	switch o.Type {
+	case OutputStderr, OutputStdout:
+	case OutputFile:
+		if o.Path == "" {
+			return core.Wrap(core.ErrInvalid, "path not specified")
+		}
+	default:
+		return core.Wrapf(core.ErrInvalid, "unsupported type %q", o.Type)
+	}
+
+	switch o.Format {
+	case FormatText, FormatJSON:
+	default:
+		return core.Wrapf(core.ErrInvalid, "unsupported format %q", o.Format)
+	}
+	return nil
+}
+
+// ParseJSON decodes a Config from JSON
+func ParseJSON(b []byte) (*Config, error) {
+	cfg := new(Config)
+	if err := json.Unmarshal(b, cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// ParseYAML decodes a Config from YAML
+func ParseYAML(b []byte) (*Config, error) {
+	cfg := new(Config)
+	if err := yaml.Unmarshal(b, cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Load reads a Config from a file, decoded as JSON if the
+// name ends in ".json", or YAML otherwise
+func Load(filename string) (*Config, error) {
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.EqualFold(filepath.Ext(filename), ".json") {
+		return ParseJSON(b)
+	}
+	return ParseYAML(b)
+}
@@ -0,0 +1,144 @@
+package slogconfig
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"darvaza.org/core"
+	"darvaza.org/slog"
+)
+
+func TestDefaults(t *testing.T) {
+	cfg, err := ParseYAML([]byte("levels:\n  proxy: debug\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Level != "info" {
+		t.Errorf("expected level info, got %q", cfg.Level)
+	}
+	want := []Output{{Type: OutputStderr, Format: FormatText}}
+	if len(cfg.Outputs) != 1 || cfg.Outputs[0] != want[0] {
+		t.Errorf("expected %v, got %v", want, cfg.Outputs)
+	}
+}
+
+func TestParseOutputs(t *testing.T) {
+	const doc = `
+outputs:
+  - type: file
+    path: /var/log/app.log
+    format: json
+  - type: stdout
+`
+	cfg, err := ParseYAML([]byte(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Output{
+		{Type: OutputFile, Path: "/var/log/app.log", Format: FormatJSON},
+		{Type: OutputStdout, Format: FormatText},
+	}
+	if len(cfg.Outputs) != len(want) {
+		t.Fatalf("expected %d outputs, got %v", len(want), cfg.Outputs)
+	}
+	for i, o := range want {
+		if cfg.Outputs[i] != o {
+			t.Errorf("outputs[%d]: expected %v, got %v", i, o, cfg.Outputs[i])
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, doc := range []string{
+		`{"level": "loud"}`,
+		`{"levels": {"proxy": "loud"}}`,
+		`{"outputs": [{}, {"type": "file"}]}`,
+		`{"outputs": [{"type": "syslog"}]}`,
+		`{"outputs": [{"format": "xml"}]}`,
+		`{"sampling": {"first": -1}}`,
+	} {
+		if _, err := ParseJSON([]byte(doc)); err == nil {
+			t.Errorf("%s: accepted", doc)
+		}
+	}
+
+	_, err := ParseJSON([]byte(`{"outputs": [{}, {"type": "file"}]}`))
+	if !errors.Is(err, core.ErrInvalid) || !strings.Contains(err.Error(), "outputs[1]") {
+		t.Errorf("error doesn't point at the output: %v", err)
+	}
+}
+
+func TestDefaultsDontModifyCaller(t *testing.T) {
+	cfg := &Config{Outputs: []Output{{Type: OutputStdout}}}
+	if _, err := cfg.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Outputs[0].Format != "" {
+		t.Error("Build modified the given Config")
+	}
+}
+
+func TestBuildOutputs(t *testing.T) {
+	dir := t.TempDir()
+	text := filepath.Join(dir, "app.log")
+	jsonl := filepath.Join(dir, "app.json")
+
+	r, err := NewReloader(&Config{
+		Level:  "warn",
+		Levels: map[string]string{"proxy": "debug"},
+		Filter: &Filter{DenyFields: []string{"password"}},
+		Outputs: []Output{
+			{Type: OutputFile, Path: text},
+			{Type: OutputFile, Path: jsonl, Format: FormatJSON},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l := r.Logger()
+	l.Info().Print("dropped")
+	l.Warn().WithField("password", "secret").Print("kept")
+	slog.WithName(l, "proxy").Debug().Print("named")
+
+	// closes the files
+	if err := r.Reload(&Config{}); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := readLines(t, text)
+	if len(lines) != 2 ||
+		!strings.Contains(lines[0], "kept") || !strings.Contains(lines[1], "named") {
+		t.Fatalf("unexpected text output: %q", lines)
+	}
+	if strings.Contains(lines[0], "secret") {
+		t.Error("denied field written")
+	}
+
+	lines = readLines(t, jsonl)
+	if len(lines) != 2 {
+		t.Fatalf("unexpected json output: %q", lines)
+	}
+	for _, line := range lines {
+		var v map[string]any
+		if err := json.Unmarshal([]byte(line), &v); err != nil {
+			t.Errorf("%q: %v", line, err)
+		}
+	}
+}
+
+func readLines(t *testing.T, filename string) []string {
+	t.Helper()
+
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Split(strings.TrimSpace(string(b)), "\n")
+}
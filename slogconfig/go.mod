@@ -0,0 +1,28 @@
+module darvaza.org/slog/slogconfig
+
+go 1.22
+
+replace (
+	darvaza.org/slog => ../
+	darvaza.org/slog/handlers/chain => ../handlers/chain
+	darvaza.org/slog/handlers/filter => ../handlers/filter
+	darvaza.org/slog/handlers/levels => ../handlers/levels
+	darvaza.org/slog/handlers/sampling => ../handlers/sampling
+	darvaza.org/slog/handlers/stdlog => ../handlers/stdlog
+)
+
+require (
+	darvaza.org/core v0.16.0
+	darvaza.org/slog v0.6.0
+	darvaza.org/slog/handlers/chain v0.0.0
+	darvaza.org/slog/handlers/filter v0.0.0
+	darvaza.org/slog/handlers/levels v0.0.0
+	darvaza.org/slog/handlers/sampling v0.0.0
+	darvaza.org/slog/handlers/stdlog v0.0.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	golang.org/x/net v0.34.0 // indirect
+	golang.org/x/text v0.21.0 // indirect
+)
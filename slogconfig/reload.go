@@ -0,0 +1,339 @@
+package slogconfig
+
+import (
+	"io"
+	"sync/atomic"
+
+	"darvaza.org/slog"
+	"darvaza.org/slog/handlers/levels"
+	"darvaza.org/slog/internal"
+)
+
+var (
+	_ slog.Logger       = (*Logger)(nil)
+	_ slog.CallerLogger = (*Logger)(nil)
+	_ slog.NamedLogger  = (*Logger)(nil)
+)
+
+// Reloader holds the handler stack built from a Config,
+// allowing it to be replaced while loggers derived from it
+// are in use
+type Reloader struct {
+	target atomic.Pointer[target]
+	levels *levels.Registry
+}
+
+// target is one generation of the handler stack
+type target struct {
+	logger slog.Logger
+	closer io.Closer
+	config Config
+
+	// refs counts the entries being written plus one held by
+	// the Reloader while it's the current generation. The file
+	// is closed when it drops to zero.
+	refs atomic.Int64
+}
+
+func newTarget(l slog.Logger, f io.Closer, cfg *Config) *target {
+	t := &target{
+		logger: l,
+		closer: f,
+		config: cfg.clone(),
+	}
+	t.config.SetDefaults()
+	t.refs.Store(1)
+	return t
+}
+
+// acquire prevents the file from being closed while writing,
+// failing if the generation was retired and it's already closed
+func (t *target) acquire() bool {
+	for {
+		n := t.refs.Load()
+		if n <= 0 {
+			return false
+		}
+		if t.refs.CompareAndSwap(n, n+1) {
+			return true
+		}
+	}
+}
+
+// release undoes acquire, closing the file if the generation
+// was retired and this was the last writer
+func (t *target) release() error {
+	if t.refs.Add(-1) == 0 && t.closer != nil {
+		return t.closer.Close()
+	}
+	return nil
+}
+
+// replay applies the recorded context to the handler stack
+func (t *target) replay(ll *internal.Loglet) slog.Logger {
+	l := t.logger
+	if level := ll.Level(); level != slog.UndefinedLevel {
+		l = l.WithLevel(level)
+	}
+	if name := ll.Name(); name != "" {
+		l = slog.WithName(l, name)
+	}
+	if st := ll.CallStack(); st != nil {
+		l = slog.WithCallStack(l, st)
+	}
+
+	if ll.FieldsCount() > 0 {
+		fields := ll.FieldsMap()
+		delete(fields, slog.NameFieldName)
+		if len(fields) > 0 {
+			l = l.WithFields(fields)
+		}
+	}
+	return l
+}
+
+// entry is a Logger's handler stack entry on a given generation
+type entry struct {
+	t      *target
+	logger slog.Logger
+}
+
+// NewReloader builds the handler stack described by the Config
+func NewReloader(cfg *Config) (*Reloader, error) {
+	r := &Reloader{
+		levels: levels.NewRegistry(nil),
+	}
+	if err := r.Reload(cfg); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload builds a new handler stack and atomically replaces
+// the current one. The file it was writing to, if any, is closed
+// once the entries being written to it are done, by Reload itself
+// if there are none, in which case the error is returned.
+// On error the current stack remains in use.
+func (r *Reloader) Reload(cfg *Config) error {
+	l, f, err := cfg.build(r.levels)
+	if err != nil {
+		return err
+	}
+
+	return r.replace(newTarget(l, f, cfg))
+}
+
+// replace makes t the current generation, retiring the previous one
+func (r *Reloader) replace(t *target) error {
+	if old := r.target.Swap(t); old != nil {
+		return old.release()
+	}
+	return nil
+}
+
+// ReloadFile loads a Config from a file and applies it
+// using Reload
+func (r *Reloader) ReloadFile(filename string) error {
+	cfg, err := Load(filename)
+	if err != nil {
+		return err
+	}
+	return r.Reload(cfg)
+}
+
+// Levels returns the levels.Registry holding the thresholds,
+// shared by all reloads, so components can Watch() them
+func (r *Reloader) Levels() *levels.Registry {
+	return r.levels
+}
+
+// Config returns a copy of the Config currently in use
+func (r *Reloader) Config() Config {
+	return r.target.Load().config
+}
+
+// Logger returns a slog.Logger that always uses the
+// current handler stack, even after being derived
+func (r *Reloader) Logger() slog.Logger {
+	t := r.target.Load()
+	l := &Logger{r: r}
+	l.entry.Store(&entry{t: t, logger: t.logger})
+	return l
+}
+
+// Logger is a slog.Logger using the handler stack of
+// a Reloader. The context is recorded so it can be applied
+// again when the handler stack is replaced.
+type Logger struct {
+	internal.Loglet
+
+	r *Reloader
+	// entry is the last one used, rebuilt once per generation
+	entry atomic.Pointer[entry]
+}
+
+// current returns the entry on the current handler stack,
+// replaying the context if it was derived from an old one
+func (l *Logger) current() *entry {
+	t := l.r.target.Load()
+	if e := l.entry.Load(); e.t == t {
+		return e
+	}
+
+	e := &entry{t: t, logger: t.replay(&l.Loglet)}
+	l.entry.Store(e)
+	return e
+}
+
+// print calls fn with the entry on the current handler stack,
+// keeping its file open until it returns
+func (l *Logger) print(fn func(slog.Logger)) {
+	for {
+		e := l.current()
+		if e.t.acquire() {
+			defer func() { _ = e.t.release() }()
+			fn(e.logger)
+			return
+		}
+	}
+}
+
+// next derives a Logger on the current handler stack
+func (l *Logger) next(ll internal.Loglet, fn func(slog.Logger) slog.Logger) *Logger {
+	e := l.current()
+	out := &Logger{
+		Loglet: ll,
+		r:      l.r,
+	}
+	out.entry.Store(&entry{t: e.t, logger: fn(e.logger)})
+	return out
+}
+
+// Enabled tells if the current handler stack would
+// log this entry
+func (l *Logger) Enabled() bool {
+	return l.current().logger.Enabled()
+}
+
+// WithEnabled passes the logger and if it's enabled
+func (l *Logger) WithEnabled() (slog.Logger, bool) {
+	return l, l.Enabled()
+}
+
+// Print adds a log entry with arguments handled in the manner of fmt.Print
+func (l *Logger) Print(args ...any) {
+	l.print(func(p slog.Logger) {
+		p.Print(args...)
+	})
+}
+
+// Println adds a log entry with arguments handled in the manner of fmt.Println
+func (l *Logger) Println(args ...any) {
+	l.print(func(p slog.Logger) {
+		p.Println(args...)
+	})
+}
+
+// Printf adds a log entry with arguments handled in the manner of fmt.Printf
+func (l *Logger) Printf(format string, args ...any) {
+	l.print(func(p slog.Logger) {
+		p.Printf(format, args...)
+	})
+}
+
+// Debug returns a new logger set to add entries as level Debug
+func (l *Logger) Debug() slog.Logger {
+	return l.WithLevel(slog.Debug)
+}
+
+// Info returns a new logger set to add entries as level Info
+func (l *Logger) Info() slog.Logger {
+	return l.WithLevel(slog.Info)
+}
+
+// Warn returns a new logger set to add entries as level Warn
+func (l *Logger) Warn() slog.Logger {
+	return l.WithLevel(slog.Warn)
+}
+
+// Error returns a new logger set to add entries as level Error
+func (l *Logger) Error() slog.Logger {
+	return l.WithLevel(slog.Error)
+}
+
+// Fatal returns a new logger set to add entries as level Fatal
+func (l *Logger) Fatal() slog.Logger {
+	return l.WithLevel(slog.Fatal)
+}
+
+// Panic returns a new logger set to add entries as level Panic
+func (l *Logger) Panic() slog.Logger {
+	return l.WithLevel(slog.Panic)
+}
+
+// WithLevel returns a new logger set to add entries to the specified level
+func (l *Logger) WithLevel(level slog.LogLevel) slog.Logger {
+	if level <= slog.UndefinedLevel {
+		// fix your code
+		l.Panic().WithStack(1).Printf("slog: invalid log level %v", level)
+	} else if level == l.Level() {
+		return l
+	}
+
+	return l.next(l.Loglet.WithLevel(level), func(p slog.Logger) slog.Logger {
+		return p.WithLevel(level)
+	})
+}
+
+// WithStack attaches a call stack to a new logger
+func (l *Logger) WithStack(skip int) slog.Logger {
+	return l.next(l.Loglet.WithStack(skip+1), func(p slog.Logger) slog.Logger {
+		return p.WithStack(skip + 3)
+	})
+}
+
+// WithCaller attaches the immediate caller to a new logger,
+// as slog.CallerFieldName field of core.Frame type
+func (l *Logger) WithCaller(skip int) slog.Logger {
+	return l.next(l.Loglet.WithCaller(skip+1), func(p slog.Logger) slog.Logger {
+		return slog.WithCaller(p, skip+3)
+	})
+}
+
+// WithName appends a component to the dot-separated name of a
+// new logger
+func (l *Logger) WithName(name string) slog.Logger {
+	if name == "" {
+		return l
+	}
+
+	ll := l.Loglet.WithName(name)
+	return l.next(ll.Compact(), func(p slog.Logger) slog.Logger {
+		return slog.WithName(p, name)
+	})
+}
+
+// WithField returns a new logger with a field attached
+func (l *Logger) WithField(label string, value any) slog.Logger {
+	if label == "" {
+		return l
+	}
+
+	ll := l.Loglet.WithField(label, value)
+	return l.next(ll.Compact(), func(p slog.Logger) slog.Logger {
+		return p.WithField(label, value)
+	})
+}
+
+// WithFields returns a new logger with a set of fields attached
+func (l *Logger) WithFields(fields map[string]any) slog.Logger {
+	delete(fields, "")
+	if len(fields) == 0 {
+		return l
+	}
+
+	ll := l.Loglet.WithFields(fields)
+	return l.next(ll.Compact(), func(p slog.Logger) slog.Logger {
+		return p.WithFields(fields)
+	})
+}
@@ -0,0 +1,122 @@
+package slogconfig
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"darvaza.org/slog"
+	"darvaza.org/slog/handlers/levels"
+	slogtest "darvaza.org/slog/internal/testing"
+)
+
+// testCloser counts the calls to Close
+type testCloser struct {
+	err    error
+	closed atomic.Int32
+}
+
+func (c *testCloser) Close() error {
+	c.closed.Add(1)
+	return c.err
+}
+
+// hookLogger calls a function before passing entries on
+type hookLogger struct {
+	*slogtest.Recorder
+	hook func()
+}
+
+func (l *hookLogger) Print(args ...any) {
+	l.hook()
+	l.Recorder.Print(args...)
+}
+
+func newTestReloader(l slog.Logger, f *testCloser) *Reloader {
+	r := &Reloader{levels: levels.NewRegistry(nil)}
+	if f != nil {
+		_ = r.replace(newTarget(l, f, &Config{}))
+	} else {
+		_ = r.replace(newTarget(l, nil, &Config{}))
+	}
+	return r
+}
+
+func TestReloadReplaysOnce(t *testing.T) {
+	old := slogtest.NewRecorder()
+	r := newTestReloader(old, nil)
+	l := r.Logger().WithField("key", "value").Warn()
+
+	rec := slogtest.NewRecorder()
+	if err := r.replace(newTarget(rec, nil, &Config{})); err != nil {
+		t.Fatal(err)
+	}
+
+	l.Print("first")
+	e := l.(*Logger).entry.Load()
+	l.Print("second")
+	if l.(*Logger).entry.Load() != e {
+		t.Error("entry rebuilt on the same generation")
+	}
+
+	if n := len(old.Messages()); n != 0 {
+		t.Errorf("%d entries written to the old generation", n)
+	}
+	slogtest.AssertMessagesInOrder(t, rec.Messages(),
+		slogtest.Expect().Level(slog.Warn).Message("first").Field("key", "value"),
+		slogtest.Expect().Level(slog.Warn).Message("second").Field("key", "value"))
+}
+
+func TestReloadKeepsCallStack(t *testing.T) {
+	r := newTestReloader(slogtest.NewRecorder(), nil)
+	l := r.Logger().Info().WithStack(0)
+
+	rec := slogtest.NewRecorder()
+	_ = r.replace(newTarget(rec, nil, &Config{}))
+	l.Print("replayed")
+
+	msgs := rec.Messages()
+	if len(msgs) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(msgs))
+	}
+	slogtest.AssertStackTop(t, msgs[0], slogtest.FrameMatcher{
+		Func: "TestReloadKeepsCallStack",
+	})
+}
+
+func TestReloadClosesIdle(t *testing.T) {
+	errClose := errors.New("close failed")
+	f := &testCloser{err: errClose}
+	r := newTestReloader(slogtest.NewRecorder(), f)
+
+	err := r.replace(newTarget(slogtest.NewRecorder(), nil, &Config{}))
+	if !errors.Is(err, errClose) {
+		t.Errorf("expected the Close error, got %v", err)
+	}
+	if n := f.closed.Load(); n != 1 {
+		t.Errorf("closed %d times", n)
+	}
+}
+
+func TestReloadWaitsForWriters(t *testing.T) {
+	f := new(testCloser)
+	old := &hookLogger{Recorder: slogtest.NewRecorder()}
+	r := newTestReloader(old, f)
+
+	old.hook = func() {
+		if err := r.replace(newTarget(slogtest.NewRecorder(), nil, &Config{})); err != nil {
+			t.Error(err)
+		}
+		if n := f.closed.Load(); n != 0 {
+			t.Error("closed while writing")
+		}
+	}
+	r.Logger().Print("reloading")
+
+	if n := f.closed.Load(); n != 1 {
+		t.Errorf("closed %d times after writing", n)
+	}
+	if n := len(old.Messages()); n != 1 {
+		t.Errorf("expected 1 entry on the old generation, got %d", n)
+	}
+}
@@ -0,0 +1,208 @@
+// Package slogcontrol provides an http.Handler to inspect and change
+// the thresholds of a running program's loggers
+package slogcontrol
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"darvaza.org/core"
+	"darvaza.org/slog"
+	"darvaza.org/slog/handlers/filter"
+	"darvaza.org/slog/handlers/levels"
+)
+
+var (
+	_ http.Handler = (*Handler)(nil)
+)
+
+// MaxBodySize is the maximum size of the body of a PUT request
+const MaxBodySize = 64 << 10
+
+// State describes the thresholds of the registered loggers.
+// Filter thresholds are levels like "debug", and registries
+// use the format read by levels.Parse().
+type State struct {
+	Filters    map[string]string `json:"filters,omitempty"`
+	Registries map[string]string `json:"registries,omitempty"`
+}
+
+// Handler is an http.Handler exposing the thresholds of registered
+// filter.Logger and levels.Registry instances. GET returns the current
+// State as JSON, and PUT changes those included in the given one,
+// responding with the result. Nothing changes if any entry is invalid.
+//
+// Handler doesn't authenticate requests, and is meant to be mounted
+// on an admin endpoint.
+type Handler struct {
+	mu         sync.Mutex
+	filters    map[string]*filter.Logger
+	registries map[string]*levels.Registry
+}
+
+// New creates an empty Handler
+func New() *Handler {
+	return &Handler{
+		filters:    make(map[string]*filter.Logger),
+		registries: make(map[string]*levels.Registry),
+	}
+}
+
+// AddFilter registers a filter.Logger under the given name,
+// replacing any previous one. Its threshold is changed using
+// SetThreshold(), so OnThresholdChange() observers are notified.
+func (h *Handler) AddFilter(name string, l *filter.Logger) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if l == nil {
+		delete(h.filters, name)
+	} else {
+		h.filters[name] = l
+	}
+}
+
+// AddRegistry registers a levels.Registry under the given name,
+// replacing any previous one
+func (h *Handler) AddRegistry(name string, r *levels.Registry) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if r == nil {
+		delete(h.registries, name)
+	} else {
+		h.registries[name] = r
+	}
+}
+
+// Get returns the current State
+func (h *Handler) Get() *State {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.get()
+}
+
+func (h *Handler) get() *State {
+	s := &State{
+		Filters:    make(map[string]string, len(h.filters)),
+		Registries: make(map[string]string, len(h.registries)),
+	}
+	for name, l := range h.filters {
+		s.Filters[name] = l.CurrentThreshold().String()
+	}
+	for name, r := range h.registries {
+		s.Registries[name] = r.Get().String()
+	}
+	return s
+}
+
+// Set applies the thresholds of the given State to the registered
+// loggers it names, and returns the resulting State. If any name is
+// unknown or any threshold invalid, nothing changes.
+func (h *Handler) Set(s *State) (*State, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	thresholds, configs, err := h.parse(s)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, level := range thresholds {
+		h.filters[name].SetThreshold(level)
+	}
+	for name, cfg := range configs {
+		h.registries[name].Set(cfg)
+	}
+	return h.get(), nil
+}
+
+// parse validates a State before anything is changed
+func (h *Handler) parse(s *State) (map[string]slog.LogLevel, map[string]*levels.Config, error) {
+	var errs []error
+
+	thresholds := make(map[string]slog.LogLevel, len(s.Filters))
+	for _, name := range core.SortedKeys(s.Filters) {
+		if _, ok := h.filters[name]; !ok {
+			errs = append(errs, fmt.Errorf("filter %q: %w", name, core.ErrNotExists))
+			continue
+		}
+
+		level, err := slog.ParseLevel(s.Filters[name])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("filter %q: %w", name, err))
+			continue
+		}
+		thresholds[name] = level
+	}
+
+	configs := make(map[string]*levels.Config, len(s.Registries))
+	for _, name := range core.SortedKeys(s.Registries) {
+		r, ok := h.registries[name]
+		if !ok {
+			errs = append(errs, fmt.Errorf("registry %q: %w", name, core.ErrNotExists))
+			continue
+		}
+
+		// keep the default unless a wildcard is given
+		cfg, err := levels.Parse(s.Registries[name], r.Threshold(""))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("registry %q: %w", name, err))
+			continue
+		}
+		configs[name] = cfg
+	}
+
+	if len(errs) > 0 {
+		return nil, nil, errors.Join(errs...)
+	}
+	return thresholds, configs, nil
+}
+
+// ServeHTTP handles GET and PUT requests
+func (h *Handler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead:
+		writeJSON(rw, http.StatusOK, h.Get())
+	case http.MethodPut:
+		h.servePut(rw, req)
+	default:
+		rw.Header().Set("Allow", "GET, HEAD, PUT")
+		http.Error(rw, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *Handler) servePut(rw http.ResponseWriter, req *http.Request) {
+	var s State
+
+	dec := json.NewDecoder(http.MaxBytesReader(rw, req.Body, MaxBodySize))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&s); err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	out, err := h.Set(&s)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeJSON(rw, http.StatusOK, out)
+}
+
+func writeJSON(rw http.ResponseWriter, code int, v any) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		// unreachable, State only contains strings
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(code)
+	_, _ = rw.Write(append(b, '\n'))
+}
@@ -0,0 +1,118 @@
+package slogcontrol
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"darvaza.org/slog"
+	"darvaza.org/slog/handlers/filter"
+	"darvaza.org/slog/handlers/levels"
+	slogtest "darvaza.org/slog/internal/testing"
+)
+
+func newFilter(threshold slog.LogLevel) *filter.Logger {
+	return filter.New(slogtest.NewDiscardRecorder(), threshold).(*filter.Logger)
+}
+
+func newTestHandler() (*Handler, *filter.Logger, *levels.Registry) {
+	l := newFilter(slog.Info)
+	r := levels.NewRegistry(levels.NewConfig(slog.Warn, map[string]slog.LogLevel{
+		"db": slog.Debug,
+	}))
+
+	h := New()
+	h.AddFilter("main", l)
+	h.AddRegistry("components", r)
+	return h, l, r
+}
+
+func serve(h http.Handler, method, body string) *httptest.ResponseRecorder {
+	rw := httptest.NewRecorder()
+	req := httptest.NewRequest(method, "/", strings.NewReader(body))
+	h.ServeHTTP(rw, req)
+	return rw
+}
+
+func expectResponse(t *testing.T, rw *httptest.ResponseRecorder, code int, body string) {
+	t.Helper()
+
+	if rw.Code != code {
+		t.Errorf("expected status %d, got %d", code, rw.Code)
+	}
+	if got := strings.TrimSpace(rw.Body.String()); got != body {
+		t.Errorf("expected %s, got %s", body, got)
+	}
+}
+
+func TestGet(t *testing.T) {
+	h, _, _ := newTestHandler()
+
+	rw := serve(h, http.MethodGet, "")
+	expectResponse(t, rw, http.StatusOK,
+		`{"filters":{"main":"info"},"registries":{"components":"db=debug,*=warn"}}`)
+	if ct := rw.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("unexpected Content-Type %q", ct)
+	}
+}
+
+func TestPut(t *testing.T) {
+	h, l, r := newTestHandler()
+
+	rw := serve(h, http.MethodPut,
+		`{"filters":{"main":"debug"},"registries":{"components":"db=error"}}`)
+	// the default of the registry is kept without wildcard
+	expectResponse(t, rw, http.StatusOK,
+		`{"filters":{"main":"debug"},"registries":{"components":"db=error,*=warn"}}`)
+
+	if level := l.CurrentThreshold(); level != slog.Debug {
+		t.Errorf("expected filter threshold debug, got %s", level)
+	}
+	if level := r.Threshold("db.pool"); level != slog.Error {
+		t.Errorf("expected db threshold error, got %s", level)
+	}
+}
+
+func TestPutInvalid(t *testing.T) {
+	for _, body := range []string{
+		`{"filters":{"main":"debug","other":"info"}}`,
+		`{"filters":{"main":"debug"},"registries":{"components":"db=loud"}}`,
+		`{"filters":{"main":"debug"},"unknown":true}`,
+		`{"filters":`,
+	} {
+		h, l, _ := newTestHandler()
+
+		rw := serve(h, http.MethodPut, body)
+		if rw.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status %d, got %d", body, http.StatusBadRequest, rw.Code)
+		}
+		// nothing changes
+		if level := l.CurrentThreshold(); level != slog.Info {
+			t.Errorf("%s: threshold changed to %s", body, level)
+		}
+	}
+}
+
+func TestMethodNotAllowed(t *testing.T) {
+	h, _, _ := newTestHandler()
+
+	rw := serve(h, http.MethodPost, "{}")
+	if rw.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected status %d, got %d", http.StatusMethodNotAllowed, rw.Code)
+	}
+	if allow := rw.Header().Get("Allow"); allow != "GET, HEAD, PUT" {
+		t.Errorf("unexpected Allow %q", allow)
+	}
+}
+
+func TestRemove(t *testing.T) {
+	h, _, _ := newTestHandler()
+	h.AddFilter("main", nil)
+	h.AddRegistry("components", nil)
+
+	s := h.Get()
+	if len(s.Filters) != 0 || len(s.Registries) != 0 {
+		t.Errorf("expected empty state, got %+v", s)
+	}
+}
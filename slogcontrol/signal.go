@@ -0,0 +1,154 @@
+package slogcontrol
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+
+	"darvaza.org/core"
+	"darvaza.org/slog"
+	"darvaza.org/slog/handlers/filter"
+)
+
+// SignalConfig describes how signals change the threshold
+// of a filter.Logger
+type SignalConfig struct {
+	// Up makes the logger more verbose, one level at a time,
+	// or toggles Debug if Toggle is set.
+	// Defaults to SIGUSR1 where available
+	Up os.Signal
+	// Down makes the logger less verbose, one level at a time,
+	// or restores the original threshold if Toggle is set.
+	// Defaults to SIGUSR2 where available
+	Down os.Signal
+
+	// Revert, if positive, restores the original threshold
+	// once it passes without further signals
+	Revert time.Duration
+
+	// Toggle makes Up switch between Debug and the original
+	// threshold, instead of stepping
+	Toggle bool
+}
+
+// SetDefaults fills the gaps in the SignalConfig
+func (cfg *SignalConfig) SetDefaults() {
+	if cfg.Up == nil {
+		cfg.Up = defaultUpSignal
+	}
+	if cfg.Down == nil {
+		cfg.Down = defaultDownSignal
+	}
+}
+
+// Validate checks the SignalConfig is usable
+func (cfg *SignalConfig) Validate() error {
+	switch {
+	case cfg.Up == nil, cfg.Down == nil:
+		return core.Wrap(core.ErrInvalid, "slogcontrol: signals not specified")
+	case cfg.Up == cfg.Down:
+		return core.Wrap(core.ErrInvalid, "slogcontrol: same signal for up and down")
+	default:
+		return nil
+	}
+}
+
+// HandleSignals changes the threshold of a filter.Logger when the
+// process receives the signals described by the SignalConfig, using
+// SetThreshold() so OnThresholdChange() observers are notified.
+// The threshold at the time of the call is the original one restored
+// by Down, on Revert, and when calling the returned stop function.
+func HandleSignals(l *filter.Logger, cfg *SignalConfig) (stop func(), err error) {
+	var c SignalConfig
+	if cfg != nil {
+		c = *cfg
+	}
+	c.SetDefaults()
+
+	if l == nil {
+		return nil, core.Wrap(core.ErrInvalid, "slogcontrol: logger not specified")
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	st := &signalToggler{
+		logger: l,
+		cfg:    c,
+		base:   l.CurrentThreshold(),
+		ch:     make(chan os.Signal, 1),
+		done:   make(chan struct{}),
+	}
+
+	signal.Notify(st.ch, c.Up, c.Down)
+	st.wg.Add(1)
+	go st.run()
+
+	var once sync.Once
+	return func() {
+		once.Do(st.stop)
+	}, nil
+}
+
+type signalToggler struct {
+	logger *filter.Logger
+	ch     chan os.Signal
+	done   chan struct{}
+	wg     sync.WaitGroup
+	cfg    SignalConfig
+	base   slog.LogLevel
+}
+
+func (st *signalToggler) run() {
+	defer st.wg.Done()
+
+	timer := time.NewTimer(0)
+	if !timer.Stop() {
+		<-timer.C
+	}
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-st.done:
+			return
+		case <-timer.C:
+			st.logger.SetThreshold(st.base)
+		case sig := <-st.ch:
+			level := st.next(sig)
+			st.logger.SetThreshold(level)
+
+			timer.Stop()
+			if st.cfg.Revert > 0 && level != st.base {
+				timer.Reset(st.cfg.Revert)
+			}
+		}
+	}
+}
+
+// next computes the threshold after a signal
+func (st *signalToggler) next(sig os.Signal) slog.LogLevel {
+	current := st.logger.CurrentThreshold()
+
+	switch {
+	case st.cfg.Toggle && sig == st.cfg.Up && current != slog.Debug:
+		return slog.Debug
+	case st.cfg.Toggle:
+		return st.base
+	case sig == st.cfg.Up && current < slog.Debug:
+		return current + 1
+	case sig == st.cfg.Down && current > slog.Panic:
+		return current - 1
+	default:
+		return current
+	}
+}
+
+func (st *signalToggler) stop() {
+	signal.Stop(st.ch)
+	close(st.done)
+	st.wg.Wait()
+
+	st.logger.SetThreshold(st.base)
+}
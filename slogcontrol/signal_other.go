@@ -0,0 +1,11 @@
+//go:build !unix
+
+package slogcontrol
+
+import "os"
+
+// no SIGUSR1 and SIGUSR2, signals need to be specified
+var (
+	defaultUpSignal   os.Signal
+	defaultDownSignal os.Signal
+)
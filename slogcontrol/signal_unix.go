@@ -0,0 +1,10 @@
+//go:build unix
+
+package slogcontrol
+
+import "syscall"
+
+var (
+	defaultUpSignal   = syscall.SIGUSR1
+	defaultDownSignal = syscall.SIGUSR2
+)
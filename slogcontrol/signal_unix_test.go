@@ -0,0 +1,101 @@
+//go:build unix
+
+package slogcontrol
+
+import (
+	"syscall"
+	"testing"
+	"time"
+
+	"darvaza.org/slog"
+	"darvaza.org/slog/handlers/filter"
+)
+
+// waitThreshold waits for the threshold to change after a signal
+func waitThreshold(t *testing.T, l *filter.Logger, want slog.LogLevel) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for l.CurrentThreshold() != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected threshold %s, got %s", want, l.CurrentThreshold())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func raise(t *testing.T, sig syscall.Signal) {
+	t.Helper()
+
+	if err := syscall.Kill(syscall.Getpid(), sig); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHandleSignals(t *testing.T) {
+	l := newFilter(slog.Info)
+
+	stop, err := HandleSignals(l, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	raise(t, syscall.SIGUSR1)
+	waitThreshold(t, l, slog.Debug)
+	raise(t, syscall.SIGUSR2)
+	waitThreshold(t, l, slog.Info)
+	raise(t, syscall.SIGUSR2)
+	waitThreshold(t, l, slog.Warn)
+
+	// restored when stopped
+	stop()
+	if level := l.CurrentThreshold(); level != slog.Info {
+		t.Errorf("expected threshold info, got %s", level)
+	}
+}
+
+func TestHandleSignalsToggle(t *testing.T) {
+	l := newFilter(slog.Warn)
+
+	changes := make(chan slog.LogLevel, 2)
+	defer l.OnThresholdChange(func(_, level slog.LogLevel) {
+		changes <- level
+	})()
+
+	stop, err := HandleSignals(l, &SignalConfig{
+		Toggle: true,
+		Revert: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+
+	raise(t, syscall.SIGUSR1)
+	// reverted without further signals
+	for _, want := range []slog.LogLevel{slog.Debug, slog.Warn} {
+		select {
+		case level := <-changes:
+			if level != want {
+				t.Fatalf("expected threshold %s, got %s", want, level)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("expected threshold %s", want)
+		}
+	}
+}
+
+func TestHandleSignalsInvalid(t *testing.T) {
+	if _, err := HandleSignals(nil, nil); err == nil {
+		t.Error("expected error without logger")
+	}
+
+	l := newFilter(slog.Info)
+	_, err := HandleSignals(l, &SignalConfig{
+		Up:   syscall.SIGUSR1,
+		Down: syscall.SIGUSR1,
+	})
+	if err == nil {
+		t.Error("expected error using the same signal")
+	}
+}
@@ -0,0 +1,87 @@
+package slogctx
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"darvaza.org/slog"
+)
+
+var (
+	_ http.Handler = (*Handler)(nil)
+)
+
+// DefaultRequestIDHeader is the header used to get the request ID
+// when none is specified
+const DefaultRequestIDHeader = "X-Request-Id"
+
+// Handler is an http.Handler middleware storing a request-scoped
+// logger on the context of each request, to be retrieved by the
+// next handler using FromContext()
+type Handler struct {
+	// Logger is the parent of the request-scoped loggers
+	Logger slog.Logger
+	// Next is the handler doing the actual work
+	Next http.Handler
+
+	// User, if set, returns the authenticated user of the request
+	User func(*http.Request) string
+
+	// NewRequestID, if set, generates IDs for requests without one.
+	// Use NewRequestID for random ones.
+	NewRequestID func() string
+
+	// RequestIDHeader is the header containing the request ID.
+	// Defaults to DefaultRequestIDHeader
+	RequestIDHeader string
+}
+
+// NewHandler creates a Handler storing children of the given
+// logger on the context of the requests passed to next
+func NewHandler(l slog.Logger, next http.Handler) *Handler {
+	return &Handler{
+		Logger: l,
+		Next:   next,
+	}
+}
+
+func (h *Handler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if h.Logger != nil {
+		ctx, _ := New(req.Context(), h.Logger, h.request(req))
+		req = req.WithContext(ctx)
+	}
+
+	if h.Next != nil {
+		h.Next.ServeHTTP(rw, req)
+	} else {
+		http.NotFound(rw, req)
+	}
+}
+
+func (h *Handler) request(req *http.Request) Request {
+	header := h.RequestIDHeader
+	if header == "" {
+		header = DefaultRequestIDHeader
+	}
+
+	r := Request{
+		ID:         req.Header.Get(header),
+		RemoteAddr: req.RemoteAddr,
+	}
+
+	if r.ID == "" && h.NewRequestID != nil {
+		r.ID = h.NewRequestID()
+	}
+	if h.User != nil {
+		r.User = h.User(req)
+	}
+	return r
+}
+
+// NewRequestID returns a random 128-bit ID as lowercase hex
+func NewRequestID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,70 @@
+// Package slogctx builds request-scoped loggers and carries
+// them on a context.Context
+package slogctx
+
+import (
+	"context"
+
+	"darvaza.org/slog"
+)
+
+// Field labels used for the standard request fields
+const (
+	// RequestIDFieldName is the field label for the request ID
+	RequestIDFieldName = "request_id"
+	// UserFieldName is the field label for the authenticated user
+	UserFieldName = "user"
+	// RemoteAddrFieldName is the field label for the address of the client
+	RemoteAddrFieldName = "remote_addr"
+)
+
+// Request holds the standard fields of a request-scoped logger
+type Request struct {
+	ID         string
+	User       string
+	RemoteAddr string
+}
+
+// Fields returns the non-empty standard fields, or nil if
+// there are none
+func (r Request) Fields() slog.Fields {
+	var fields slog.Fields
+
+	for _, f := range []slog.Field{
+		{Key: RequestIDFieldName, Value: r.ID},
+		{Key: UserFieldName, Value: r.User},
+		{Key: RemoteAddrFieldName, Value: r.RemoteAddr},
+	} {
+		if f.Value != "" {
+			if fields == nil {
+				fields = make(slog.Fields, 3)
+			}
+			fields[f.Key] = f.Value
+		}
+	}
+	return fields
+}
+
+// New derives a child of the given logger with the standard
+// fields of the request, and the trace of the context if any,
+// and returns it alongside a context carrying it.
+// Each call creates its own child, so fields never leak
+// between requests.
+func New(ctx context.Context, parent slog.Logger, r Request) (context.Context, slog.Logger) {
+	l := parent
+	if fields := r.Fields(); len(fields) > 0 {
+		l = l.WithFields(fields)
+	}
+	l = slog.WithTrace(ctx, l)
+
+	return slog.WithLogger(ctx, l), l
+}
+
+// FromContext returns the logger carried by the context,
+// or the fallback if there is none
+func FromContext(ctx context.Context, fallback slog.Logger) slog.Logger {
+	if l, ok := slog.GetLogger(ctx); ok && l != nil {
+		return l
+	}
+	return fallback
+}
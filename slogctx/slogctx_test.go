@@ -0,0 +1,84 @@
+package slogctx
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	slogtest "darvaza.org/slog/internal/testing"
+)
+
+const concurrentRequests = 32
+
+func TestNewIsolation(t *testing.T) {
+	var wg sync.WaitGroup
+
+	rec := slogtest.NewRecorder()
+	base := rec.WithField("service", "test")
+
+	for i := 0; i < concurrentRequests; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			ctx, _ := New(context.Background(), base, Request{
+				ID:   fmt.Sprint(i),
+				User: fmt.Sprint("user", i),
+			})
+			FromContext(ctx, nil).Info().Print(i)
+		}(i)
+	}
+	wg.Wait()
+
+	checkIsolation(t, rec, "user")
+}
+
+func TestHandlerIsolation(t *testing.T) {
+	var wg sync.WaitGroup
+
+	rec := slogtest.NewRecorder()
+	h := NewHandler(rec, http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) {
+		l := FromContext(req.Context(), nil)
+		l.Info().Print(req.Header.Get(DefaultRequestIDHeader))
+	}))
+
+	for i := 0; i < concurrentRequests; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(DefaultRequestIDHeader, fmt.Sprint(i))
+			h.ServeHTTP(httptest.NewRecorder(), req)
+		}(i)
+	}
+	wg.Wait()
+
+	checkIsolation(t, rec, "")
+}
+
+// checkIsolation verifies every entry carries the fields of
+// the request that logged it, which logged its ID as message
+func checkIsolation(t *testing.T, rec *slogtest.Recorder, userPrefix string) {
+	t.Helper()
+
+	msgs := rec.Messages()
+	if len(msgs) != concurrentRequests {
+		t.Fatalf("expected %d entries, got %d", concurrentRequests, len(msgs))
+	}
+
+	for _, m := range msgs {
+		if id := m.Fields[RequestIDFieldName]; id != m.Message {
+			t.Errorf("entry %q carries request_id %v", m.Message, id)
+		}
+		if userPrefix == "" {
+			continue
+		}
+		if user := m.Fields[UserFieldName]; user != userPrefix+m.Message {
+			t.Errorf("entry %q carries user %v", m.Message, user)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package slog
+
+import (
+	"strconv"
+	"strings"
+
+	"darvaza.org/core"
+)
+
+// Frame is the structured representation of a call stack frame,
+// used by handlers to render call stacks consistently
+type Frame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line,omitempty"`
+}
+
+// String renders the frame as the full function name followed
+// by the file and line of the source code
+func (f Frame) String() string {
+	if f.Line > 0 {
+		return f.Function + " " + f.File + ":" + strconv.Itoa(f.Line)
+	}
+	return f.Function + " " + f.File
+}
+
+// NewFrame converts a core.Frame into a Frame
+func NewFrame(f core.Frame) Frame {
+	return Frame{
+		Function: f.Name(),
+		File:     f.File(),
+		Line:     f.Line(),
+	}
+}
+
+// StackFrames converts a call stack into its structured representation,
+// or nil if empty
+func StackFrames(st core.Stack) []Frame {
+	if len(st) == 0 {
+		return nil
+	}
+
+	out := make([]Frame, len(st))
+	for i, f := range st {
+		out[i] = NewFrame(f)
+	}
+	return out
+}
+
+// FormatStack renders a call stack as text, one frame per line
+// as rendered by Frame.String()
+func FormatStack(st core.Stack) string {
+	var buf strings.Builder
+
+	for i, f := range st {
+		if i > 0 {
+			buf.WriteByte('\n')
+		}
+		buf.WriteString(NewFrame(f).String())
+	}
+	return buf.String()
+}
+
+// StackLogger is implemented by loggers that can attach a call
+// stack captured elsewhere, e.g. trimmed by a filter
+type StackLogger interface {
+	// WithCallStack attaches the given call stack
+	WithCallStack(st core.Stack) Logger
+}
+
+// WithCallStack attaches a given call stack to a log context. It uses
+// the logger's own WithCallStack() if implemented, or a StackFieldName
+// field as rendered by FormatStack() otherwise.
+func WithCallStack(l Logger, st core.Stack) Logger {
+	if sl, ok := l.(StackLogger); ok {
+		return sl.WithCallStack(st)
+	}
+
+	if l.Enabled() && len(st) > 0 {
+		return l.WithField(StackFieldName, FormatStack(st))
+	}
+	return l
+}
+
+// TrimStack removes from a call stack the frames of functions whose
+// full name starts with any of the given prefixes, e.g. "runtime.",
+// and then keeps at most maxDepth frames, if positive.
+// The given call stack isn't modified.
+func TrimStack(st core.Stack, maxDepth int, prefixes ...string) core.Stack {
+	out := make(core.Stack, 0, len(st))
+	for _, f := range st {
+		if !hasAnyPrefix(f.Name(), prefixes) {
+			out = append(out, f)
+		}
+	}
+
+	if maxDepth > 0 && len(out) > maxDepth {
+		out = out[:maxDepth]
+	}
+	return out
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,141 @@
+package slog
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// Keys recognised on structured lines by a Writer
+var (
+	structuredLevelKeys   = []string{"level", "lvl", "severity"}
+	structuredMessageKeys = []string{"msg", "message"}
+	structuredTimeKeys    = []string{"time", "ts", "timestamp"}
+)
+
+// writeStructured logs a JSON or logfmt line, if it is one
+// and parsing it is enabled
+func (w *Writer) writeStructured(line string) bool {
+	var fields Fields
+	var ok bool
+
+	if w.opts.ParseJSON {
+		fields, ok = parseJSONLine(line)
+	}
+	if !ok && w.opts.ParseLogfmt {
+		fields, ok = parseLogfmtLine(line)
+	}
+	if !ok {
+		return false
+	}
+
+	level := w.opts.Level
+	if s, ok := takeString(fields, structuredLevelKeys); ok {
+		if l, ok := levelPrefixes[strings.ToUpper(s)]; ok {
+			level = l
+		}
+	}
+	msg, _ := takeString(fields, structuredMessageKeys)
+
+	l := w.l.WithLevel(level)
+	if s, ok := takeString(fields, structuredTimeKeys); ok {
+		if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+			l = WithTime(l, t)
+		} else {
+			// not ours to interpret
+			fields[structuredTimeKeys[0]] = s
+		}
+	}
+	if len(fields) > 0 {
+		l = l.WithFields(fields)
+	}
+	l.Print(msg)
+	return true
+}
+
+// takeString removes and returns the first of the keys
+// holding a string
+func takeString(fields Fields, keys []string) (string, bool) {
+	for _, k := range keys {
+		if s, ok := fields[k].(string); ok {
+			delete(fields, k)
+			return s, true
+		}
+	}
+	return "", false
+}
+
+// parseJSONLine decodes a line holding a JSON object
+func parseJSONLine(line string) (Fields, bool) {
+	s := strings.TrimSpace(line)
+	if !strings.HasPrefix(s, "{") || !strings.HasSuffix(s, "}") {
+		return nil, false
+	}
+
+	var fields Fields
+	if err := json.Unmarshal([]byte(s), &fields); err != nil {
+		return nil, false
+	}
+	return fields, true
+}
+
+// parseLogfmtLine decodes a line of space separated key=value
+// pairs, with optionally quoted values. Lines with less than two
+// pairs, or any word that isn't one, aren't considered logfmt.
+func parseLogfmtLine(line string) (Fields, bool) {
+	fields := make(Fields)
+
+	s := strings.TrimSpace(line)
+	for s != "" {
+		key, rest, ok := strings.Cut(s, "=")
+		if !ok || key == "" || strings.ContainsAny(key, " \t\"") {
+			return nil, false
+		}
+
+		value, rest, ok := cutLogfmtValue(rest)
+		if !ok {
+			return nil, false
+		}
+
+		fields[key] = value
+		s = strings.TrimLeft(rest, " \t")
+	}
+
+	if len(fields) < 2 {
+		return nil, false
+	}
+	return fields, true
+}
+
+// cutLogfmtValue splits the value at the beginning of s
+// from the rest of the line
+func cutLogfmtValue(s string) (value, rest string, ok bool) {
+	if !strings.HasPrefix(s, `"`) {
+		i := strings.IndexAny(s, " \t")
+		if i < 0 {
+			return s, "", true
+		}
+		return s[:i], s[i:], true
+	}
+
+	// quoted, find the closing quote
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			v, err := unquoteLogfmt(s[:i+1])
+			if err != nil {
+				return "", "", false
+			}
+			return v, s[i+1:], true
+		}
+	}
+	return "", "", false
+}
+
+func unquoteLogfmt(s string) (string, error) {
+	var v string
+	err := json.Unmarshal([]byte(s), &v)
+	return v, err
+}
@@ -0,0 +1,26 @@
+package slog
+
+import "time"
+
+// TimedLogger is implemented by loggers that can record
+// when the event happened, instead of when the entry is
+// written
+type TimedLogger interface {
+	// WithTime records the time of the event
+	WithTime(t time.Time) Logger
+}
+
+// WithTime records when the event happened on a log context,
+// so asynchronous handlers don't report the time the entry was
+// delivered instead. It uses the logger's own WithTime() if
+// implemented, or a TimeFieldName field otherwise.
+func WithTime(l Logger, t time.Time) Logger {
+	if tl, ok := l.(TimedLogger); ok {
+		return tl.WithTime(t)
+	}
+
+	if l.Enabled() {
+		return l.WithField(TimeFieldName, t)
+	}
+	return l
+}
@@ -0,0 +1,152 @@
+package slog
+
+import (
+	"context"
+	"encoding/hex"
+	"sync"
+
+	"darvaza.org/core"
+)
+
+const (
+	// TraceIDFieldName is the preferred field label for the
+	// trace ID, as lowercase hex
+	TraceIDFieldName = "trace_id"
+	// SpanIDFieldName is the preferred field label for the
+	// span ID, as lowercase hex
+	SpanIDFieldName = "span_id"
+
+	// TraceParentHeader is the W3C Trace Context header
+	TraceParentHeader = "traceparent"
+)
+
+// TraceID identifies a trace, as defined by W3C Trace Context
+// and OpenTelemetry
+type TraceID [16]byte
+
+// String renders the TraceID as lowercase hex
+func (id TraceID) String() string { return hex.EncodeToString(id[:]) }
+
+// IsValid tells if the TraceID isn't all zeros
+func (id TraceID) IsValid() bool { return id != TraceID{} }
+
+// SpanID identifies a span, as defined by W3C Trace Context
+// and OpenTelemetry
+type SpanID [8]byte
+
+// String renders the SpanID as lowercase hex
+func (id SpanID) String() string { return hex.EncodeToString(id[:]) }
+
+// IsValid tells if the SpanID isn't all zeros
+func (id SpanID) IsValid() bool { return id != SpanID{} }
+
+// TraceContext identifies the span a log entry belongs to
+type TraceContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	Flags   byte
+}
+
+// IsValid tells if both TraceID and SpanID are valid
+func (tc TraceContext) IsValid() bool {
+	return tc.TraceID.IsValid() && tc.SpanID.IsValid()
+}
+
+// TraceParent renders the TraceContext as the value of
+// a W3C traceparent header
+func (tc TraceContext) TraceParent() string {
+	return "00-" + tc.TraceID.String() + "-" + tc.SpanID.String() + "-" +
+		hex.EncodeToString([]byte{tc.Flags})
+}
+
+// ParseTraceParent parses the value of a W3C traceparent header
+func ParseTraceParent(s string) (TraceContext, error) {
+	var tc TraceContext
+
+	// version-traceid-spanid-flags
+	if len(s) < 55 || s[2] != '-' || s[35] != '-' || s[52] != '-' ||
+		(len(s) > 55 && s[55] != '-') || s[:2] == "ff" {
+		return tc, core.Wrapf(core.ErrInvalid, "invalid traceparent %q", s)
+	}
+
+	var flags [1]byte
+	if !decodeHex(tc.TraceID[:], s[3:35]) ||
+		!decodeHex(tc.SpanID[:], s[36:52]) ||
+		!decodeHex(flags[:], s[53:55]) ||
+		!tc.IsValid() {
+		return TraceContext{}, core.Wrapf(core.ErrInvalid, "invalid traceparent %q", s)
+	}
+
+	tc.Flags = flags[0]
+	return tc, nil
+}
+
+// decodeHex decodes lowercase hex into dst
+func decodeHex(dst []byte, s string) bool {
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+
+	_, err := hex.Decode(dst, []byte(s))
+	return err == nil
+}
+
+// TraceExtractor obtains the TraceContext of a context.Context,
+// e.g. from an OpenTelemetry span
+type TraceExtractor func(context.Context) (TraceContext, bool)
+
+var (
+	traceExtractorsMu sync.RWMutex
+	traceExtractors   []TraceExtractor
+)
+
+// RegisterTraceExtractor adds a TraceExtractor consulted by
+// GetTraceContext() when the context doesn't carry a TraceContext
+// attached by WithTraceContext()
+func RegisterTraceExtractor(fn TraceExtractor) {
+	if fn != nil {
+		traceExtractorsMu.Lock()
+		defer traceExtractorsMu.Unlock()
+
+		traceExtractors = append(traceExtractors, fn)
+	}
+}
+
+// WithTraceContext attaches a TraceContext to the given context
+func WithTraceContext(ctx context.Context, tc TraceContext) context.Context {
+	return ctxTraceKey.WithValue(ctx, tc)
+}
+
+// GetTraceContext returns the TraceContext attached to the given
+// context, or provided by the registered extractors
+func GetTraceContext(ctx context.Context) (TraceContext, bool) {
+	if tc, ok := ctxTraceKey.Get(ctx); ok && tc.IsValid() {
+		return tc, true
+	}
+
+	traceExtractorsMu.RLock()
+	defer traceExtractorsMu.RUnlock()
+
+	for _, fn := range traceExtractors {
+		if tc, ok := fn(ctx); ok && tc.IsValid() {
+			return tc, true
+		}
+	}
+	return TraceContext{}, false
+}
+
+// WithTrace attaches the TraceIDFieldName and SpanIDFieldName
+// fields of the TraceContext of the given context, if any
+func WithTrace(ctx context.Context, l Logger) Logger {
+	if tc, ok := GetTraceContext(ctx); ok && l.Enabled() {
+		return l.WithFields(map[string]any{
+			TraceIDFieldName: tc.TraceID.String(),
+			SpanIDFieldName:  tc.SpanID.String(),
+		})
+	}
+	return l
+}
+
+var ctxTraceKey = core.NewContextKey[TraceContext]("trace")
@@ -0,0 +1,104 @@
+package slog_test
+
+import (
+	"context"
+	"testing"
+
+	"darvaza.org/slog"
+	slogtest "darvaza.org/slog/internal/testing"
+)
+
+const testTraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func TestParseTraceParent(t *testing.T) {
+	tc, err := slog.ParseTraceParent(testTraceParent)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s := tc.TraceID.String(); s != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("unexpected TraceID %q", s)
+	}
+	if s := tc.SpanID.String(); s != "00f067aa0ba902b7" {
+		t.Errorf("unexpected SpanID %q", s)
+	}
+	if tc.Flags != 1 {
+		t.Errorf("unexpected flags %#x", tc.Flags)
+	}
+	if s := tc.TraceParent(); s != testTraceParent {
+		t.Errorf("expected %q, got %q", testTraceParent, s)
+	}
+
+	// later versions may append fields
+	if _, err := slog.ParseTraceParent(testTraceParent + "-extra"); err != nil {
+		t.Errorf("future version: %v", err)
+	}
+}
+
+func TestParseTraceParentInvalid(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-0x",
+		"00_4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		testTraceParent + "extra",
+	} {
+		if tc, err := slog.ParseTraceParent(s); err == nil {
+			t.Errorf("%q: expected error, got %+v", s, tc)
+		}
+	}
+}
+
+type testTraceKey struct{}
+
+func TestGetTraceContext(t *testing.T) {
+	tc, _ := slog.ParseTraceParent(testTraceParent)
+
+	if _, ok := slog.GetTraceContext(context.Background()); ok {
+		t.Error("unexpected TraceContext")
+	}
+
+	ctx := slog.WithTraceContext(context.Background(), tc)
+	if got, ok := slog.GetTraceContext(ctx); !ok || got != tc {
+		t.Errorf("expected %+v, got %+v", tc, got)
+	}
+
+	slog.RegisterTraceExtractor(func(ctx context.Context) (slog.TraceContext, bool) {
+		tc, ok := ctx.Value(testTraceKey{}).(slog.TraceContext)
+		return tc, ok
+	})
+
+	other := tc
+	other.SpanID[0] = 0xff
+	ctx = context.WithValue(context.Background(), testTraceKey{}, other)
+	if got, ok := slog.GetTraceContext(ctx); !ok || got != other {
+		t.Errorf("extractor: expected %+v, got %+v", other, got)
+	}
+
+	// attached beats extracted
+	ctx = slog.WithTraceContext(ctx, tc)
+	if got, _ := slog.GetTraceContext(ctx); got != tc {
+		t.Errorf("expected %+v, got %+v", tc, got)
+	}
+}
+
+func TestWithTrace(t *testing.T) {
+	tc, _ := slog.ParseTraceParent(testTraceParent)
+	ctx := slog.WithTraceContext(context.Background(), tc)
+
+	rec := slogtest.NewRecorder()
+	slog.WithTrace(ctx, rec.Info()).Print("traced")
+	slog.WithTrace(context.Background(), rec.Info()).Print("untraced")
+
+	slogtest.AssertMessagesInOrder(t, rec.Messages(),
+		slogtest.Expect().Message("traced").
+			Field(slog.TraceIDFieldName, tc.TraceID.String()).
+			Field(slog.SpanIDFieldName, tc.SpanID.String()),
+		slogtest.Expect().Message("untraced").
+			NoField(slog.TraceIDFieldName).
+			NoField(slog.SpanIDFieldName))
+}